@@ -4,6 +4,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/f4ah6o/jww-parser/dxf"
@@ -14,6 +15,8 @@ func main() {
 	outputDxf := flag.Bool("dxf", false, "Output DXF format")
 	outputFile := flag.String("o", "", "Output file (default: stdout)")
 	verbose := flag.Bool("v", false, "Verbose output")
+	logConvert := flag.Bool("log-convert", false, "Log per-entity conversion decisions to stderr")
+	listLayers := flag.Bool("layers", false, "List named or used layer groups and layers with entity counts")
 	flag.Parse()
 
 	if flag.NArg() < 1 {
@@ -46,6 +49,14 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  Paper Size: %d\n", doc.PaperSize)
 		fmt.Fprintf(os.Stderr, "  Entities: %d\n", len(doc.Entities))
 		fmt.Fprintf(os.Stderr, "  Blocks: %d\n", len(doc.BlockDefs))
+		for _, w := range doc.Warnings {
+			fmt.Fprintf(os.Stderr, "  Warning: %s\n", w)
+		}
+	}
+
+	if *listLayers {
+		printLayers(os.Stdout, doc)
+		return
 	}
 
 	// Auto-enable DXF output if -o flag is specified
@@ -55,7 +66,11 @@ func main() {
 
 	if *outputDxf {
 		// Convert to DXF
-		dxfDoc := dxf.ConvertDocument(doc)
+		var opts dxf.ConvertOptions
+		if *logConvert {
+			opts.Logger = os.Stderr
+		}
+		dxfDoc := dxf.ConvertDocumentWithOptions(doc, opts)
 		dxfStr := dxf.ToString(dxfDoc)
 
 		// Output
@@ -80,3 +95,45 @@ func main() {
 		fmt.Printf("  Blocks: %d\n", len(doc.BlockDefs))
 	}
 }
+
+// layerKey identifies a layer by its group and layer number.
+type layerKey struct {
+	group, layer uint16
+}
+
+// tallyLayers counts the entities of doc.Entities on each layer.
+func tallyLayers(doc *jww.Document) map[layerKey]int {
+	counts := make(map[layerKey]int)
+	for _, e := range doc.Entities {
+		base := e.Base()
+		counts[layerKey{base.LayerGroup, base.Layer}]++
+	}
+	return counts
+}
+
+// printLayers writes every layer group and layer that has a name or
+// entities, with the number of entities on it.
+func printLayers(w io.Writer, doc *jww.Document) {
+	counts := tallyLayers(doc)
+	for g := range doc.LayerGroups {
+		lg := &doc.LayerGroups[g]
+		groupTotal, named := 0, lg.Name != ""
+		for l := range lg.Layers {
+			groupTotal += counts[layerKey{uint16(g), uint16(l)}]
+			named = named || lg.Layers[l].Name != ""
+		}
+		if !named && groupTotal == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "Group %X %q: %d entities\n", g, lg.Name, groupTotal)
+		for l := range lg.Layers {
+			name := lg.Layers[l].Name
+			n := counts[layerKey{uint16(g), uint16(l)}]
+			if name == "" && n == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "  Layer %X-%X %q: %d entities\n", g, l, name, n)
+		}
+	}
+}
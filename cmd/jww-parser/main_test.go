@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestTallyLayers(t *testing.T) {
+	doc := &jww.Document{Entities: []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{LayerGroup: 0, Layer: 1}},
+		&jww.Line{EntityBase: jww.EntityBase{LayerGroup: 0, Layer: 1}},
+		&jww.Point{EntityBase: jww.EntityBase{LayerGroup: 2, Layer: 15}},
+	}}
+
+	counts := tallyLayers(doc)
+	if counts[layerKey{0, 1}] != 2 || counts[layerKey{2, 15}] != 1 || len(counts) != 2 {
+		t.Errorf("got %v", counts)
+	}
+
+	doc.LayerGroups[3].Layers[4].Name = "通り芯"
+	var buf bytes.Buffer
+	printLayers(&buf, doc)
+	out := buf.String()
+	for _, want := range []string{
+		`Group 0 "": 2 entities`,
+		`  Layer 0-1 "": 2 entities`,
+		`  Layer 2-F "": 1 entities`,
+		`  Layer 3-4 "通り芯": 0 entities`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Layer 0-0") {
+		t.Errorf("empty unnamed layer listed:\n%s", out)
+	}
+}
@@ -3,8 +3,11 @@ package main
 
 import (
 	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -20,6 +23,9 @@ import (
 
 // Command line flags
 var odaFlag = flag.Bool("oda", false, "Run ODA FileConverter check (disabled by default)")
+var pruneLayersFlag = flag.Bool("prune-layers", false, "Drop layers no entity uses from the converted DXF")
+var formatFlag = flag.String("format", "markdown", "Output format: markdown, csv, or json")
+var failOnFlag = flag.String("fail-on", "", "Exit with status 1 if any file matches a criterion, e.g. \"parse-errors>0,dxf-diff!=0\"")
 
 type FileStats struct {
 	Name      string
@@ -29,6 +35,7 @@ type FileStats struct {
 	Points    int
 	Texts     int
 	Solids    int
+	Dims      int
 	Blocks    int
 	BlockDefs int
 	Unknown   []string
@@ -65,10 +72,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	switch *formatFlag {
+	case "markdown", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format %q (want markdown, csv, or json)\n", *formatFlag)
+		os.Exit(1)
+	}
+
+	criteria, err := parseCriteria(*failOnFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -fail-on: %v\n", err)
+		os.Exit(1)
+	}
+
 	dir := flag.Arg(0)
 	var files []string
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -98,6 +118,21 @@ func main() {
 
 	wg.Wait()
 
+	// Machine-readable output replaces the Markdown tables
+	switch *formatFlag {
+	case "csv":
+		err = writeCSV(os.Stdout, allStats)
+	case "json":
+		err = writeJSON(os.Stdout, allStats)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *formatFlag, err)
+		os.Exit(1)
+	}
+	if *formatFlag != "markdown" {
+		os.Exit(runGate(os.Stderr, allStats, criteria))
+	}
+
 	// Build Test Data Matrix rows
 	var testDataRows [][]string
 	for _, s := range allStats {
@@ -113,6 +148,7 @@ func main() {
 			fmt.Sprintf("%d", s.Points),
 			fmt.Sprintf("%d", s.Texts),
 			fmt.Sprintf("%d", s.Solids),
+			fmt.Sprintf("%d", s.Dims),
 			fmt.Sprintf("%d", s.Blocks),
 			fmt.Sprintf("%d", s.BlockDefs),
 			errStr,
@@ -121,7 +157,7 @@ func main() {
 
 	fmt.Println("## Test Data Matrix")
 	fmt.Println()
-	printTable([]string{"File", "Version", "Line", "Arc", "Point", "Text", "Solid", "Block", "BlockDef", "Error"}, testDataRows)
+	printTable([]string{"File", "Version", "Line", "Arc", "Point", "Text", "Solid", "Dimension", "Block", "BlockDef", "Error"}, testDataRows)
 
 	// Build DXF Conversion Results rows
 	var dxfRows [][]string
@@ -132,7 +168,7 @@ func main() {
 		} else if s.Error != "" {
 			status = "⏭️ Parse failed"
 		}
-		jwwTotal := s.Lines + s.Arcs + s.Points + s.Texts + s.Solids + s.Blocks
+		jwwTotal := jwwEntities(s)
 		diff := s.DXFEntities - jwwTotal
 		diffStr := fmt.Sprintf("%+d", diff)
 		if diff == 0 {
@@ -259,6 +295,107 @@ func main() {
 	if *odaFlag {
 		fmt.Printf("- ODA FileConverter passed (0 errors): %d\n", odaPassFiles)
 	}
+
+	os.Exit(runGate(os.Stderr, allStats, criteria))
+}
+
+// jwwEntities returns the number of entities counted in the JWW file.
+func jwwEntities(s FileStats) int {
+	return s.Lines + s.Arcs + s.Points + s.Texts + s.Solids + s.Dims + s.Blocks
+}
+
+// gateMetrics maps the metric names usable in -fail-on to their per-file values.
+var gateMetrics = map[string]func(FileStats) int{
+	"parse-errors": func(s FileStats) int { return boolToInt(s.Error != "") },
+	"dxf-errors":   func(s FileStats) int { return boolToInt(s.DXFError != "") },
+	"dxf-diff":     func(s FileStats) int { return s.DXFEntities - jwwEntities(s) },
+	"unknown":      func(s FileStats) int { return len(s.Unknown) },
+	"ezdxf-errors": func(s FileStats) int { return s.EzdxfErrors },
+	"ezdxf-fixes":  func(s FileStats) int { return s.EzdxfFixes },
+	"oda-errors":   func(s FileStats) int { return s.ODAErrors },
+	"oda-warnings": func(s FileStats) int { return s.ODAWarnings },
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// criterion is one -fail-on condition such as "dxf-diff!=0".
+type criterion struct {
+	metric string
+	op     string
+	value  int
+}
+
+var criterionRe = regexp.MustCompile(`^([a-z-]+)\s*(>=|<=|==|!=|>|<)\s*(-?\d+)$`)
+
+// parseCriteria parses a comma-separated list of criteria. An empty string
+// yields no criteria.
+func parseCriteria(spec string) ([]criterion, error) {
+	var criteria []criterion
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		m := criterionRe.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("malformed criterion %q (want <metric><op><integer>)", part)
+		}
+		if _, ok := gateMetrics[m[1]]; !ok {
+			return nil, fmt.Errorf("unknown metric %q in %q", m[1], part)
+		}
+		var c criterion
+		c.metric, c.op = m[1], m[2]
+		fmt.Sscanf(m[3], "%d", &c.value)
+		criteria = append(criteria, c)
+	}
+	return criteria, nil
+}
+
+// matches reports whether s meets the criterion, i.e. violates the gate.
+func (c criterion) matches(s FileStats) bool {
+	v := gateMetrics[c.metric](s)
+	switch c.op {
+	case ">":
+		return v > c.value
+	case ">=":
+		return v >= c.value
+	case "<":
+		return v < c.value
+	case "<=":
+		return v <= c.value
+	case "==":
+		return v == c.value
+	case "!=":
+		return v != c.value
+	}
+	return false
+}
+
+func (c criterion) String() string {
+	return fmt.Sprintf("%s%s%d", c.metric, c.op, c.value)
+}
+
+// runGate reports each file matching a criterion to w and returns the exit
+// status: 1 if any file matched, 0 otherwise.
+func runGate(w io.Writer, stats []FileStats, criteria []criterion) int {
+	failed := false
+	for _, s := range stats {
+		for _, c := range criteria {
+			if c.matches(s) {
+				fmt.Fprintf(w, "FAIL %s: %s (got %d)\n", s.Name, c, gateMetrics[c.metric](s))
+				failed = true
+			}
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
 }
 
 func parseFile(path string) FileStats {
@@ -296,6 +433,8 @@ func parseFile(path string) FileStats {
 			stats.Texts++
 		case "SOLID":
 			stats.Solids++
+		case "DIMENSION":
+			stats.Dims++
 		case "BLOCK":
 			stats.Blocks++
 		default:
@@ -304,10 +443,11 @@ func parseFile(path string) FileStats {
 	}
 
 	// Convert to DXF and collect statistics
-	dxfDoc := dxf.ConvertDocument(doc)
-	stats.DXFEntities = len(dxfDoc.Entities)
-	stats.DXFLayers = len(dxfDoc.Layers)
-	stats.DXFBlocks = len(dxfDoc.Blocks)
+	dxfDoc := dxf.ConvertDocumentWithOptions(doc, dxf.ConvertOptions{PruneUnusedLayers: *pruneLayersFlag})
+	dxfStats := dxfDoc.Statistics()
+	stats.DXFEntities = dxfStats.EntityCount
+	stats.DXFLayers = dxfStats.LayerCount
+	stats.DXFBlocks = dxfStats.BlockCount
 
 	// Write DXF to temp file and run ezdxf audit
 	tmpFile, err := os.CreateTemp("", "jww-stats-*.dxf")
@@ -505,6 +645,69 @@ func runEzdxfInfo(dxfPath string, stats *FileStats) {
 	stats.EzdxfInfoStatus = "✅"
 }
 
+// csvHeader lists the FileStats fields in the column order of csvRecord.
+var csvHeader = []string{
+	"Name", "Version", "Lines", "Arcs", "Points", "Texts", "Solids", "Dims", "Blocks", "BlockDefs",
+	"Unknown", "Error", "DXFEntities", "DXFLayers", "DXFBlocks", "DXFError",
+	"EzdxfErrors", "EzdxfFixes", "EzdxfStatus",
+	"EzdxfInfoEntities", "EzdxfInfoLayers", "EzdxfInfoBlocks", "EzdxfInfoStatus",
+	"ODAWarnings", "ODAErrors", "ODAStatus",
+}
+
+// csvRecord returns the CSV cells of s. Unknown entity types are joined with ";".
+func csvRecord(s FileStats) []string {
+	return []string{
+		s.Name,
+		fmt.Sprintf("%d", s.Version),
+		fmt.Sprintf("%d", s.Lines),
+		fmt.Sprintf("%d", s.Arcs),
+		fmt.Sprintf("%d", s.Points),
+		fmt.Sprintf("%d", s.Texts),
+		fmt.Sprintf("%d", s.Solids),
+		fmt.Sprintf("%d", s.Dims),
+		fmt.Sprintf("%d", s.Blocks),
+		fmt.Sprintf("%d", s.BlockDefs),
+		strings.Join(s.Unknown, ";"),
+		s.Error,
+		fmt.Sprintf("%d", s.DXFEntities),
+		fmt.Sprintf("%d", s.DXFLayers),
+		fmt.Sprintf("%d", s.DXFBlocks),
+		s.DXFError,
+		fmt.Sprintf("%d", s.EzdxfErrors),
+		fmt.Sprintf("%d", s.EzdxfFixes),
+		s.EzdxfStatus,
+		fmt.Sprintf("%d", s.EzdxfInfoEntities),
+		fmt.Sprintf("%d", s.EzdxfInfoLayers),
+		fmt.Sprintf("%d", s.EzdxfInfoBlocks),
+		s.EzdxfInfoStatus,
+		fmt.Sprintf("%d", s.ODAWarnings),
+		fmt.Sprintf("%d", s.ODAErrors),
+		s.ODAStatus,
+	}
+}
+
+// writeCSV writes stats as CSV with a header row and one row per file.
+func writeCSV(w io.Writer, stats []FileStats) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, s := range stats {
+		if err := cw.Write(csvRecord(s)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes stats as an indented JSON array of FileStats objects.
+func writeJSON(w io.Writer, stats []FileStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
+
 // printTable prints a markdown table with aligned columns using tabwriter.
 // headers is a slice of column header strings.
 // rows is a slice of row data, where each row is a slice of cell strings.
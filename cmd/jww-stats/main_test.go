@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testStats = []FileStats{
+	{Name: "a.jww", Version: 700, Lines: 3, Arcs: 1, Unknown: []string{"FOO", "BAR"}, DXFEntities: 4, EzdxfStatus: "✅"},
+	{Name: "b.jww", Error: "invalid, truncated file"},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, testStats); err != nil {
+		t.Fatalf("writeCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records", len(records))
+	}
+	if n := reflect.TypeOf(FileStats{}).NumField(); len(records[0]) != n {
+		t.Errorf("header has %d columns, FileStats has %d fields", len(records[0]), n)
+	}
+
+	row := make(map[string]string)
+	for i, name := range records[0] {
+		row[name] = records[1][i]
+	}
+	for field, want := range map[string]string{
+		"Name": "a.jww", "Version": "700", "Lines": "3", "Arcs": "1",
+		"Unknown": "FOO;BAR", "DXFEntities": "4", "EzdxfStatus": "✅",
+	} {
+		if row[field] != want {
+			t.Errorf("%s: got %q, want %q", field, row[field], want)
+		}
+	}
+	if got := records[2][11]; got != "invalid, truncated file" {
+		t.Errorf("Error: got %q", got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, testStats); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+
+	var got []FileStats
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decoding JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, testStats) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, testStats)
+	}
+
+	var raw []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("decoding JSON: %v", err)
+	}
+	if n := reflect.TypeOf(FileStats{}).NumField(); len(raw[0]) != n {
+		t.Errorf("object has %d keys, FileStats has %d fields", len(raw[0]), n)
+	}
+}
+
+func TestParseCriteria(t *testing.T) {
+	criteria, err := parseCriteria("parse-errors>0, dxf-diff != 0,ezdxf-errors>=-1")
+	if err != nil {
+		t.Fatalf("parseCriteria failed: %v", err)
+	}
+	want := []criterion{{"parse-errors", ">", 0}, {"dxf-diff", "!=", 0}, {"ezdxf-errors", ">=", -1}}
+	if !reflect.DeepEqual(criteria, want) {
+		t.Errorf("got %v, want %v", criteria, want)
+	}
+
+	if criteria, err := parseCriteria(""); err != nil || len(criteria) != 0 {
+		t.Errorf("empty spec: got %v, %v", criteria, err)
+	}
+	for _, spec := range []string{"parse-errors", "parse-errors>x", "bogus>0", "dxf-diff=>0"} {
+		if _, err := parseCriteria(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestRunGate(t *testing.T) {
+	tests := []struct {
+		spec  string
+		code  int
+		fails int
+	}{
+		{"", 0, 0},
+		{"parse-errors>0", 1, 1},
+		{"dxf-diff!=0", 0, 0},
+		{"unknown>=2", 1, 1},
+		{"ezdxf-errors>0,oda-errors>0", 0, 0},
+		{"parse-errors>0,unknown>1", 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			criteria, err := parseCriteria(tt.spec)
+			if err != nil {
+				t.Fatalf("parseCriteria failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if code := runGate(&buf, testStats, criteria); code != tt.code {
+				t.Errorf("exit code: got %d, want %d", code, tt.code)
+			}
+			if n := strings.Count(buf.String(), "FAIL "); n != tt.fails {
+				t.Errorf("reported %d failures, want %d:\n%s", n, tt.fails, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package dxf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrOpenBoundary is returned by RegionArea when the edges do not form a
+// closed chain.
+var ErrOpenBoundary = errors.New("dxf: boundary is not closed")
+
+// regionJoinTolerance is the largest distance between edge endpoints that
+// RegionArea still treats as connected.
+const regionJoinTolerance = 1e-6
+
+// PolygonArea returns the area of the polygon with the given corners using
+// the Shoelace formula. The polygon is closed implicitly, so the last corner
+// need not repeat the first. Self-intersecting polygons have their parts of
+// opposite winding partly cancel out.
+//
+// Example:
+//
+//	area := dxf.PolygonArea([][2]float64{{0, 0}, {10, 0}, {10, 5}, {0, 5}}) // Returns 50
+func PolygonArea(pts [][2]float64) float64 {
+	var area2 float64 // twice the signed area
+	for i, p := range pts {
+		q := pts[(i+1)%len(pts)]
+		area2 += p[0]*q[1] - q[0]*p[1]
+	}
+	return math.Abs(area2) / 2
+}
+
+// RegionArea returns the area enclosed by a boundary of lines and arcs,
+// e.g. a room outline with a curved wall. The edges must be listed in
+// boundary order, each starting where the previous one ends; lines and
+// arcs may run in either direction. Arcs are approximated by chords that
+// deviate from them by at most maxSagitta (<= 0 uses 0.1% of the radius).
+//
+// It returns ErrOpenBoundary if the edges do not connect into a closed
+// boundary, and an error for edges other than *Line and *Arc.
+//
+// Example:
+//
+//	// A 10 x 10 square whose top edge bulges out in a half circle
+//	area, err := dxf.RegionArea([]dxf.Entity{
+//		dxf.NewLine(0, 0, 10, 0),
+//		dxf.NewLine(10, 0, 10, 10),
+//		dxf.NewArc(5, 10, 5, 0, 180),
+//		dxf.NewLine(0, 10, 0, 0),
+//	}, 0.001)
+func RegionArea(edges []Entity, maxSagitta float64) (float64, error) {
+	if len(edges) == 0 {
+		return 0, nil
+	}
+
+	paths := make([][]Vertex, len(edges))
+	for i, edge := range edges {
+		switch e := edge.(type) {
+		case *Line:
+			paths[i] = []Vertex{{e.X1, e.Y1}, {e.X2, e.Y2}}
+		case *Arc:
+			paths[i] = e.Flatten(maxSagitta)
+		default:
+			return 0, fmt.Errorf("dxf: region edge %d: unsupported entity type %s", i, edge.EntityType())
+		}
+	}
+
+	// The first edge runs towards the end of the second it touches
+	if len(paths) > 1 {
+		first, second := paths[0], paths[1]
+		if !touches(first[len(first)-1], second) && touches(first[0], second) {
+			slices.Reverse(first)
+		}
+	}
+
+	ring := slices.Clone(paths[0])
+	for i, path := range paths[1:] {
+		end := ring[len(ring)-1]
+		switch {
+		case near(end, path[0]):
+		case near(end, path[len(path)-1]):
+			slices.Reverse(path)
+		default:
+			return 0, fmt.Errorf("%w: edge %d does not connect to (%g, %g)", ErrOpenBoundary, i+1, end.X, end.Y)
+		}
+		ring = append(ring, path[1:]...)
+	}
+	if start, end := ring[0], ring[len(ring)-1]; !near(start, end) {
+		return 0, fmt.Errorf("%w: ends at (%g, %g), started at (%g, %g)", ErrOpenBoundary, end.X, end.Y, start.X, start.Y)
+	}
+
+	pts := make([][2]float64, len(ring))
+	for i, v := range ring {
+		pts[i] = [2]float64{v.X, v.Y}
+	}
+	return PolygonArea(pts), nil
+}
+
+// near reports whether a and b are within regionJoinTolerance.
+func near(a, b Vertex) bool {
+	return math.Hypot(a.X-b.X, a.Y-b.Y) <= regionJoinTolerance
+}
+
+// touches reports whether v is near either end of path.
+func touches(v Vertex, path []Vertex) bool {
+	return near(v, path[0]) || near(v, path[len(path)-1])
+}
@@ -0,0 +1,98 @@
+package dxf
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPolygonArea(t *testing.T) {
+	tests := []struct {
+		name string
+		pts  [][2]float64
+		want float64
+	}{
+		{"rectangle", [][2]float64{{0, 0}, {10, 0}, {10, 5}, {0, 5}}, 50},
+		{"clockwise", [][2]float64{{0, 0}, {0, 5}, {10, 5}, {10, 0}}, 50},
+		{"closed with repeated corner", [][2]float64{{0, 0}, {4, 0}, {0, 3}, {0, 0}}, 6},
+		{"L shape", [][2]float64{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}, 3},
+		{"empty", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PolygonArea(tt.pts); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionArea(t *testing.T) {
+	// A 10 x 10 square whose top edge bulges out in a half circle of radius 5
+	halfDisk := 100 + math.Pi*25/2
+
+	tests := []struct {
+		name  string
+		edges []Entity
+		want  float64
+	}{
+		{
+			"polygon",
+			[]Entity{
+				NewLine(0, 0, 4, 0),
+				NewLine(4, 0, 4, 3),
+				NewLine(4, 3, 0, 0),
+			},
+			6,
+		},
+		{
+			"arc edge",
+			[]Entity{
+				NewLine(0, 0, 10, 0),
+				NewLine(10, 0, 10, 10),
+				NewArc(5, 10, 5, 0, 180),
+				NewLine(0, 10, 0, 0),
+			},
+			halfDisk,
+		},
+		{
+			"reversed edges",
+			[]Entity{
+				NewLine(10, 0, 0, 0),
+				NewLine(0, 10, 0, 0),
+				NewArc(5, 10, 5, 0, 180),
+				NewLine(10, 10, 10, 0),
+			},
+			halfDisk,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RegionArea(tt.edges, 1e-6)
+			if err != nil {
+				t.Fatalf("RegionArea failed: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-4 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionArea_Errors(t *testing.T) {
+	open := []Entity{NewLine(0, 0, 10, 0), NewLine(10, 0, 10, 10)}
+	if _, err := RegionArea(open, 0); !errors.Is(err, ErrOpenBoundary) {
+		t.Errorf("open boundary: got %v, want ErrOpenBoundary", err)
+	}
+
+	gap := []Entity{NewLine(0, 0, 10, 0), NewLine(10, 1, 0, 0)}
+	if _, err := RegionArea(gap, 0); !errors.Is(err, ErrOpenBoundary) {
+		t.Errorf("gap: got %v, want ErrOpenBoundary", err)
+	}
+
+	if _, err := RegionArea([]Entity{NewCircle(0, 0, 1)}, 0); err == nil {
+		t.Error("circle edge: expected an error")
+	}
+}
@@ -0,0 +1,132 @@
+package dxf
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+// ConvertResult is the outcome of converting one file in ConvertDirectory.
+type ConvertResult struct {
+	// Input is the path of the JWW file.
+	Input string
+
+	// Output is the path of the DXF file, or "" if none was written.
+	Output string
+
+	// Err is the error that stopped the conversion, or nil on success.
+	Err error
+}
+
+// ConvertDirectory converts every .jww (or .JWW) file under inDir to a .dxf
+// file in outDir, keeping the subdirectory layout, e.g. inDir/a/plan.jww
+// becomes outDir/a/plan.dxf. Files are converted concurrently by up to
+// GOMAXPROCS workers.
+//
+// It returns one result per file, sorted by input path. A file that fails
+// to convert only sets the Err of its result; the returned error reports
+// problems walking inDir.
+//
+// Example:
+//
+//	results, err := dxf.ConvertDirectory("drawings", "out", dxf.ConvertOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, r := range results {
+//		if r.Err != nil {
+//			log.Printf("%s: %v", r.Input, r.Err)
+//		}
+//	}
+func ConvertDirectory(inDir, outDir string, opts ConvertOptions) ([]ConvertResult, error) {
+	var files []string
+	err := filepath.WalkDir(inDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (filepath.Ext(path) == ".jww" || filepath.Ext(path) == ".JWW") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	results := make([]ConvertResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = convertFile(inDir, outDir, files[i], opts)
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+// convertFile converts the JWW file at path under inDir to the matching DXF
+// file under outDir.
+func convertFile(inDir, outDir, path string, opts ConvertOptions) ConvertResult {
+	result := ConvertResult{Input: path}
+
+	rel, err := filepath.Rel(inDir, path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	out := filepath.Join(outDir, strings.TrimSuffix(rel, filepath.Ext(rel))+".dxf")
+
+	f, err := os.Open(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer f.Close()
+
+	doc, err := jww.Parse(f)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	dxfDoc := ConvertDocumentWithOptions(doc, opts)
+
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		result.Err = err
+		return result
+	}
+	w, err := os.Create(out)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if err := NewWriter(w).WriteDocument(dxfDoc); err != nil {
+		w.Close()
+		os.Remove(out)
+		result.Err = err
+		return result
+	}
+	if err := w.Close(); err != nil {
+		os.Remove(out)
+		result.Err = err
+		return result
+	}
+	result.Output = out
+	return result
+}
@@ -0,0 +1,74 @@
+package dxf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestConvertDirectory(t *testing.T) {
+	inDir, outDir := t.TempDir(), t.TempDir()
+
+	doc := &jww.Document{Version: 700}
+	for i := range doc.LayerGroups {
+		doc.LayerGroups[i].Scale = 1
+	}
+	doc.Entities = []jww.Entity{&jww.Line{EndX: 10}}
+	var buf bytes.Buffer
+	if err := jww.Write(&buf, doc); err != nil {
+		t.Fatalf("jww.Write failed: %v", err)
+	}
+	valid := filepath.Join(inDir, "sub", "plan.JWW")
+	if err := os.MkdirAll(filepath.Dir(valid), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(valid, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(inDir, "broken.jww")
+	if err := os.WriteFile(invalid, []byte("not a jww file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(inDir, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ConvertDirectory(inDir, outDir, ConvertOptions{})
+	if err != nil {
+		t.Fatalf("ConvertDirectory failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+
+	// Results are sorted by input path
+	broken, plan := results[0], results[1]
+	if broken.Input != invalid || broken.Err == nil || broken.Output != "" {
+		t.Errorf("invalid file: got %+v, want an error and no output", broken)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "broken.dxf")); !os.IsNotExist(err) {
+		t.Errorf("invalid file produced a DXF file: %v", err)
+	}
+
+	wantOut := filepath.Join(outDir, "sub", "plan.dxf")
+	if plan.Input != valid || plan.Err != nil || plan.Output != wantOut {
+		t.Fatalf("valid file: got %+v, want output %s", plan, wantOut)
+	}
+	data, err := os.ReadFile(wantOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\n  0\nLINE\n") || !strings.HasSuffix(string(data), "EOF\n") {
+		t.Errorf("output is not a DXF file with the line:\n%s", data)
+	}
+}
+
+func TestConvertDirectory_MissingDir(t *testing.T) {
+	if _, err := ConvertDirectory(filepath.Join(t.TempDir(), "missing"), t.TempDir(), ConvertOptions{}); err == nil {
+		t.Error("expected an error for a missing input directory")
+	}
+}
@@ -211,6 +211,19 @@ func WithTextStyle(style string) TextOption {
 	}
 }
 
+// WithTextAlignment sets the justification and the alignment point (x, y)
+// of a Text entity.
+func WithTextAlignment(h TextHAlign, v TextVAlign, x, y float64) TextOption {
+	return func(t *Text) {
+		t.HAlign, t.VAlign = h, v
+		t.AlignX, t.AlignY = x, y
+	}
+}
+
+// DefaultTextHeight is the height of texts created by NewText, and of
+// converted JWW texts without a height unless ConvertOptions says otherwise.
+const DefaultTextHeight = 2.5
+
 // NewText creates a new Text entity with the given position and content.
 // Optional TextOption functions can customize the text properties.
 //
@@ -227,7 +240,7 @@ func NewText(x, y float64, content string, opts ...TextOption) *Text {
 		LineType: "CONTINUOUS",
 		X:        x,
 		Y:        y,
-		Height:   2.5, // Default height
+		Height:   DefaultTextHeight,
 		Rotation: 0,
 		Content:  content,
 		Style:    "STANDARD",
@@ -0,0 +1,192 @@
+package dxf
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Clone returns a copy of the line.
+func (l *Line) Clone() Entity {
+	c := *l
+	c.XData = l.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the circle.
+func (c *Circle) Clone() Entity {
+	clone := *c
+	clone.XData = c.XData.clone()
+	return &clone
+}
+
+// Clone returns a copy of the arc.
+func (a *Arc) Clone() Entity {
+	c := *a
+	c.XData = a.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the ellipse.
+func (e *Ellipse) Clone() Entity {
+	c := *e
+	c.XData = e.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the point.
+func (p *Point) Clone() Entity {
+	c := *p
+	c.XData = p.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the text.
+func (t *Text) Clone() Entity {
+	c := *t
+	c.XData = t.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the solid.
+func (s *Solid) Clone() Entity {
+	c := *s
+	c.XData = s.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the image.
+func (i *Image) Clone() Entity {
+	c := *i
+	c.XData = i.XData.clone()
+	return &c
+}
+
+// Clone returns a copy of the insert, including its attributes.
+func (i *Insert) Clone() Entity {
+	c := *i
+	c.XData = i.XData.clone()
+	c.Attributes = slices.Clone(i.Attributes)
+	return &c
+}
+
+// Clone returns a copy of the polyline, including its vertices.
+func (p *LWPolyline) Clone() Entity {
+	c := *p
+	c.XData = p.XData.clone()
+	c.Vertices = slices.Clone(p.Vertices)
+	return &c
+}
+
+// Clone returns a copy of the spline, including its control points and knots.
+func (s *Spline) Clone() Entity {
+	c := *s
+	c.XData = s.XData.clone()
+	c.ControlPoints = slices.Clone(s.ControlPoints)
+	c.Knots = slices.Clone(s.Knots)
+	return &c
+}
+
+// Clone returns a copy of the hatch, including its boundaries.
+func (h *Hatch) Clone() Entity {
+	c := *h
+	c.XData = h.XData.clone()
+	if h.Boundaries != nil {
+		c.Boundaries = make([][]Vertex, len(h.Boundaries))
+		for i, boundary := range h.Boundaries {
+			c.Boundaries[i] = slices.Clone(boundary)
+		}
+	}
+	return &c
+}
+
+// Clone returns a copy of the dimension, including the entities drawing it.
+func (d *Dimension) Clone() Entity {
+	c := *d
+	c.XData = d.XData.clone()
+	c.Entities = cloneEntities(d.Entities)
+	return &c
+}
+
+// cloner is implemented by entities that can copy themselves.
+type cloner interface {
+	Clone() Entity
+}
+
+// cloneEntities copies each entity that implements Clone. Other entities
+// (custom Entity implementations) are shared with the original slice.
+func cloneEntities(entities []Entity) []Entity {
+	if entities == nil {
+		return nil
+	}
+	clones := make([]Entity, len(entities))
+	for i, e := range entities {
+		if c, ok := e.(cloner); ok {
+			clones[i] = c.Clone()
+		} else {
+			clones[i] = e
+		}
+	}
+	return clones
+}
+
+// Clone returns a deep copy of the document: its layers, linetypes, text
+// styles, comments, model and paper space entities, blocks with their
+// entities and attributes, and groups, which refer to the copied entities.
+// Editing the copy leaves the original unchanged. Entity types defined
+// outside this package are shared unless they implement Clone() Entity.
+//
+// Example:
+//
+//	edited := doc.Clone()
+//	edited.Transform(dxf.TranslationMatrix(100, 0))
+func (d *Document) Clone() *Document {
+	c := *d
+	c.Layers = slices.Clone(d.Layers)
+	if d.LineTypes != nil {
+		c.LineTypes = make([]LineType, len(d.LineTypes))
+		for i, lt := range d.LineTypes {
+			lt.Pattern = slices.Clone(lt.Pattern)
+			c.LineTypes[i] = lt
+		}
+	}
+	c.TextStyles = slices.Clone(d.TextStyles)
+	c.Comments = slices.Clone(d.Comments)
+	c.Entities = cloneEntities(d.Entities)
+	c.PaperSpaceEntities = cloneEntities(d.PaperSpaceEntities)
+	if d.Blocks != nil {
+		c.Blocks = make([]Block, len(d.Blocks))
+		for i, block := range d.Blocks {
+			block.Entities = cloneEntities(block.Entities)
+			block.Attributes = slices.Clone(block.Attributes)
+			c.Blocks[i] = block
+		}
+	}
+	if d.Groups != nil {
+		clones := make(map[Entity]Entity)
+		mapClones(clones, d.Entities, c.Entities)
+		mapClones(clones, d.PaperSpaceEntities, c.PaperSpaceEntities)
+		for i := range d.Blocks {
+			mapClones(clones, d.Blocks[i].Entities, c.Blocks[i].Entities)
+		}
+		c.Groups = make([]Group, len(d.Groups))
+		for i, g := range d.Groups {
+			g.Entities = slices.Clone(g.Entities)
+			for j, e := range g.Entities {
+				if e != nil && reflect.TypeOf(e).Comparable() && clones[e] != nil {
+					g.Entities[j] = clones[e]
+				}
+			}
+			c.Groups[i] = g
+		}
+	}
+	return &c
+}
+
+// mapClones records the clone of each comparable entity in clones.
+func mapClones(clones map[Entity]Entity, originals, copies []Entity) {
+	for i, e := range originals {
+		if e != nil && reflect.TypeOf(e).Comparable() {
+			clones[e] = copies[i]
+		}
+	}
+}
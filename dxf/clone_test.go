@@ -0,0 +1,80 @@
+package dxf
+
+import "testing"
+
+func TestEntityClone(t *testing.T) {
+	poly := &LWPolyline{Vertices: []Vertex{{0, 0}, {10, 0}}}
+	clone := poly.Clone().(*LWPolyline)
+	clone.Vertices[1].X = 99
+	clone.Layer = "B"
+	if poly.Vertices[1].X != 10 || poly.Layer != "" {
+		t.Errorf("mutating the clone changed the original: %+v", poly)
+	}
+
+	hatch := &Hatch{Boundaries: [][]Vertex{{{0, 0}, {1, 0}, {1, 1}}}}
+	hatch.Clone().(*Hatch).Boundaries[0][2].Y = 5
+	if hatch.Boundaries[0][2].Y != 1 {
+		t.Errorf("hatch boundary shared with clone: %v", hatch.Boundaries)
+	}
+
+	spline := &Spline{ControlPoints: []Vertex{{0, 0}}, Knots: []float64{0, 1}}
+	sc := spline.Clone().(*Spline)
+	sc.ControlPoints[0].X, sc.Knots[1] = 3, 2
+	if spline.ControlPoints[0].X != 0 || spline.Knots[1] != 1 {
+		t.Errorf("spline data shared with clone: %+v", spline)
+	}
+}
+
+func TestDocumentClone(t *testing.T) {
+	doc := NewDocument().
+		AddLayer("A", 1, "CONTINUOUS").
+		AddLine(0, 0, 10, 10).
+		AddInsert("B", 5, 5).
+		AddBlock(Block{Name: "B", Entities: []Entity{NewCircle(0, 0, 1)}, Attributes: []Attribute{{Tag: "NO"}}})
+	doc.Entities[1].(*Insert).Attributes = []Attribute{{Tag: "NO", Value: "1"}}
+	doc.Groups = []Group{{Name: "G", Entities: []Entity{doc.Entities[0], doc.Blocks[0].Entities[0]}}}
+
+	clone := doc.Clone()
+	if g := clone.Groups[0]; g.Entities[0] != clone.Entities[0] || g.Entities[1] != clone.Blocks[0].Entities[0] {
+		t.Errorf("group members do not refer to the cloned entities: %+v", g.Entities)
+	}
+	clone.Layers[len(clone.Layers)-1].Color = 5
+	clone.Entities[0].(*Line).X2 = 99
+	clone.Entities[1].(*Insert).Attributes[0].Value = "2"
+	clone.Entities = append(clone.Entities, NewPoint(0, 0))
+	clone.Blocks[0].Entities[0].(*Circle).Radius = 7
+	clone.Blocks[0].Attributes[0].Tag = "ID"
+	clone.Transform(TranslationMatrix(100, 0))
+
+	if layer := doc.Layers[len(doc.Layers)-1]; layer.Color != 1 {
+		t.Errorf("layer %s color changed: %d", layer.Name, layer.Color)
+	}
+	if len(doc.Entities) != 2 {
+		t.Errorf("entity count changed: %d", len(doc.Entities))
+	}
+	if l := doc.Entities[0].(*Line); l.X1 != 0 || l.X2 != 10 {
+		t.Errorf("line changed: %+v", l)
+	}
+	if a := doc.Entities[1].(*Insert).Attributes[0]; a.Value != "1" {
+		t.Errorf("insert attribute changed: %+v", a)
+	}
+	if c := doc.Blocks[0].Entities[0].(*Circle); c.Radius != 1 {
+		t.Errorf("block circle changed: %+v", c)
+	}
+	if doc.Blocks[0].Attributes[0].Tag != "NO" {
+		t.Errorf("block attribute changed: %+v", doc.Blocks[0].Attributes[0])
+	}
+}
+
+func TestEntityClone_XData(t *testing.T) {
+	line := NewLine(0, 0, 1, 1)
+	line.XData = XData{"APP": {{1071, 7}}}
+
+	clone := line.Clone().(*Line)
+	clone.XData["APP"][0].Value = 8
+	clone.XData["OTHER"] = nil
+
+	if line.XData["APP"][0].Value != 7 || len(line.XData) != 1 {
+		t.Errorf("mutating the clone's xdata changed the original: %v", line.XData)
+	}
+}
@@ -2,17 +2,25 @@ package dxf
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/f4ah6o/jww-parser/jww"
+	"golang.org/x/text/width"
 )
 
 // ConvertDocument converts a JWW (Jw_cad) document to a DXF document.
 //
 // This function transforms JWW entities into their DXF equivalents:
 //   - JWW layers are converted to DXF layers with appropriate mapping
-//   - JWW entities (Line, Arc, Point, Text, Solid, Block) are converted to DXF entities
+//   - JWW entities (Line, Arc, Point, Text, Solid, Block) are converted to DXF entities;
+//     circle solids become solid-fill HATCH entities, and dimensions their
+//     line, text, and arrowhead solids
 //   - JWW block definitions are converted to DXF blocks
+//   - JWW entity groups (curve attribute numbers) are converted to DXF groups
 //
 // The conversion handles:
 //   - Layer group and layer hierarchy mapping
@@ -23,23 +31,475 @@ import (
 //
 // Returns a DXF Document ready to be written to a file.
 func ConvertDocument(doc *jww.Document) *Document {
+	return ConvertDocumentWithOptions(doc, ConvertOptions{})
+}
+
+// ConvertOptions controls optional behavior of ConvertDocumentWithOptions.
+// The zero value produces the same output as ConvertDocument.
+type ConvertOptions struct {
+	// SingleColorByLayer writes entity colors as BYLAYER when every converted
+	// entity shares the same color, and assigns that color to the layers instead.
+	// This keeps monochrome drawings easy to recolor in CAD software.
+	SingleColorByLayer bool
+
+	// Logger, if non-nil, receives one line per entity describing how it was
+	// converted or why it was skipped (e.g. "arc 42 -> ELLIPSE: flatness 0.5").
+	// Entities are identified by their index in the document entity list,
+	// or by "<block name>/<index>" for block definition entities.
+	Logger io.Writer
+
+	// SortEntities orders the converted entities canonically so re-exports
+	// of the same drawing can be diffed. Entities (and each block's entities)
+	// are sorted by layer name, then DXF entity type, then the X and Y of a
+	// representative point (start point, center, insertion point, or first
+	// vertex). The sort is stable: entities with equal keys keep file order.
+	SortEntities bool
+
+	// LineTypeScale is a global multiplier for dashed linetypes, written as
+	// $LTSCALE (0 means 1.0). Independently of it, entities with
+	// non-continuous linetypes get their layer group's scale denominator as
+	// entity linetype scale (group code 48), so dash lengths keep the same
+	// paper size as in Jw_cad regardless of the group scale.
+	LineTypeScale float64
+
+	// TitleBlockLayers names DXF layers (e.g. "F-0" or a named JWW layer)
+	// whose entities belong to the paper space layout. They are moved to
+	// Document.PaperSpaceEntities instead of model space.
+	TitleBlockLayers []string
+
+	// IncludeTemporaryPoints converts temporary (construction) points, which
+	// are dropped by default, onto the TemporaryPointLayer layer.
+	IncludeTemporaryPoints bool
+
+	// LayerColorsFromEntities sets each layer's color to the most common
+	// explicit entity color on that layer (ties go to the lower ACI number),
+	// so the layer palette matches what the drawing shows. Layers without
+	// colored entities keep their default color.
+	LayerColorsFromEntities bool
+
+	// EntityHook, if non-nil, is called with each JWW entity and its DXF
+	// conversion, including block definition entities. The returned entity
+	// replaces the conversion; returning nil drops it. Entities skipped by
+	// the converter are not passed to the hook. Dimensions, which convert to
+	// several entities, pass each of them, or their DIMENSION entity with
+	// DimensionEntities. Layers the hook assigns
+	// must exist in the layer table for the output to validate.
+	EntityHook func(jww.Entity, Entity) Entity
+
+	// IncludeLayers, if not empty, keeps only model space entities whose
+	// DXF layer name (e.g. "2-A" or a named JWW layer) is listed.
+	// ExcludeLayers drops entities on the listed layers, also when they are
+	// included. Block definition entities are not filtered.
+	IncludeLayers []string
+	ExcludeLayers []string
+
+	// ColorToLayer puts each entity on a layer named after its JWW pen
+	// color (ColorLayerName) instead of its JWW layer, for workflows that
+	// map colors to plotter pens or tools. The color layers are created
+	// for the colors in use, with the matching ACI color.
+	ColorToLayer bool
+
+	// GlobalRotation (degrees, counter-clockwise about the origin) and then
+	// GlobalTranslation are applied to the whole converted drawing with
+	// Document.Transform, e.g. to align a site plan to project north.
+	GlobalRotation    float64
+	GlobalTranslation Vertex
+
+	// GroupToLayerSuffix puts entities with a non-zero JWW curve attribute
+	// number (EntityBase.Group, 曲線属性番号) on a layer named after their
+	// JWW layer and the number (GroupLayerName), e.g. "2-3_G5", so groups
+	// that carry meaning such as structural or architectural lines stay
+	// apart.
+	// The group layers copy the settings of their JWW layer. It is ignored
+	// with ColorToLayer.
+	GroupToLayerSuffix bool
+
+	// PruneUnusedLayers removes layers no entity (including paper space and
+	// block definition entities) refers to from the layer table, instead of
+	// writing all 256 JWW layers. Layer "0" is always written.
+	PruneUnusedLayers bool
+
+	// SXFPalette maps the predefined SXF extended colors (JWW pen colors
+	// 101-116) to the ACI color nearest to their RGB value (SXFColorRGB)
+	// instead of the legacy offset mapping, which collides with unrelated
+	// ACI hues. User-defined SXF colors keep the legacy mapping.
+	SXFPalette bool
+
+	// SkipHiddenLayerEntities drops model space entities on JWW layers that
+	// are hidden (layer or layer group state 0) instead of emitting them on
+	// frozen layers, which shrinks presentation exports. Block definition
+	// entities are kept.
+	SkipHiddenLayerEntities bool
+
+	// DefaultTextHeight is the height given to texts without a height
+	// (SizeY <= 0), which also sets the arrow length of their dimensions.
+	// 0 means DefaultTextHeight (2.5), the height NewText uses.
+	DefaultTextHeight float64
+
+	// ScaleDefaultTextHeight multiplies the default text height by the
+	// layer group's scale denominator, so texts without a height keep the
+	// same paper size in groups drawn at different scales. PaperSizesToWorld
+	// implies it.
+	ScaleDefaultTextHeight bool
+
+	// PaperSizesToWorld converts the sizes JWW stores in paper millimeters
+	// (図寸) to the real-world millimeters (実寸) of the coordinates by
+	// multiplying them by the layer group's scale denominator: text heights,
+	// including default ones, and the dimension arrow lengths derived from
+	// them. Without it a 5 mm text on a 1:100 layer group is 5 drawing units
+	// high next to geometry drawn 100 times larger.
+	// It also sets $DIMSCALE to the scale of the write layer group.
+	PaperSizesToWorld bool
+
+	// TextAlignment justifies texts along their JWW baseline, which runs
+	// from the start to the end point. JWW keeps only these points, not the
+	// justification chosen when the text was placed, so the choice decides
+	// which point stays fixed when the CAD font is wider or narrower than
+	// the JWW one: TextHAlignLeft (the default) keeps the start point,
+	// TextHAlignCenter the midpoint, TextHAlignMiddle the center of the
+	// text box, and TextHAlignRight the end point, while TextHAlignAligned
+	// and TextHAlignFit stretch texts between both points. Texts whose end
+	// point equals the start point stay left-justified.
+	TextAlignment TextHAlign
+
+	// SourceIDs attaches the JWW entity ID (jww.EntityBase.ID) to each
+	// converted entity as XData of application SourceIDAppName: a 1071 code
+	// with the ID. It lets DXF entities be traced back to the JWW entities
+	// they came from. Entities without an ID get none.
+	SourceIDs bool
+
+	// SourceAttributes stores the original JWW attributes of each converted
+	// entity as XData of application SourceAttributesAppName, so they can
+	// be recovered from the DXF: 1070 codes with the layer group, layer,
+	// and pen style, then 1071 codes with the pen color and curve attribute
+	// number.
+	SourceAttributes bool
+
+	// DimensionEntities converts JWW dimensions to DIMENSION entities that
+	// use the STANDARD dimension style, so CAD software can edit them as
+	// dimensions. Their measurement line, text, and arrowheads, which are
+	// written as separate entities by default, draw the dimension through
+	// its anonymous block.
+	DimensionEntities bool
+
+	// InsertAttributes, if set, returns the attributes of a JWW block
+	// insert, such as the fields of a title block, which JWW files do not
+	// store. Only their Tag and Value are used: they are written as ATTRIB
+	// entities stacked below the insertion point of the INSERT, and each
+	// tag used by the inserts of a block gets an ATTDEF entity in its
+	// definition.
+	InsertAttributes func(b *jww.Block) []Attribute
+
+	// AddExtentsRectangle appends a closed LWPOLYLINE tracing the model
+	// space extents (Document.ComputeExtents, written as $EXTMIN/$EXTMAX)
+	// on the ExtentsLayer layer, to check the extents visually. Drawings
+	// without extents get none.
+	AddExtentsRectangle bool
+
+	// CurveSegments tessellates circles, arcs, and ellipses into LWPOLYLINE
+	// entities of this many straight segments, for targets that cannot draw
+	// curves or to trade precision for file size. Full circles and ellipses
+	// become closed polylines, arcs keep their exact endpoints. Values <= 0
+	// write native CIRCLE, ARC, and ELLIPSE entities.
+	CurveSegments int
+}
+
+// TemporaryPointLayer is the layer that receives temporary points when
+// ConvertOptions.IncludeTemporaryPoints is set.
+const TemporaryPointLayer = "TEMP_POINTS"
+
+// ExtentsLayer is the layer of the rectangle added by
+// ConvertOptions.AddExtentsRectangle.
+const ExtentsLayer = "_EXTENTS"
+
+// XData application names used by ConvertOptions.SourceIDs and
+// ConvertOptions.SourceAttributes.
+const (
+	SourceIDAppName         = "JWW_PARSER"
+	SourceAttributesAppName = "JWW_ATTRIBUTES"
+)
+
+// GroupLayerName returns the name of the layer that receives entities with
+// JWW curve attribute number group on layer when
+// ConvertOptions.GroupToLayerSuffix is set, e.g. "2-3_G5".
+func GroupLayerName(layer string, group uint32) string {
+	return fmt.Sprintf("%s_G%d", layer, group)
+}
+
+// ColorLayerName returns the name of the layer that receives entities of
+// JWW pen color when ConvertOptions.ColorToLayer is set, e.g. "COLOR_8".
+func ColorLayerName(penColor uint16) string {
+	return fmt.Sprintf("COLOR_%d", penColor)
+}
+
+// logf writes a formatted line to the Logger, if one is set.
+func (o ConvertOptions) logf(format string, args ...interface{}) {
+	if o.Logger != nil {
+		fmt.Fprintf(o.Logger, format+"\n", args...)
+	}
+}
+
+// insertAttributes returns the attributes InsertAttributes gives b, if set.
+func (o ConvertOptions) insertAttributes(b *jww.Block) []Attribute {
+	if o.InsertAttributes == nil {
+		return nil
+	}
+	return o.InsertAttributes(b)
+}
+
+// aci returns the DXF ACI color for a JWW pen color.
+func (o ConvertOptions) aci(penColor uint16) int {
+	if o.SXFPalette {
+		return mapSXFColor(penColor)
+	}
+	return mapColor(penColor)
+}
+
+// textHeight returns the height for texts without a height on layerGroup.
+func (o ConvertOptions) textHeight(doc *jww.Document, layerGroup uint16) float64 {
+	height := o.DefaultTextHeight
+	if height <= 0 {
+		height = DefaultTextHeight
+	}
+	if o.ScaleDefaultTextHeight || o.PaperSizesToWorld {
+		height *= groupScale(doc, layerGroup)
+	}
+	return height
+}
+
+// paperSize returns the JWW paper size size on layerGroup in drawing units,
+// scaled to real-world size if PaperSizesToWorld is set.
+func (o ConvertOptions) paperSize(doc *jww.Document, layerGroup uint16, size float64) float64 {
+	if o.PaperSizesToWorld {
+		size *= groupScale(doc, layerGroup)
+	}
+	return size
+}
+
+// ConvertDocumentWithOptions converts a JWW document to a DXF document like
+// ConvertDocument, applying the behavior selected in opts.
+func ConvertDocumentWithOptions(doc *jww.Document, opts ConvertOptions) *Document {
+	entities, converted := convertEntities(doc, opts)
 	dxfDoc := &Document{
-		Layers:   convertLayers(doc),
-		Entities: convertEntities(doc),
-		Blocks:   convertBlocks(doc),
+		Layers:   convertLayers(doc, opts),
+		Entities: entities,
+		Blocks:   convertBlocks(doc, opts),
+		Groups:   convertGroups(doc, converted),
+
+		LineTypes:  convertLineTypes(doc),
+		TextStyles: convertTextStyles(doc),
+
+		LineTypeScale: opts.LineTypeScale,
+		DimStyle:      convertDimStyle(opts),
+		DimScale:      dimScale(doc, opts),
+		Units:         Millimeters, // JWW coordinates are in millimeters
+		Comments:      convertComments(doc),
+	}
+
+	dxfDoc.Blocks = append(dxfDoc.Blocks, markerBlocks(dxfDoc)...)
+
+	if hasStyledPoints(doc, opts) {
+		dxfDoc.PointMode = pointModeX
+		dxfDoc.PointSize = markerSize
+	}
+
+	if opts.ColorToLayer {
+		dxfDoc.Layers = append(dxfDoc.Layers, colorLayers(doc, opts)...)
+	}
+
+	if opts.IncludeTemporaryPoints {
+		dxfDoc.Layers = append(dxfDoc.Layers, Layer{
+			Name:     TemporaryPointLayer,
+			Color:    7,
+			LineType: "CONTINUOUS",
+		})
+	}
+
+	if len(opts.TitleBlockLayers) > 0 {
+		dxfDoc.Entities, dxfDoc.PaperSpaceEntities = splitPaperSpace(dxfDoc.Entities, opts)
+	}
+
+	if opts.PruneUnusedLayers {
+		dxfDoc.Layers = usedLayers(dxfDoc)
+	}
+
+	dxfDoc.CurrentLayer = currentLayer(doc, dxfDoc.Layers)
+
+	if opts.LayerColorsFromEntities {
+		applyLayerColorsFromEntities(dxfDoc)
+	}
+
+	if opts.SingleColorByLayer {
+		applySingleColorByLayer(dxfDoc)
+	}
+
+	if opts.GlobalRotation != 0 || opts.GlobalTranslation != (Vertex{}) {
+		dxfDoc.Transform(RotationMatrix(opts.GlobalRotation).
+			Then(TranslationMatrix(opts.GlobalTranslation.X, opts.GlobalTranslation.Y)))
+	}
+
+	if opts.AddExtentsRectangle {
+		addExtentsRectangle(dxfDoc)
+	}
+
+	if opts.SortEntities {
+		sortEntities(dxfDoc.Entities)
+		sortEntities(dxfDoc.PaperSpaceEntities)
+		for i := range dxfDoc.Blocks {
+			sortEntities(dxfDoc.Blocks[i].Entities)
+		}
 	}
+
 	return dxfDoc
 }
 
+// convertComments returns the JWW memo, if any, as DXF comments. JWW has
+// no other comment record; further comments can be added to the converted
+// Document.Comments.
+func convertComments(doc *jww.Document) []string {
+	if strings.TrimSpace(doc.Memo) == "" {
+		return nil
+	}
+	return []string{doc.Memo}
+}
+
+// splitPaperSpace separates entities on opts.TitleBlockLayers from the rest.
+func splitPaperSpace(entities []Entity, opts ConvertOptions) (model, paper []Entity) {
+	titleBlock := make(map[string]bool, len(opts.TitleBlockLayers))
+	for _, name := range opts.TitleBlockLayers {
+		titleBlock[name] = true
+	}
+
+	for _, e := range entities {
+		if layer, ok := layerOf(e); ok && titleBlock[layer] {
+			opts.logf("%s on layer %s moved to paper space", strings.ToLower(e.EntityType()), layer)
+			paper = append(paper, e)
+		} else {
+			model = append(model, e)
+		}
+	}
+	return model, paper
+}
+
+// applySingleColorByLayer moves a shared entity color onto the layer table.
+// If all entities (including block entities) carry the same explicit color,
+// their colors are set to BYLAYER and every layer takes that color.
+// Documents with mixed colors or only BYLAYER entities are left unchanged.
+func applySingleColorByLayer(doc *Document) {
+	var colors []*int
+	for _, e := range doc.Entities {
+		if c := colorOf(e); c != nil {
+			colors = append(colors, c)
+		}
+	}
+	for _, e := range doc.PaperSpaceEntities {
+		if c := colorOf(e); c != nil {
+			colors = append(colors, c)
+		}
+	}
+	for i := range doc.Blocks {
+		for _, e := range doc.Blocks[i].Entities {
+			if c := colorOf(e); c != nil {
+				colors = append(colors, c)
+			}
+		}
+	}
+
+	if len(colors) == 0 {
+		return
+	}
+	shared := *colors[0]
+	if shared == 0 {
+		return
+	}
+	for _, c := range colors[1:] {
+		if *c != shared {
+			return
+		}
+	}
+
+	for _, c := range colors {
+		*c = 0 // BYLAYER
+	}
+	for i := range doc.Layers {
+		doc.Layers[i].Color = shared
+	}
+}
+
+// applyLayerColorsFromEntities sets each layer's color to the most common
+// explicit color of the entities (including block entities) on it.
+func applyLayerColorsFromEntities(doc *Document) {
+	counts := make(map[string]map[int]int)
+	count := func(entities []Entity) {
+		for _, e := range entities {
+			layer, ok := layerOf(e)
+			c := colorOf(e)
+			if !ok || c == nil || *c <= 0 || *c > 255 {
+				continue // BYLAYER, BYBLOCK, or unknown
+			}
+			if counts[layer] == nil {
+				counts[layer] = make(map[int]int)
+			}
+			counts[layer][*c]++
+		}
+	}
+	count(doc.Entities)
+	count(doc.PaperSpaceEntities)
+	for i := range doc.Blocks {
+		count(doc.Blocks[i].Entities)
+	}
+
+	for i := range doc.Layers {
+		best, bestCount := 0, 0
+		for color, n := range counts[doc.Layers[i].Name] {
+			if n > bestCount || (n == bestCount && color < best) {
+				best, bestCount = color, n
+			}
+		}
+		if best != 0 {
+			doc.Layers[i].Color = best
+		}
+	}
+}
+
+// sortEntities stably sorts entities by layer, entity type, and anchor point.
+func sortEntities(entities []Entity) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+		la, _ := layerOf(a)
+		lb, _ := layerOf(b)
+		if la != lb {
+			return la < lb
+		}
+		if ta, tb := a.EntityType(), b.EntityType(); ta != tb {
+			return ta < tb
+		}
+		ax, ay := anchorOf(a)
+		bx, by := anchorOf(b)
+		if ax != bx {
+			return ax < bx
+		}
+		return ay < by
+	})
+}
+
 // convertLayers creates DXF layers from JWW layer groups.
 // JWW has 16 layer groups with 16 layers each (256 total layers).
 // Each JWW layer is converted to a single DXF layer with a name like "0-0" or "F-A".
-// Layer properties (frozen, locked) are preserved in the conversion.
-func convertLayers(doc *jww.Document) []Layer {
+// Layer properties (frozen, locked) are preserved in the conversion, and the
+// layer color and linetype come from the pen color and style most used by
+// the lines and arcs in the group (jww.LayerGroup.InferredPenColor and
+// InferredPenStyle), as JWW stores no layer colors.
+func convertLayers(doc *jww.Document, opts ConvertOptions) []Layer {
 	var layers []Layer
 
 	for gLay := 0; gLay < 16; gLay++ {
 		lg := &doc.LayerGroups[gLay]
+		color := opts.aci(lg.InferredPenColor)
+		if color == 0 {
+			color = 7 // No entities in the group: foreground white/black
+		}
+		lineType := lineTypeName(doc, lg.InferredPenStyle)
 		for lay := 0; lay < 16; lay++ {
 			l := &lg.Layers[lay]
 			name := l.Name
@@ -49,32 +509,451 @@ func convertLayers(doc *jww.Document) []Layer {
 
 			layers = append(layers, Layer{
 				Name:     name,
-				Color:    (gLay*16+lay)%255 + 1, // Simple ACI color mapping
-				LineType: "CONTINUOUS",
+				Color:    color,
+				LineType: lineType,
 				Frozen:   l.State == 0,
 				Locked:   l.Protect != 0,
 			})
 		}
 	}
 
+	if opts.GroupToLayerSuffix && !opts.ColorToLayer {
+		layers = append(layers, groupLayers(doc, layers)...)
+	}
+
 	return layers
 }
 
+// groupLayers returns a layer for each combination of JWW layer and
+// non-zero curve attribute number used by the document and block definition entities,
+// copying the settings of the JWW layer from layers (in group and layer
+// order, as built by convertLayers).
+func groupLayers(doc *jww.Document, layers []Layer) []Layer {
+	type key struct {
+		layer int // index into layers
+		group uint32
+	}
+	used := make(map[key]bool)
+	var keys []key
+	add := func(e jww.Entity) {
+		base := e.Base()
+		if base.Group == 0 || base.LayerGroup >= 16 || base.Layer >= 16 {
+			return
+		}
+		k := key{int(base.LayerGroup)*16 + int(base.Layer), base.Group}
+		if !used[k] {
+			used[k] = true
+			keys = append(keys, k)
+		}
+	}
+	for _, e := range doc.Entities {
+		add(e)
+	}
+	for _, bd := range doc.BlockDefs {
+		for _, e := range bd.Entities {
+			add(e)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].layer != keys[j].layer {
+			return keys[i].layer < keys[j].layer
+		}
+		return keys[i].group < keys[j].group
+	})
+	extra := make([]Layer, 0, len(keys))
+	for _, k := range keys {
+		layer := layers[k.layer]
+		layer.Name = GroupLayerName(layer.Name, k.group)
+		extra = append(extra, layer)
+	}
+	return extra
+}
+
 // convertEntities converts all JWW entities to DXF entities.
 // This function iterates through all entities in the JWW document and
 // converts each one based on its type. Unsupported or invalid entities
-// are skipped.
-func convertEntities(doc *jww.Document) []Entity {
-	var entities []Entity
+// are skipped. converted holds the DXF entities kept for each JWW entity,
+// by index.
+func convertEntities(doc *jww.Document, opts ConvertOptions) (entities []Entity, converted [][]Entity) {
+	filter := newLayerFilter(opts)
+	converted = make([][]Entity, len(doc.Entities))
 
-	for _, e := range doc.Entities {
-		dxfEntity := convertEntity(e, doc)
-		if dxfEntity != nil {
+	for i, e := range doc.Entities {
+		if opts.SkipHiddenLayerEntities && onHiddenLayer(doc, e) {
+			base := e.Base()
+			opts.logf("%s %d skipped: layer %X-%X hidden", strings.ToLower(e.Type()), i, base.LayerGroup, base.Layer)
+			continue
+		}
+		for _, dxfEntity := range convertEntityWithHook(e, doc, opts, fmt.Sprint(i)) {
+			if layer, ok := layerOf(dxfEntity); ok && !filter.keeps(layer) {
+				opts.logf("%s %d skipped: layer %s filtered out", strings.ToLower(e.Type()), i, layer)
+				continue
+			}
 			entities = append(entities, dxfEntity)
+			converted[i] = append(converted[i], dxfEntity)
+		}
+	}
+
+	return entities, converted
+}
+
+// convertGroups converts the entity groups of doc to DXF groups named
+// JWW_GROUP followed by the curve attribute number, with the DXF entities
+// converted from their members. Groups without converted members are
+// left out.
+func convertGroups(doc *jww.Document, converted [][]Entity) []Group {
+	var groups []Group
+	for _, g := range doc.Groups {
+		var members []Entity
+		for _, i := range g.Entities {
+			if i >= 0 && i < len(converted) {
+				members = append(members, converted[i]...)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		groups = append(groups, Group{
+			Name:     fmt.Sprintf("JWW_GROUP%d", g.Number),
+			Entities: members,
+		})
+	}
+	return groups
+}
+
+// onHiddenLayer reports whether e lies on a hidden JWW layer or layer group.
+func onHiddenLayer(doc *jww.Document, e jww.Entity) bool {
+	base := e.Base()
+	if base.LayerGroup >= 16 || base.Layer >= 16 {
+		return false
+	}
+	lg := &doc.LayerGroups[base.LayerGroup]
+	return lg.State == 0 || lg.Layers[base.Layer].State == 0
+}
+
+// colorLayers returns a layer for each JWW pen color used by the
+// document and block definition entities, in color order, colored with
+// the matching ACI color.
+func colorLayers(doc *jww.Document, opts ConvertOptions) []Layer {
+	used := make(map[uint16]bool)
+	for _, e := range doc.Entities {
+		used[e.Base().PenColor] = true
+	}
+	for _, bd := range doc.BlockDefs {
+		for _, e := range bd.Entities {
+			used[e.Base().PenColor] = true
+		}
+	}
+
+	colors := make([]int, 0, len(used))
+	for c := range used {
+		colors = append(colors, int(c))
+	}
+	sort.Ints(colors)
+
+	layers := make([]Layer, 0, len(colors))
+	for _, c := range colors {
+		aci := opts.aci(uint16(c))
+		if aci == 0 {
+			aci = 7 // a layer cannot be BYLAYER
+		}
+		layers = append(layers, Layer{
+			Name:     ColorLayerName(uint16(c)),
+			Color:    aci,
+			LineType: "CONTINUOUS",
+		})
+	}
+	return layers
+}
+
+// layerFilter implements ConvertOptions.IncludeLayers and ExcludeLayers.
+type layerFilter struct {
+	include, exclude map[string]bool
+}
+
+// newLayerFilter builds the layer filter selected in opts.
+func newLayerFilter(opts ConvertOptions) layerFilter {
+	set := func(names []string) map[string]bool {
+		if len(names) == 0 {
+			return nil
+		}
+		m := make(map[string]bool, len(names))
+		for _, name := range names {
+			m[name] = true
+		}
+		return m
+	}
+	return layerFilter{set(opts.IncludeLayers), set(opts.ExcludeLayers)}
+}
+
+// keeps reports whether entities on layer pass the filter.
+func (f layerFilter) keeps(layer string) bool {
+	if f.include != nil && !f.include[layer] {
+		return false
+	}
+	return !f.exclude[layer]
+}
+
+// usedLayers returns the layers of doc that model space, paper space, or
+// block definition entities refer to, in layer table order. Layer "0" is
+// kept if the table has it; the writer adds it otherwise.
+func usedLayers(doc *Document) []Layer {
+	used := map[string]bool{"0": true}
+	var mark func(entities []Entity)
+	mark = func(entities []Entity) {
+		for _, e := range entities {
+			if layer, ok := layerOf(e); ok {
+				used[layer] = true
+			}
+			if d, ok := e.(*Dimension); ok {
+				mark(d.Entities) // drawn by the dimension's block
+			}
+		}
+	}
+	mark(doc.Entities)
+	mark(doc.PaperSpaceEntities)
+	for _, b := range doc.Blocks {
+		mark(b.Entities)
+	}
+
+	var layers []Layer
+	for _, l := range doc.Layers {
+		if used[l.Name] {
+			layers = append(layers, l)
+		}
+	}
+	return layers
+}
+
+// convertEntityWithHook converts e and passes each resulting entity through
+// opts.EntityHook, if one is set. Dimensions convert to several entities,
+// or to one DIMENSION with opts.DimensionEntities; other entity types to
+// at most one.
+func convertEntityWithHook(e jww.Entity, doc *jww.Document, opts ConvertOptions, ref string) []Entity {
+	var converted []Entity
+	if dim, ok := e.(*jww.Dimension); ok {
+		converted = convertDimension(dim, doc, opts, ref)
+		if opts.DimensionEntities && len(converted) > 0 {
+			opts.logf("dimension %s -> DIMENSION", ref)
+			converted = []Entity{dimensionEntity(dim, converted)}
+		}
+	} else if dxfEntity := convertEntity(e, doc, opts, ref); dxfEntity != nil {
+		converted = []Entity{dxfEntity}
+	}
+	if opts.CurveSegments > 0 {
+		for i, dxfEntity := range converted {
+			if p := curvePolyline(dxfEntity, opts.CurveSegments); p != nil {
+				opts.logf("%s %s -> LWPOLYLINE: %s in %d segments", strings.ToLower(e.Type()), ref, dxfEntity.EntityType(), opts.CurveSegments)
+				converted[i] = p
+			}
+		}
+	}
+	if opts.EntityHook != nil {
+		var hooked []Entity
+		for _, dxfEntity := range converted {
+			if h := opts.EntityHook(e, dxfEntity); h != nil {
+				hooked = append(hooked, h)
+			} else {
+				opts.logf("%s %s dropped by entity hook", strings.ToLower(e.Type()), ref)
+			}
+		}
+		converted = hooked
+	}
+
+	base := e.Base()
+	for _, dxfEntity := range converted {
+		if opts.SourceIDs && base.ID != 0 {
+			setXData(dxfEntity, SourceIDAppName, []GroupCode{{1071, int(base.ID)}})
+		}
+		if opts.SourceAttributes {
+			setXData(dxfEntity, SourceAttributesAppName, []GroupCode{
+				{1070, int(base.LayerGroup)},
+				{1070, int(base.Layer)},
+				{1070, int(base.PenStyle)},
+				{1071, int(base.PenColor)},
+				{1071, int(base.Group)},
+			})
+		}
+	}
+	return converted
+}
+
+// curvePolyline returns a circle, arc, or ellipse as a polyline of
+// segments chords, or nil for other entities. Full circles and ellipses
+// become closed polylines with segments vertices.
+func curvePolyline(e Entity, segments int) *LWPolyline {
+	var (
+		p            *LWPolyline
+		start, sweep float64
+		point        func(t float64) Vertex
+	)
+	switch c := e.(type) {
+	case *Circle:
+		p = &LWPolyline{Layer: c.Layer, Color: c.Color, LineType: c.LineType, LineTypeScale: c.LineTypeScale}
+		sweep = 2 * math.Pi
+		point = func(t float64) Vertex {
+			return Vertex{c.CenterX + c.Radius*math.Cos(t), c.CenterY + c.Radius*math.Sin(t)}
+		}
+	case *Arc:
+		p = &LWPolyline{Layer: c.Layer, Color: c.Color, LineType: c.LineType, LineTypeScale: c.LineTypeScale}
+		start = c.StartAngle * math.Pi / 180.0
+		sweep = math.Mod((c.EndAngle-c.StartAngle)*math.Pi/180.0, 2*math.Pi)
+		if sweep <= 0 {
+			sweep += 2 * math.Pi
+		}
+		point = func(t float64) Vertex {
+			return Vertex{c.CenterX + c.Radius*math.Cos(t), c.CenterY + c.Radius*math.Sin(t)}
 		}
+	case *Ellipse:
+		p = &LWPolyline{Layer: c.Layer, Color: c.Color, LineType: c.LineType, LineTypeScale: c.LineTypeScale}
+		start = c.StartParam
+		sweep = c.EndParam - c.StartParam
+		if sweep <= 0 {
+			sweep += 2 * math.Pi
+		}
+		minorX, minorY := -c.MajorAxisY*c.MinorRatio, c.MajorAxisX*c.MinorRatio
+		point = func(t float64) Vertex {
+			cos, sin := math.Cos(t), math.Sin(t)
+			return Vertex{c.CenterX + c.MajorAxisX*cos + minorX*sin, c.CenterY + c.MajorAxisY*cos + minorY*sin}
+		}
+	default:
+		return nil
 	}
 
-	return entities
+	p.Closed = sweep >= 2*math.Pi-1e-9
+	n := segments + 1
+	if p.Closed {
+		n = segments
+	}
+	p.Vertices = make([]Vertex, n)
+	for i := range n {
+		p.Vertices[i] = point(start + sweep*float64(i)/float64(segments))
+	}
+	return p
+}
+
+// convertDimStyle returns the dimension style matching converted
+// dimensions: arrowheads as long as the default text height, which is in
+// paper units like the text heights of the style.
+func convertDimStyle(opts ConvertOptions) DimStyle {
+	height := opts.DefaultTextHeight
+	if height <= 0 {
+		height = DefaultTextHeight
+	}
+	return DimStyle{ArrowSize: height, TextHeight: height}
+}
+
+// dimScale returns the dimension scale for the converted document: the
+// scale denominator of the write layer group if PaperSizesToWorld scales
+// paper sizes to real-world size, and 1 otherwise, when texts and
+// arrowheads keep their paper size in drawing units.
+func dimScale(doc *jww.Document, opts ConvertOptions) float64 {
+	if !opts.PaperSizesToWorld {
+		return 1
+	}
+	return groupScale(doc, uint16(doc.WriteLayerGroup))
+}
+
+// dimensionArrowAngle is the half angle of dimension arrowheads in radians.
+const dimensionArrowAngle = 15 * math.Pi / 180
+
+// convertDimension converts a JWW dimension to its measurement line, its
+// value text, and a solid arrowhead at each end of the line. Arrowheads
+// are as long as the text is high, and point at the dimension's end
+// points, or at the line ends for files without them (before Ver.4.20).
+// SXF dimensions also get their extension lines.
+func convertDimension(dim *jww.Dimension, doc *jww.Document, opts ConvertOptions, ref string) []Entity {
+	lineEntity := convertEntity(&dim.Line, doc, opts, ref+"/line")
+	line, ok := lineEntity.(*Line)
+	if !ok {
+		return nil
+	}
+	parts := []Entity{line}
+
+	if text := convertEntity(&dim.Text, doc, opts, ref+"/text"); text != nil {
+		parts = append(parts, text)
+	}
+
+	if dim.SXFMode != 0 {
+		for i := range dim.ExtensionLines {
+			if ext := convertEntity(&dim.ExtensionLines[i], doc, opts, fmt.Sprintf("%s/extension%d", ref, i)); ext != nil {
+				parts = append(parts, ext)
+			}
+		}
+	}
+
+	tips := [2]Vertex{{line.X1, line.Y1}, {line.X2, line.Y2}}
+	if p := dim.EndPoints; p[0].X != p[1].X || p[0].Y != p[1].Y {
+		tips = [2]Vertex{{p[0].X, p[0].Y}, {p[1].X, p[1].Y}}
+	}
+	length := opts.paperSize(doc, dim.Text.LayerGroup, dim.Text.SizeY)
+	if length <= 0 {
+		length = opts.textHeight(doc, dim.Text.LayerGroup)
+	}
+	arrows := 0
+	for i, tip := range tips {
+		if arrow := dimensionArrow(tip, tips[1-i], length); arrow != nil {
+			arrow.Layer, arrow.Color, arrow.LineType = line.Layer, line.Color, line.LineType
+			parts = append(parts, arrow)
+			arrows++
+		}
+	}
+
+	opts.logf("dimension %s -> %d entities with %d arrowhead SOLIDs", ref, len(parts), arrows)
+	return parts
+}
+
+// dimensionEntity returns a DIMENSION entity for dim drawn by parts, the
+// entities convertDimension returns, which start with the measurement line.
+// The measured points are the dimension's end points, or the line ends for
+// files without them.
+func dimensionEntity(dim *jww.Dimension, parts []Entity) *Dimension {
+	line := parts[0].(*Line)
+	d := &Dimension{
+		Layer:    line.Layer,
+		Color:    line.Color,
+		LineType: line.LineType,
+		X1:       line.X1,
+		Y1:       line.Y1,
+		X2:       line.X2,
+		Y2:       line.Y2,
+		LineX:    line.X2,
+		LineY:    line.Y2,
+		TextX:    (dim.Text.StartX + dim.Text.EndX) / 2,
+		TextY:    (dim.Text.StartY + dim.Text.EndY) / 2,
+		Entities: parts,
+	}
+	if p := dim.EndPoints; p[0].X != p[1].X || p[0].Y != p[1].Y {
+		d.X1, d.Y1, d.X2, d.Y2 = p[0].X, p[0].Y, p[1].X, p[1].Y
+	}
+	for _, part := range parts {
+		if text, ok := part.(*Text); ok {
+			d.Text = text.Content
+			break
+		}
+	}
+	return d
+}
+
+// dimensionArrow returns a triangular solid of the given length with its
+// tip at tip, pointing away from from. It returns nil if the points coincide.
+func dimensionArrow(tip, from Vertex, length float64) *Solid {
+	dx, dy := tip.X-from.X, tip.Y-from.Y
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return nil
+	}
+	ux, uy := dx/d, dy/d
+	baseX, baseY := tip.X-ux*length, tip.Y-uy*length
+	half := length * math.Tan(dimensionArrowAngle)
+
+	return &Solid{
+		X1: tip.X, Y1: tip.Y,
+		X2: baseX - uy*half, Y2: baseY + ux*half,
+		X3: baseX + uy*half, Y3: baseY - ux*half,
+		X4: baseX + uy*half, Y4: baseY - ux*half,
+	}
 }
 
 // convertEntity converts a single JWW entity to its DXF equivalent.
@@ -87,35 +966,52 @@ func convertEntities(doc *jww.Document) []Entity {
 //   - jww.Solid -> dxf.Solid
 //   - jww.Block -> dxf.Insert
 //
+// Dimensions are converted by convertDimension.
 // Returns nil for unsupported entity types or entities that should be skipped.
-func convertEntity(e jww.Entity, doc *jww.Document) Entity {
+// ref identifies the entity in log messages written to opts.Logger.
+func convertEntity(e jww.Entity, doc *jww.Document, opts ConvertOptions, ref string) Entity {
 	base := e.Base()
 	layerName := getLayerName(doc, base.LayerGroup, base.Layer)
-	color := mapColor(base.PenColor)
-	lineType := mapLineType(base.PenStyle)
+	if opts.ColorToLayer {
+		layerName = ColorLayerName(base.PenColor)
+	} else if opts.GroupToLayerSuffix && base.Group != 0 {
+		layerName = GroupLayerName(layerName, base.Group)
+	}
+	color := opts.aci(base.PenColor)
+	lineType := lineTypeName(doc, base.PenStyle)
+	label := strings.ToLower(e.Type()) + " " + ref
+	ltScale := groupScale(doc, base.LayerGroup)
 
 	switch v := e.(type) {
 	case *jww.Line:
+		opts.logf("%s -> LINE on layer %s", label, layerName)
 		return &Line{
-			Layer:    layerName,
-			Color:    color,
-			LineType: lineType,
-			X1:       v.StartX,
-			Y1:       v.StartY,
-			X2:       v.EndX,
-			Y2:       v.EndY,
+			Layer:         layerName,
+			Color:         color,
+			LineType:      lineType,
+			LineTypeScale: ltScale,
+			X1:            v.StartX,
+			Y1:            v.StartY,
+			X2:            v.EndX,
+			Y2:            v.EndY,
 		}
 
 	case *jww.Arc:
-		if v.IsFullCircle && v.Flatness == 1.0 {
+		// A sweep of a full turn or more is drawn as a closed curve; treating
+		// it as an arc would collapse to zero sweep once angles are normalized.
+		isFull := v.IsFullCircle || math.Abs(v.ArcAngle) >= 2*math.Pi
+
+		if isFull && v.Flatness == 1.0 {
 			// Full circle
+			opts.logf("%s -> CIRCLE: full circle with flatness 1", label)
 			return &Circle{
-				Layer:    layerName,
-				Color:    color,
-				LineType: lineType,
-				CenterX:  v.CenterX,
-				CenterY:  v.CenterY,
-				Radius:   v.Radius,
+				Layer:         layerName,
+				Color:         color,
+				LineType:      lineType,
+				LineTypeScale: ltScale,
+				CenterX:       v.CenterX,
+				CenterY:       v.CenterY,
+				Radius:        v.Radius,
 			}
 		} else if v.Flatness != 1.0 {
 			// Ellipse or elliptical arc
@@ -130,6 +1026,9 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 				majorRadius = v.Radius * v.Flatness
 				minorRatio = 1.0 / v.Flatness
 				tiltAngle = v.TiltAngle + math.Pi/2
+				opts.logf("%s -> ELLIPSE: flatness %g > 1, axes swapped", label, v.Flatness)
+			} else {
+				opts.logf("%s -> ELLIPSE: flatness %g", label, v.Flatness)
 			}
 
 			// Major axis endpoint relative to center
@@ -138,43 +1037,76 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 
 			startParam := v.StartAngle
 			endParam := v.StartAngle + v.ArcAngle
-			if v.IsFullCircle {
+			if v.ArcAngle < 0 {
+				// Clockwise sweep: DXF ellipses run counter-clockwise
+				startParam, endParam = endParam, startParam
+			}
+			if isFull {
 				startParam = 0
 				endParam = 2 * math.Pi
 			}
 
 			return &Ellipse{
-				Layer:      layerName,
-				Color:      color,
-				LineType:   lineType,
-				CenterX:    v.CenterX,
-				CenterY:    v.CenterY,
-				MajorAxisX: majorAxisX,
-				MajorAxisY: majorAxisY,
-				MinorRatio: minorRatio,
-				StartParam: startParam,
-				EndParam:   endParam,
+				Layer:         layerName,
+				Color:         color,
+				LineType:      lineType,
+				LineTypeScale: ltScale,
+				CenterX:       v.CenterX,
+				CenterY:       v.CenterY,
+				MajorAxisX:    majorAxisX,
+				MajorAxisY:    majorAxisY,
+				MinorRatio:    minorRatio,
+				StartParam:    startParam,
+				EndParam:      endParam,
 			}
 		} else {
 			// Arc
-			startAngle := radToDeg(v.StartAngle)
-			endAngle := radToDeg(v.StartAngle + v.ArcAngle)
+			startAngle, endAngle := arcDegrees(v.StartAngle, v.ArcAngle)
+			opts.logf("%s -> ARC: %g° to %g°", label, startAngle, endAngle)
 
 			return &Arc{
-				Layer:      layerName,
-				Color:      color,
-				LineType:   lineType,
-				CenterX:    v.CenterX,
-				CenterY:    v.CenterY,
-				Radius:     v.Radius,
-				StartAngle: startAngle,
-				EndAngle:   endAngle,
+				Layer:         layerName,
+				Color:         color,
+				LineType:      lineType,
+				LineTypeScale: ltScale,
+				CenterX:       v.CenterX,
+				CenterY:       v.CenterY,
+				Radius:        v.Radius,
+				StartAngle:    startAngle,
+				EndAngle:      endAngle,
 			}
 		}
 
 	case *jww.Point:
+		if v.Code != 0 && !v.IsTemporary {
+			blockName := markerBlockName(v.Code)
+			opts.logf("%s -> INSERT of marker block %s", label, blockName)
+			scale := v.Scale
+			if scale == 0 {
+				scale = 1.0
+			}
+			return &Insert{
+				Layer:     layerName,
+				Color:     color,
+				LineType:  lineType,
+				BlockName: blockName,
+				X:         v.X,
+				Y:         v.Y,
+				ScaleX:    scale,
+				ScaleY:    scale,
+				Rotation:  radToDeg(v.Angle),
+			}
+		}
+
 		if v.IsTemporary {
-			return nil // Skip temporary points
+			if !opts.IncludeTemporaryPoints {
+				opts.logf("%s skipped: temporary point", label)
+				return nil // Skip temporary points
+			}
+			layerName = TemporaryPointLayer
+			opts.logf("%s -> POINT: temporary point on layer %s", label, layerName)
+		} else {
+			opts.logf("%s -> POINT", label)
 		}
 		return &Point{
 			Layer:    layerName,
@@ -185,12 +1117,31 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 		}
 
 	case *jww.Text:
+		// Image data strings are placed as IMAGE entities
+		if img, ok := v.Image(); ok {
+			opts.logf("%s -> IMAGE: image data string for %s", label, img.Path)
+			return &Image{
+				Layer:    layerName,
+				Color:    color,
+				LineType: lineType,
+				X:        v.StartX,
+				Y:        v.StartY,
+				Width:    img.Width,
+				Height:   img.Height,
+				Rotation: v.Angle,
+				FilePath: img.Path,
+			}
+		}
+
 		// Use default height if SizeY is not set or too small
-		height := v.SizeY
+		height := opts.paperSize(doc, base.LayerGroup, v.SizeY)
 		if height <= 0 {
-			height = 2.5 // Default text height (same as NewText builder)
+			height = opts.textHeight(doc, base.LayerGroup)
+			opts.logf("%s -> TEXT: height %g not set, using default %g", label, v.SizeY, height)
+		} else {
+			opts.logf("%s -> TEXT", label)
 		}
-		return &Text{
+		text := &Text{
 			Layer:    layerName,
 			Color:    color,
 			LineType: lineType,
@@ -199,11 +1150,24 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 			Height:   height,
 			Rotation: v.Angle,
 			Content:  v.Content,
-			Style:    "STANDARD",
+			Style:    TextStyleName(v.FontName),
 		}
+		alignText(text, v, opts.TextAlignment)
+		return text
 
 	case *jww.Solid:
-		return &Solid{
+		if cs, ok := v.CircleSolid(); ok {
+			boundaries := circleSolidBoundaries(cs)
+			opts.logf("%s -> HATCH: circle solid (pen style %d)", label, v.PenStyle)
+			return &Hatch{
+				Layer:      layerName,
+				Color:      color,
+				LineType:   lineType,
+				Boundaries: boundaries,
+			}
+		}
+
+		solid := &Solid{
 			Layer:    layerName,
 			Color:    color,
 			LineType: lineType,
@@ -216,29 +1180,39 @@ func convertEntity(e jww.Entity, doc *jww.Document) Entity {
 			X4:       v.Point4X,
 			Y4:       v.Point4Y,
 		}
+		if solid.selfIntersecting() && !solid.IsTriangle() {
+			opts.logf("%s -> SOLID: corners reordered to avoid a bowtie", label)
+			return solid.NormalizeWinding()
+		}
+		opts.logf("%s -> SOLID", label)
+		return solid
 
 	case *jww.Block:
 		blockName := getBlockName(doc, v.DefNumber)
+		opts.logf("%s -> INSERT of block %s", label, blockName)
+		attrs := convertAttributes(opts.insertAttributes(v), v.RefX, v.RefY, v.Rotation, v.ScaleY*attributeHeight)
 		return &Insert{
-			Layer:     layerName,
-			Color:     color,
-			LineType:  lineType,
-			BlockName: blockName,
-			X:         v.RefX,
-			Y:         v.RefY,
-			ScaleX:    v.ScaleX,
-			ScaleY:    v.ScaleY,
-			Rotation:  radToDeg(v.Rotation),
+			Layer:      layerName,
+			Color:      color,
+			LineType:   lineType,
+			BlockName:  blockName,
+			X:          v.RefX,
+			Y:          v.RefY,
+			ScaleX:     v.ScaleX,
+			ScaleY:     v.ScaleY,
+			Rotation:   radToDeg(v.Rotation),
+			Attributes: attrs,
 		}
 	}
 
+	opts.logf("%s skipped: unsupported entity type", label)
 	return nil
 }
 
 // convertBlocks converts JWW block definitions to DXF blocks.
 // Each JWW block definition is converted to a DXF block with all its
 // entities converted to DXF equivalents.
-func convertBlocks(doc *jww.Document) []Block {
+func convertBlocks(doc *jww.Document, opts ConvertOptions) []Block {
 	var blocks []Block
 
 	for _, bd := range doc.BlockDefs {
@@ -248,12 +1222,11 @@ func convertBlocks(doc *jww.Document) []Block {
 			BaseY: 0,
 		}
 
-		for _, e := range bd.Entities {
-			dxfEntity := convertEntity(e, doc)
-			if dxfEntity != nil {
-				block.Entities = append(block.Entities, dxfEntity)
-			}
+		for i, e := range bd.Entities {
+			converted := convertEntityWithHook(e, doc, opts, fmt.Sprintf("%s/%d", bd.Name, i))
+			block.Entities = append(block.Entities, converted...)
 		}
+		block.Attributes = convertAttributes(blockAttributes(doc, bd.Number, opts), 0, 0, 0, attributeHeight)
 
 		blocks = append(blocks, block)
 	}
@@ -261,6 +1234,136 @@ func convertBlocks(doc *jww.Document) []Block {
 	return blocks
 }
 
+// attributeHeight is the text height of block attributes at scale 1.
+// JWW stores no attribute formatting, so a common drawing text size is used.
+const attributeHeight = 2.5
+
+// convertAttributes places the tags and values of attrs as attributes with
+// text of the given height, stacked downward from (x, y) one line apart
+// in the direction of rotation (in radians). Spaces in tags, which DXF
+// does not allow, become underscores.
+func convertAttributes(attrs []Attribute, x, y, rotation, height float64) []Attribute {
+	var converted []Attribute
+	step := math.Abs(height) * TextLineSpacing
+	sin, cos := math.Sincos(rotation)
+	for i, a := range attrs {
+		offset := step * float64(i)
+		converted = append(converted, Attribute{
+			Tag:      strings.ReplaceAll(a.Tag, " ", "_"),
+			Value:    a.Value,
+			X:        x + offset*sin,
+			Y:        y - offset*cos,
+			Height:   math.Abs(height),
+			Rotation: radToDeg(rotation),
+		})
+	}
+	return converted
+}
+
+// blockAttributes returns the attribute tags ConvertOptions.InsertAttributes
+// gives inserts of block definition number, in first-use order, with empty
+// default values.
+func blockAttributes(doc *jww.Document, number uint32, opts ConvertOptions) []Attribute {
+	if opts.InsertAttributes == nil {
+		return nil
+	}
+	var attrs []Attribute
+	seen := make(map[string]bool)
+	collect := func(entities []jww.Entity) {
+		for _, e := range entities {
+			b, ok := e.(*jww.Block)
+			if !ok || b.DefNumber != number {
+				continue
+			}
+			for _, a := range opts.InsertAttributes(b) {
+				if !seen[a.Tag] {
+					seen[a.Tag] = true
+					attrs = append(attrs, Attribute{Tag: a.Tag})
+				}
+			}
+		}
+	}
+	collect(doc.Entities)
+	for _, bd := range doc.BlockDefs {
+		collect(bd.Entities)
+	}
+	return attrs
+}
+
+// markerBlockPrefix starts the names of the blocks that draw point markers.
+const markerBlockPrefix = "JWW_MARKER_"
+
+// markerSize is the half width of a marker symbol at scale 1.
+const markerSize = 1.0
+
+// markerBlockName returns the name of the block drawing point marker code.
+// Each code gets its own block so the symbols can be redefined in CAD
+// software; the format notes do not describe the marker shapes.
+func markerBlockName(code uint32) string {
+	return fmt.Sprintf("%s%d", markerBlockPrefix, code)
+}
+
+// pointModeX is the $PDMODE value drawing points as an X.
+const pointModeX = 3
+
+// hasStyledPoints reports whether doc has marker points, or temporary points
+// that are converted. Such drawings display POINT entities as an X of
+// marker size instead of a single dot, so the points stay visible.
+func hasStyledPoints(doc *jww.Document, opts ConvertOptions) bool {
+	for _, p := range jww.EntitiesOfType[*jww.Point](doc) {
+		if p.Code != 0 || (p.IsTemporary && opts.IncludeTemporaryPoints) {
+			return true
+		}
+	}
+	return false
+}
+
+// markerBlocks returns a block definition for every marker block referenced
+// by the document's entities that is not already defined. Markers are drawn
+// as a cross of two lines through the insertion point.
+func markerBlocks(doc *Document) []Block {
+	var blocks []Block
+	defined := make(map[string]bool)
+	for i := range doc.Blocks {
+		defined[doc.Blocks[i].Name] = true
+	}
+
+	addMarkers := func(entities []Entity) {
+		for _, e := range entities {
+			insert, ok := e.(*Insert)
+			if !ok || !strings.HasPrefix(insert.BlockName, markerBlockPrefix) || defined[insert.BlockName] {
+				continue
+			}
+			defined[insert.BlockName] = true
+			blocks = append(blocks, Block{
+				Name: insert.BlockName,
+				Entities: []Entity{
+					&Line{Layer: "0", LineType: "CONTINUOUS", X1: -markerSize, X2: markerSize},
+					&Line{Layer: "0", LineType: "CONTINUOUS", Y1: -markerSize, Y2: markerSize},
+				},
+			})
+		}
+	}
+	addMarkers(doc.Entities)
+	for i := range doc.Blocks {
+		addMarkers(doc.Blocks[i].Entities)
+	}
+	return blocks
+}
+
+// groupScale returns the scale denominator of the given layer group, or 1
+// when the group is out of range or has no usable scale.
+func groupScale(doc *jww.Document, layerGroup uint16) float64 {
+	if int(layerGroup) >= len(doc.LayerGroups) {
+		return 1
+	}
+	scale := doc.LayerGroups[layerGroup].Scale
+	if scale <= 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		return 1
+	}
+	return scale
+}
+
 // getLayerName returns the DXF layer name for a given JWW layer group and layer.
 // If the layer has a custom name, it is used. Otherwise, a default name
 // in the format "G-L" (e.g., "0-0", "F-A") is generated using hexadecimal notation.
@@ -275,6 +1378,59 @@ func getLayerName(doc *jww.Document, layerGroup, layer uint16) string {
 	return fmt.Sprintf("%X-%X", layerGroup, layer)
 }
 
+// addExtentsRectangle appends a rectangle tracing the extents of doc on
+// ExtentsLayer, adding the layer.
+func addExtentsRectangle(doc *Document) {
+	minX, minY, maxX, maxY, ok := doc.extents()
+	if !ok {
+		return
+	}
+	doc.Layers = append(doc.Layers, Layer{Name: ExtentsLayer, Color: 7, LineType: "CONTINUOUS"})
+	doc.Entities = append(doc.Entities, &LWPolyline{
+		Layer:    ExtentsLayer,
+		Vertices: []Vertex{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}},
+		Closed:   true,
+	})
+}
+
+// alignText justifies t by align, placing its alignment point on the
+// baseline of the JWW text v. It leaves t left-justified if v has no end
+// point or align is unknown.
+func alignText(t *Text, v *jww.Text, align TextHAlign) {
+	if align == TextHAlignLeft || v.EndX == v.StartX && v.EndY == v.StartY {
+		return
+	}
+	midX, midY := (v.StartX+v.EndX)/2, (v.StartY+v.EndY)/2
+	switch align {
+	case TextHAlignCenter:
+		t.AlignX, t.AlignY = midX, midY
+	case TextHAlignMiddle:
+		// Half the height above the baseline midpoint
+		sin, cos := math.Sincos(t.Rotation * math.Pi / 180.0)
+		t.AlignX, t.AlignY = midX-t.Height/2*sin, midY+t.Height/2*cos
+	case TextHAlignRight, TextHAlignAligned, TextHAlignFit:
+		t.AlignX, t.AlignY = v.EndX, v.EndY
+	default:
+		return
+	}
+	t.HAlign = align
+}
+
+// currentLayer returns the name of the JWW write layer, the layer of the
+// write layer group that was active when the file was saved, or "" if it
+// is out of range or not among layers (e.g. pruned as unused).
+func currentLayer(doc *jww.Document, layers []Layer) string {
+	group := doc.WriteLayerGroup
+	if group >= 16 || doc.LayerGroups[group].WriteLayer >= 16 {
+		return ""
+	}
+	name := getLayerName(doc, uint16(group), uint16(doc.LayerGroups[group].WriteLayer))
+	if !slices.ContainsFunc(layers, func(l Layer) bool { return l.Name == name }) {
+		return ""
+	}
+	return name
+}
+
 // getBlockName returns the block name for a given JWW block definition number.
 // If the block has a custom name, it is used. Otherwise, a default name
 // like "BLOCK_1" is generated.
@@ -351,7 +1507,8 @@ func mapColor(jwwColor uint16) int {
 //   - 5: dotted (点線)
 //   - 6-9: double-length variants of 2-5
 //
-// Extended values and unknown styles fall back to CONTINUOUS.
+// Extended values and unknown styles fall back to CONTINUOUS; see
+// lineTypeName for the user-defined line types of a document.
 func mapLineType(penStyle byte) string {
 	switch penStyle {
 	case 0, 1:
@@ -377,8 +1534,233 @@ func mapLineType(penStyle byte) string {
 	}
 }
 
+// lineTypeName maps a JWW pen style to a DXF linetype name like
+// mapLineType, but names the user-defined line types of doc after
+// CustomLineTypeName.
+func lineTypeName(doc *jww.Document, penStyle byte) string {
+	for _, def := range doc.LineTypes {
+		if def.PenStyle == penStyle {
+			return CustomLineTypeName(penStyle)
+		}
+	}
+	return mapLineType(penStyle)
+}
+
+// CustomLineTypeName returns the DXF linetype name of the JWW user-defined
+// line type with the given pen style, e.g. "JWW_LT31".
+func CustomLineTypeName(penStyle byte) string {
+	return fmt.Sprintf("JWW_LT%d", penStyle)
+}
+
+// convertLineTypes converts the user-defined line types of doc to DXF
+// linetypes, keeping the JWW name as description. Dash and gap lengths
+// stay in paper millimeters; entities scale them by their layer group's
+// scale denominator through their linetype scale.
+func convertLineTypes(doc *jww.Document) []LineType {
+	var lineTypes []LineType
+	for _, def := range doc.LineTypes {
+		pattern := make([]float64, len(def.Pattern))
+		for i, length := range def.Pattern {
+			if i%2 == 1 {
+				length = -length // gap
+			}
+			pattern[i] = length
+		}
+		lineTypes = append(lineTypes, LineType{
+			Name:        CustomLineTypeName(def.PenStyle),
+			Description: def.Name,
+			Pattern:     pattern,
+		})
+	}
+	return lineTypes
+}
+
+// jwwFonts maps common Jw_cad fonts, with full-width characters folded to
+// ASCII, to their DXF text style name and TrueType font file.
+var jwwFonts = map[string]struct{ style, file string }{
+	"MS ゴシック":  {"MS_GOTHIC", "msgothic.ttc"},
+	"MS Pゴシック": {"MS_PGOTHIC", "msgothic.ttc"},
+	"MS 明朝":    {"MS_MINCHO", "msmincho.ttc"},
+	"MS P明朝":   {"MS_PMINCHO", "msmincho.ttc"},
+}
+
+// TextStyleName returns the DXF text style name of texts in the JWW font
+// font: an ASCII name for common fonts, e.g. "MS_GOTHIC" for ＭＳ ゴシック,
+// the font name itself for other fonts, and "STANDARD" for no font.
+func TextStyleName(font string) string {
+	font = strings.TrimSpace(font)
+	if font == "" {
+		return "STANDARD"
+	}
+	if f, ok := jwwFonts[width.Fold.String(font)]; ok {
+		return f.style
+	}
+	return font
+}
+
+// textStyleFont returns the font file of the JWW font font: the TrueType
+// file of common fonts, and the font name itself for other fonts, which
+// CAD software resolves as best it can.
+func textStyleFont(font string) string {
+	font = strings.TrimSpace(font)
+	if f, ok := jwwFonts[width.Fold.String(font)]; ok {
+		return f.file
+	}
+	return font
+}
+
+// convertTextStyles returns a text style for each distinct font of the
+// texts in doc, including dimension and block texts, in order of first use.
+func convertTextStyles(doc *jww.Document) []TextStyle {
+	var styles []TextStyle
+	seen := map[string]bool{"STANDARD": true}
+	add := func(t *jww.Text) {
+		if _, ok := t.Image(); ok {
+			return
+		}
+		name := TextStyleName(t.FontName)
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		styles = append(styles, TextStyle{Name: name, Font: textStyleFont(t.FontName)})
+	}
+	visit := func(entities []jww.Entity) {
+		for _, e := range entities {
+			switch v := e.(type) {
+			case *jww.Text:
+				add(v)
+			case *jww.Dimension:
+				add(&v.Text)
+			}
+		}
+	}
+	visit(doc.Entities)
+	for _, bd := range doc.BlockDefs {
+		visit(bd.Entities)
+	}
+	return styles
+}
+
 // radToDeg converts an angle from radians to degrees.
 // This is used for converting JWW angle values (in radians) to DXF angle values (in degrees).
 func radToDeg(rad float64) float64 {
 	return rad * 180.0 / math.Pi
 }
+
+// normalizeDeg wraps an angle in degrees into the range [0, 360).
+func normalizeDeg(a float64) float64 {
+	a = math.Mod(a, 360)
+	if a < 0 {
+		a += 360
+	}
+	if a >= 360 {
+		// Adding 360 to a tiny negative remainder can round up to 360.
+		a = 0
+	}
+	return a
+}
+
+// normalizeRad wraps an angle in radians into the range [0, 2π).
+func normalizeRad(a float64) float64 {
+	a = math.Mod(a, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	if a >= 2*math.Pi {
+		a = 0
+	}
+	return a
+}
+
+// arcDegrees converts a JWW arc start angle and sweep (radians) to DXF
+// counter-clockwise start and end angles in degrees, both in [0, 360).
+// A negative sweep runs clockwise, so its end becomes the DXF start.
+func arcDegrees(startRad, sweepRad float64) (start, end float64) {
+	if sweepRad < 0 {
+		startRad, sweepRad = startRad+sweepRad, -sweepRad
+	}
+	return normalizeDeg(radToDeg(startRad)), normalizeDeg(radToDeg(startRad + sweepRad))
+}
+
+// circleSolidSegments is the number of boundary segments used for a full
+// turn when approximating a circle solid.
+const circleSolidSegments = 72
+
+// circleSolidBoundaries returns the hatch boundary loops of a circle solid.
+func circleSolidBoundaries(cs jww.CircleSolid) [][]Vertex {
+	full := math.Abs(cs.ArcAngle) >= 2*math.Pi
+	outer := circleSolidArc(cs, cs.Radius, cs.Flatness, full)
+
+	switch cs.Kind {
+	case jww.CircleSolidSector:
+		if full {
+			return [][]Vertex{outer}
+		}
+		return [][]Vertex{append([]Vertex{{X: cs.CenterX, Y: cs.CenterY}}, outer...)}
+
+	case jww.CircleSolidOuterArc:
+		// The tangents at the arc ends meet on the bisector at radius/cos(half).
+		// Jw_cad limits outer arc solids to a quarter turn, so cos(half) > 0.
+		half := cs.ArcAngle / 2
+		if full || math.Cos(half) < 1e-9 {
+			return [][]Vertex{outer}
+		}
+		corner := circleSolidPoint(cs, cs.Radius/math.Cos(half), cs.Flatness, cs.StartAngle+half)
+		return [][]Vertex{append(outer, corner)}
+
+	case jww.CircleSolidAnnulus:
+		if cs.InnerRadius <= 0 || cs.InnerRadius >= cs.Radius {
+			return [][]Vertex{outer}
+		}
+		innerFlatness := cs.Flatness
+		if cs.EqualWidth {
+			// Keep the ring width on the minor axis equal to the major axis width
+			innerFlatness = (cs.Radius*cs.Flatness - (cs.Radius - cs.InnerRadius)) / cs.InnerRadius
+		}
+		inner := circleSolidArc(cs, cs.InnerRadius, innerFlatness, full)
+		if full {
+			return [][]Vertex{outer, inner}
+		}
+		for i, j := 0, len(inner)-1; i < j; i, j = i+1, j-1 {
+			inner[i], inner[j] = inner[j], inner[i]
+		}
+		return [][]Vertex{append(outer, inner...)}
+	}
+
+	// Full circles, segments, and circumference solids fill the area the
+	// curve encloses, closing partial arcs with their chord.
+	return [][]Vertex{outer}
+}
+
+// circleSolidArc samples the arc of a circle solid at the given radius and
+// flatness. Full turns omit the closing vertex.
+func circleSolidArc(cs jww.CircleSolid, radius, flatness float64, full bool) []Vertex {
+	segments := int(math.Ceil(math.Abs(cs.ArcAngle) / (2 * math.Pi) * circleSolidSegments))
+	if segments < 4 {
+		segments = 4
+	}
+	count := segments + 1
+	if full {
+		count = segments
+	}
+
+	vertices := make([]Vertex, count)
+	for i := range vertices {
+		t := cs.StartAngle + cs.ArcAngle*float64(i)/float64(segments)
+		vertices[i] = circleSolidPoint(cs, radius, flatness, t)
+	}
+	return vertices
+}
+
+// circleSolidPoint returns the point at parameter t of the ellipse with the
+// circle solid's center and tilt.
+func circleSolidPoint(cs jww.CircleSolid, radius, flatness, t float64) Vertex {
+	x := radius * math.Cos(t)
+	y := radius * flatness * math.Sin(t)
+	cos, sin := math.Cos(cs.TiltAngle), math.Sin(cs.TiltAngle)
+	return Vertex{
+		X: cs.CenterX + x*cos - y*sin,
+		Y: cs.CenterY + x*sin + y*cos,
+	}
+}
@@ -2,6 +2,10 @@ package dxf
 
 import (
 	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/f4ah6o/jww-parser/jww"
@@ -123,6 +127,82 @@ func TestConvertArc(t *testing.T) {
 	}
 }
 
+func TestNormalizeDeg(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{0, 0},
+		{90, 90},
+		{360, 0},
+		{450, 90},
+		{-90, 270},
+		{-720, 0},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeDeg(tt.in); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("normalizeDeg(%v): got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConvertArc_AngleWraparound(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     float64
+		sweep     float64
+		wantStart float64
+		wantEnd   float64
+	}{
+		{"negative sweep", math.Pi / 2, -math.Pi / 2, 0, 90},
+		{"negative start", -math.Pi / 2, math.Pi, 270, 90},
+		{"end past 360", 3 * math.Pi / 2, math.Pi, 270, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := createTestDocument()
+			doc.Entities = []jww.Entity{&jww.Arc{
+				Radius:     10,
+				StartAngle: tt.start,
+				ArcAngle:   tt.sweep,
+				Flatness:   1.0,
+			}}
+
+			result := ConvertDocument(doc)
+
+			dxfArc, ok := result.Entities[0].(*Arc)
+			if !ok {
+				t.Fatalf("expected *Arc, got %T", result.Entities[0])
+			}
+			if math.Abs(dxfArc.StartAngle-tt.wantStart) > 0.001 {
+				t.Errorf("startAngle: got %v, want %v", dxfArc.StartAngle, tt.wantStart)
+			}
+			if math.Abs(dxfArc.EndAngle-tt.wantEnd) > 0.001 {
+				t.Errorf("endAngle: got %v, want %v", dxfArc.EndAngle, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestConvertArc_FullSweep(t *testing.T) {
+	for _, sweep := range []float64{2 * math.Pi, 3 * math.Pi, -2 * math.Pi} {
+		doc := createTestDocument()
+		doc.Entities = []jww.Entity{&jww.Arc{
+			Radius:     10,
+			StartAngle: math.Pi / 4,
+			ArcAngle:   sweep,
+			Flatness:   1.0,
+		}}
+
+		result := ConvertDocument(doc)
+
+		if _, ok := result.Entities[0].(*Circle); !ok {
+			t.Errorf("sweep %v: expected *Circle, got %T", sweep, result.Entities[0])
+		}
+	}
+}
+
 func TestMapLineType(t *testing.T) {
 	cases := []struct {
 		penStyle byte
@@ -236,6 +316,87 @@ func TestConvertPoint_Temporary(t *testing.T) {
 	}
 }
 
+func TestConvertPoint_IncludeTemporaryPoints(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Point{EntityBase: jww.EntityBase{PenColor: 1}, X: 25, Y: 75, IsTemporary: true},
+		&jww.Point{EntityBase: jww.EntityBase{PenColor: 1}, X: 10, Y: 20},
+	}
+
+	result := ConvertDocument(doc)
+	if len(result.Entities) != 1 || result.HasLayer(TemporaryPointLayer) {
+		t.Fatalf("default: got %d entities, want only the permanent point", len(result.Entities))
+	}
+
+	result = ConvertDocumentWithOptions(doc, ConvertOptions{IncludeTemporaryPoints: true})
+	if len(result.Entities) != 2 {
+		t.Fatalf("expected 2 entities, got %d", len(result.Entities))
+	}
+	if temp := result.Entities[0].(*Point); temp.Layer != TemporaryPointLayer || temp.X != 25 {
+		t.Errorf("temporary point: got layer %q at x %v, want %q", temp.Layer, temp.X, TemporaryPointLayer)
+	}
+	if perm := result.Entities[1].(*Point); perm.Layer == TemporaryPointLayer {
+		t.Errorf("permanent point moved to %q", perm.Layer)
+	}
+	if !result.HasLayer(TemporaryPointLayer) {
+		t.Errorf("layer %q not defined", TemporaryPointLayer)
+	}
+}
+
+func TestConvertPoint_Marker(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Point{EntityBase: jww.EntityBase{PenStyle: 100, PenColor: 1}, X: 5, Y: 6, Code: 3, Angle: math.Pi / 2, Scale: 2},
+		&jww.Point{EntityBase: jww.EntityBase{PenStyle: 100, PenColor: 1}, X: 7, Y: 8, Code: 3},
+	}
+
+	result := ConvertDocument(doc)
+
+	insert, ok := result.Entities[0].(*Insert)
+	if !ok {
+		t.Fatalf("expected *Insert, got %T", result.Entities[0])
+	}
+	if insert.BlockName != "JWW_MARKER_3" || insert.X != 5 || insert.Y != 6 {
+		t.Errorf("insert: got %q at (%v, %v)", insert.BlockName, insert.X, insert.Y)
+	}
+	if insert.ScaleX != 2 || insert.ScaleY != 2 || insert.Rotation != 90 {
+		t.Errorf("insert transform: got scale (%v, %v) rotation %v", insert.ScaleX, insert.ScaleY, insert.Rotation)
+	}
+	if unscaled := result.Entities[1].(*Insert); unscaled.ScaleX != 1 {
+		t.Errorf("scale 0 should insert at scale 1, got %v", unscaled.ScaleX)
+	}
+
+	if len(result.Blocks) != 1 {
+		t.Fatalf("expected 1 marker block, got %d", len(result.Blocks))
+	}
+	cross := result.GetBlock("JWW_MARKER_3")
+	if cross == nil || len(cross.Entities) != 2 {
+		t.Errorf("marker block should be a cross of two lines, got %+v", cross)
+	}
+	if issues := Validate(result); len(issues) != 0 {
+		t.Errorf("unexpected validation issues: %v", issues)
+	}
+}
+
+func TestConvertPoint_DisplayMode(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Point{EntityBase: jww.EntityBase{PenColor: 1}, X: 1, Y: 2}}
+
+	if result := ConvertDocument(doc); result.PointMode != 0 || result.PointSize != 0 {
+		t.Errorf("plain points: got $PDMODE %d $PDSIZE %v, want defaults", result.PointMode, result.PointSize)
+	}
+
+	doc.Entities = append(doc.Entities,
+		&jww.Point{EntityBase: jww.EntityBase{PenStyle: 100, PenColor: 1}, X: 5, Y: 6, Code: 3})
+	result := ConvertDocument(doc)
+	if result.PointMode != 3 || result.PointSize != markerSize {
+		t.Errorf("marker points: got $PDMODE %d $PDSIZE %v, want 3 and %v", result.PointMode, result.PointSize, markerSize)
+	}
+	if output := ToString(result); !strings.Contains(output, "$PDMODE\n 70\n3\n") {
+		t.Error("expected $PDMODE 3 in the DXF header")
+	}
+}
+
 func TestConvertText(t *testing.T) {
 	txt := &jww.Text{
 		EntityBase: jww.EntityBase{
@@ -308,6 +469,146 @@ func TestConvertTextWithZeroHeight(t *testing.T) {
 	}
 }
 
+func TestConvertText_Alignment(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Text{StartX: 10, StartY: 20, EndX: 30, EndY: 20, SizeY: 4, Content: "ABCD"},
+	}
+
+	tests := []struct {
+		name           string
+		align          TextHAlign
+		alignX, alignY float64
+		codes          string
+	}{
+		{"left", TextHAlignLeft, 0, 0, ""},
+		{"center", TextHAlignCenter, 20, 20, " 72\n1\n 11\n20.0\n 21\n20.0\n 31\n0.0\n 73\n0\n"},
+		{"right", TextHAlignRight, 30, 20, " 72\n2\n 11\n30.0\n 21\n20.0\n 31\n0.0\n 73\n0\n"},
+		{"middle", TextHAlignMiddle, 20, 22, " 72\n4\n 11\n20.0\n 21\n22.0\n 31\n0.0\n 73\n0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ConvertDocumentWithOptions(doc, ConvertOptions{TextAlignment: tt.align})
+			text := result.Entities[0].(*Text)
+			if text.HAlign != tt.align || text.AlignX != tt.alignX || text.AlignY != tt.alignY {
+				t.Errorf("got %v at (%v, %v), want %v at (%v, %v)",
+					text.HAlign, text.AlignX, text.AlignY, tt.align, tt.alignX, tt.alignY)
+			}
+			if text.X != 10 || text.Y != 20 {
+				t.Errorf("insertion point: got (%v, %v), want (10, 20)", text.X, text.Y)
+			}
+
+			output := ToStringWithOptions(result, WriteOptions{Precision: 1})
+			if tt.codes == "" {
+				if strings.Contains(output, "ABCD\n  7\nSTANDARD\n 72\n") {
+					t.Error("Expected no justification codes for left-justified text")
+				}
+			} else if !strings.Contains(output, tt.codes) {
+				t.Errorf("Expected output to contain %q", tt.codes)
+			}
+		})
+	}
+
+	// Without an end point the text stays left-justified
+	doc.Entities = []jww.Entity{&jww.Text{StartX: 10, StartY: 20, EndX: 10, EndY: 20, Content: "A"}}
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{TextAlignment: TextHAlignRight})
+	if text := result.Entities[0].(*Text); text.HAlign != TextHAlignLeft {
+		t.Errorf("expected left justification without an end point, got %v", text.HAlign)
+	}
+}
+
+func TestConvertPaperSizesToWorld(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[1].Scale = 100
+	onGroup1 := jww.EntityBase{LayerGroup: 1}
+	doc.Entities = []jww.Entity{
+		// A 5 mm (図寸) text labelling a 1000 mm (実寸) wall
+		&jww.Text{EntityBase: onGroup1, StartX: 0, StartY: 100, EndX: 500, EndY: 100, SizeX: 5, SizeY: 5, Content: "WALL"},
+		&jww.Line{EntityBase: onGroup1, EndX: 1000},
+		&jww.Text{EntityBase: onGroup1, Content: "no height"},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{PaperSizesToWorld: true})
+
+	if text := result.Entities[0].(*Text); text.Height != 500 || text.X != 0 || text.Y != 100 {
+		t.Errorf("text: got height %v at (%v, %v), want 500 at (0, 100)", text.Height, text.X, text.Y)
+	}
+	if line := result.Entities[1].(*Line); line.X2 != 1000 {
+		t.Errorf("line: got end X %v, want 1000", line.X2)
+	}
+	if text := result.Entities[2].(*Text); text.Height != DefaultTextHeight*100 {
+		t.Errorf("default height: got %v, want %v", text.Height, DefaultTextHeight*100)
+	}
+
+	if plain := ConvertDocument(doc).Entities[0].(*Text); plain.Height != 5 {
+		t.Errorf("without the option: got height %v, want 5", plain.Height)
+	}
+}
+
+func TestConvertText_DefaultTextHeight(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[1].Scale = 100
+	doc.Entities = []jww.Entity{
+		&jww.Text{Content: "A"},
+		&jww.Text{EntityBase: jww.EntityBase{LayerGroup: 1}, Content: "B"},
+		&jww.Text{SizeY: 4, Content: "C"},
+	}
+
+	tests := []struct {
+		name string
+		opts ConvertOptions
+		want [3]float64
+	}{
+		{"default", ConvertOptions{}, [3]float64{2.5, 2.5, 4}},
+		{"configured", ConvertOptions{DefaultTextHeight: 3.5}, [3]float64{3.5, 3.5, 4}},
+		{"scaled", ConvertOptions{DefaultTextHeight: 3.5, ScaleDefaultTextHeight: true}, [3]float64{3.5, 350, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ConvertDocumentWithOptions(doc, tt.opts)
+			for i, want := range tt.want {
+				if got := result.Entities[i].(*Text).Height; got != want {
+					t.Errorf("text %d: got height %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertText_Image(t *testing.T) {
+	txt := &jww.Text{
+		EntityBase: jww.EntityBase{PenColor: 1},
+		StartX:     10,
+		StartY:     20,
+		Angle:      30,
+		Content:    "^@BM%temp%logo.bmp,100,50,0,0,1,0",
+	}
+
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{txt}
+
+	result := ConvertDocument(doc)
+
+	img, ok := result.Entities[0].(*Image)
+	if !ok {
+		t.Fatalf("expected *Image, got %T", result.Entities[0])
+	}
+	if img.X != 10 || img.Y != 20 {
+		t.Errorf("position: got (%v, %v), want (10, 20)", img.X, img.Y)
+	}
+	if img.Width != 100 || img.Height != 50 {
+		t.Errorf("size: got (%v, %v), want (100, 50)", img.Width, img.Height)
+	}
+	if img.Rotation != 30 {
+		t.Errorf("rotation: got %v, want 30", img.Rotation)
+	}
+	if img.FilePath != "%temp%logo.bmp" {
+		t.Errorf("file path: got %q, want %q", img.FilePath, "%temp%logo.bmp")
+	}
+}
+
 func TestConvertSolid(t *testing.T) {
 	solid := &jww.Solid{
 		EntityBase: jww.EntityBase{
@@ -340,6 +641,67 @@ func TestConvertSolid(t *testing.T) {
 	}
 }
 
+func TestConvertCircleSolid(t *testing.T) {
+	full := &jww.Solid{
+		EntityBase: jww.EntityBase{PenStyle: 101, PenColor: 1},
+		Point1X:    10, Point1Y: 20, // center
+		Point4X: 5, Point4Y: 1, // radius, flatness
+		Point3X: 2 * math.Pi, Point3Y: 100, // full circle
+	}
+	annulus := &jww.Solid{
+		EntityBase: jww.EntityBase{PenStyle: 105, PenColor: 1},
+		Point4X:    5, Point4Y: 1,
+		Point3X: 2 * math.Pi, Point3Y: 3, // inner radius
+	}
+	sector := &jww.Solid{
+		EntityBase: jww.EntityBase{PenStyle: 101, PenColor: 1},
+		Point4X:    5, Point4Y: 1,
+		Point3X: math.Pi / 2, Point3Y: 0,
+	}
+
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{full, annulus, sector}
+
+	result := ConvertDocument(doc)
+
+	hatch, ok := result.Entities[0].(*Hatch)
+	if !ok {
+		t.Fatalf("expected *Hatch, got %T", result.Entities[0])
+	}
+	if len(hatch.Boundaries) != 1 || len(hatch.Boundaries[0]) != circleSolidSegments {
+		t.Fatalf("full circle boundaries: got %d loops", len(hatch.Boundaries))
+	}
+	for _, v := range hatch.Boundaries[0] {
+		if r := math.Hypot(v.X-10, v.Y-20); math.Abs(r-5) > 1e-9 {
+			t.Fatalf("boundary vertex (%v, %v) is %v from the center, want 5", v.X, v.Y, r)
+		}
+	}
+	minX, minY, maxX, maxY := hatch.BoundingBox()
+	if minX != 5 || maxX != 15 || math.Abs(minY-15) > 1e-9 || math.Abs(maxY-25) > 1e-9 {
+		t.Errorf("bounding box: got (%v, %v)-(%v, %v)", minX, minY, maxX, maxY)
+	}
+
+	ring := result.Entities[1].(*Hatch)
+	if len(ring.Boundaries) != 2 {
+		t.Fatalf("annulus: got %d loops, want 2", len(ring.Boundaries))
+	}
+	if r := math.Hypot(ring.Boundaries[1][0].X, ring.Boundaries[1][0].Y); math.Abs(r-3) > 1e-9 {
+		t.Errorf("annulus inner radius: got %v, want 3", r)
+	}
+
+	pie := result.Entities[2].(*Hatch)
+	if v := pie.Boundaries[0][0]; v.X != 0 || v.Y != 0 {
+		t.Errorf("sector should start at the center, got (%v, %v)", v.X, v.Y)
+	}
+
+	if counts := result.CountByType(); counts["HATCH"] != 3 || counts["SOLID"] != 0 {
+		t.Errorf("entity types: got %v, want 3 HATCH", counts)
+	}
+	if output := ToString(result); !strings.Contains(output, "HATCH") {
+		t.Error("expected HATCH in the DXF output")
+	}
+}
+
 func TestConvertBlock(t *testing.T) {
 	block := &jww.Block{
 		EntityBase: jww.EntityBase{
@@ -387,6 +749,55 @@ func TestConvertBlock(t *testing.T) {
 	}
 }
 
+func TestConvertBlock_Attributes(t *testing.T) {
+	doc := createTestDocument()
+	doc.BlockDefs = []jww.BlockDef{{Number: 1, Name: "TitleBlock"}}
+	doc.Entities = []jww.Entity{&jww.Block{
+		RefX: 100, RefY: 50, ScaleX: 1, ScaleY: 1,
+		DefNumber: 1,
+	}}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{
+		InsertAttributes: func(b *jww.Block) []Attribute {
+			return []Attribute{{Tag: "DRAWING TITLE", Value: "配置図"}}
+		},
+	})
+
+	insert := result.Entities[0].(*Insert)
+	if len(insert.Attributes) != 1 {
+		t.Fatalf("expected 1 attribute, got %d", len(insert.Attributes))
+	}
+	attr := insert.Attributes[0]
+	if attr.Tag != "DRAWING_TITLE" || attr.Value != "配置図" {
+		t.Errorf("attribute: got %q = %q, want DRAWING_TITLE = 配置図", attr.Tag, attr.Value)
+	}
+	if attr.X != 100 || attr.Y != 50 || attr.Height != attributeHeight {
+		t.Errorf("attribute position: got (%v, %v) height %v", attr.X, attr.Y, attr.Height)
+	}
+
+	defs := result.Blocks[0].Attributes
+	if len(defs) != 1 || defs[0].Tag != "DRAWING_TITLE" {
+		t.Fatalf("expected an ATTDEF for DRAWING_TITLE, got %+v", defs)
+	}
+
+	output := ToString(result)
+	wantInsert := "  0\nINSERT\n"
+	wantAttrib := " 66\n1\n  0\nATTRIB\n"
+	wantTag := "  2\nDRAWING_TITLE\n 70\n0\n  0\nSEQEND\n"
+	i := strings.Index(output, wantInsert)
+	j := strings.Index(output, wantAttrib)
+	k := strings.Index(output, wantTag)
+	if i < 0 || j < i || k < j {
+		t.Errorf("expected INSERT, then 66=1 and an ATTRIB, then its tag and SEQEND")
+	}
+	if !strings.Contains(output, `\U+914D\U+7F6E\U+56F3`) {
+		t.Errorf("expected the attribute value in the output")
+	}
+	if !strings.Contains(output, "  0\nATTDEF\n") {
+		t.Errorf("expected an ATTDEF in the block definition")
+	}
+}
+
 func TestMapColor(t *testing.T) {
 	tests := []struct {
 		jwwColor uint16
@@ -428,6 +839,28 @@ func TestConvertLayers(t *testing.T) {
 	}
 }
 
+func TestConvertLayers_GroupInferredPens(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[1].InferredPenColor = 8 // JWW red
+	doc.LayerGroups[1].InferredPenStyle = 2 // dashed
+
+	result := ConvertDocument(doc)
+
+	// Group 1 layers follow group 0's 16 layers
+	layer := result.Layers[16]
+	if layer.Color != 1 {
+		t.Errorf("group inferred color: got %d, want 1 (red)", layer.Color)
+	}
+	if layer.LineType != "DASHED" {
+		t.Errorf("group inferred linetype: got %s, want DASHED", layer.LineType)
+	}
+
+	// Groups without entities fall back to foreground color
+	if result.Layers[32].Color != 7 {
+		t.Errorf("empty group color: got %d, want 7", result.Layers[32].Color)
+	}
+}
+
 func TestConvertBlocks(t *testing.T) {
 	line := &jww.Line{
 		EntityBase: jww.EntityBase{PenColor: 1},
@@ -460,6 +893,172 @@ func TestConvertBlocks(t *testing.T) {
 	}
 }
 
+func TestConvertDocumentWithOptions_Logger(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EndX: 10},
+		&jww.Arc{Radius: 10, ArcAngle: math.Pi, Flatness: 0.5},
+		&jww.Point{X: 1, Y: 1, IsTemporary: true},
+	}
+
+	var log strings.Builder
+	ConvertDocumentWithOptions(doc, ConvertOptions{Logger: &log})
+
+	for _, want := range []string{
+		"line 0 -> LINE",
+		"arc 1 -> ELLIPSE: flatness 0.5",
+		"point 2 skipped: temporary point",
+	} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("log missing %q, got:\n%s", want, log.String())
+		}
+	}
+}
+
+func TestConvertDocumentWithOptions_SortEntities(t *testing.T) {
+	entities := []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 1}, StartX: 5, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 0}, StartX: 3, EndX: 10},
+		&jww.Point{EntityBase: jww.EntityBase{Layer: 0}, X: 1, Y: 2},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 0}, StartX: 3, StartY: -1, EndX: 10},
+		&jww.Arc{EntityBase: jww.EntityBase{Layer: 1}, Radius: 5, IsFullCircle: true, Flatness: 1},
+	}
+	shuffled := []jww.Entity{entities[3], entities[0], entities[4], entities[2], entities[1]}
+
+	convert := func(input []jww.Entity) string {
+		doc := createTestDocument()
+		doc.Entities = input
+		return ToString(ConvertDocumentWithOptions(doc, ConvertOptions{SortEntities: true}))
+	}
+
+	if convert(entities) != convert(shuffled) {
+		t.Error("expected identical output for shuffled input order")
+	}
+
+	doc := createTestDocument()
+	doc.Entities = shuffled
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{SortEntities: true})
+
+	first, ok := result.Entities[0].(*Line)
+	if !ok || first.Layer != "0-0" || first.Y1 != -1 {
+		t.Errorf("expected first entity to be the layer 0-0 line at (3, -1), got %#v", result.Entities[0])
+	}
+	if _, ok := result.Entities[2].(*Point); !ok {
+		t.Errorf("expected POINT after LINEs on layer 0-0, got %T", result.Entities[2])
+	}
+}
+
+func TestConvertDocumentWithOptions_SingleColorByLayer(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 2}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 7}, EndY: 10},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{SingleColorByLayer: true})
+
+	for i, e := range result.Entities {
+		if c := e.(*Line).Color; c != 0 {
+			t.Errorf("entity %d color: got %d, want 0 (BYLAYER)", i, c)
+		}
+	}
+	for _, l := range result.Layers {
+		if l.Color != 7 {
+			t.Fatalf("layer %s color: got %d, want 7", l.Name, l.Color)
+		}
+	}
+}
+
+func TestConvertDocumentWithOptions_SingleColorByLayerMixed(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 2}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 8}, EndY: 10},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{SingleColorByLayer: true})
+
+	if c := result.Entities[0].(*Line).Color; c != 7 {
+		t.Errorf("first entity color: got %d, want 7", c)
+	}
+	if c := result.Entities[1].(*Line).Color; c != 1 {
+		t.Errorf("second entity color: got %d, want 1", c)
+	}
+}
+
+func TestConvertDocumentWithOptions_LineTypeScale(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[1].Scale = 50
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 2, LayerGroup: 1}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 1, LayerGroup: 1}, EndY: 10},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{LineTypeScale: 0.5})
+
+	if result.LineTypeScale != 0.5 {
+		t.Errorf("document linetype scale: got %v, want 0.5", result.LineTypeScale)
+	}
+
+	dashed := result.Entities[0].(*Line)
+	if dashed.LineTypeScale != 50 {
+		t.Errorf("dashed linetype scale: got %v, want 50", dashed.LineTypeScale)
+	}
+	if !hasGroupCode(dashed.GroupCodes(), 48, 50.0) {
+		t.Errorf("expected group code 48 on dashed line, got %v", dashed.GroupCodes())
+	}
+
+	continuous := result.Entities[1].(*Line)
+	for _, gc := range continuous.GroupCodes() {
+		if gc.Code == 48 {
+			t.Errorf("unexpected group code 48 on continuous line: %v", gc.Value)
+		}
+	}
+}
+
+func TestConvertDocumentWithOptions_TitleBlockLayers(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[15].Layers[0].Name = "Title"
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{LayerGroup: 15}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{LayerGroup: 0}, EndY: 10},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{TitleBlockLayers: []string{"Title"}})
+
+	if len(result.Entities) != 1 || len(result.PaperSpaceEntities) != 1 {
+		t.Fatalf("got %d model and %d paper space entities, want 1 and 1",
+			len(result.Entities), len(result.PaperSpaceEntities))
+	}
+	if l := result.PaperSpaceEntities[0].(*Line); l.Layer != "Title" {
+		t.Errorf("paper space entity layer: got %s, want Title", l.Layer)
+	}
+
+	output := ToString(result)
+	if got := strings.Count(output, " 67\n1\n"); got != 1 {
+		t.Errorf("expected 67=1 once, got %d", got)
+	}
+	if !strings.Contains(output, "  0\nLINE\n 67\n1\n  8\nTitle\n") {
+		t.Errorf("expected title block line marked with 67=1")
+	}
+	if !strings.Contains(output, "  0\nLINE\n  8\n0-0\n") {
+		t.Errorf("expected model space line without 67")
+	}
+	if !strings.Contains(output, "  2\n*Paper_Space\n") {
+		t.Errorf("expected *Paper_Space block definition")
+	}
+}
+
+// hasGroupCode reports whether codes contains the given code/value pair.
+func hasGroupCode(codes []GroupCode, code int, value interface{}) bool {
+	for _, gc := range codes {
+		if gc.Code == code && gc.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
 // createTestDocument creates a minimal JWW document for testing.
 func createTestDocument() *jww.Document {
 	doc := &jww.Document{
@@ -481,3 +1080,703 @@ func createTestDocument() *jww.Document {
 
 	return doc
 }
+
+func TestConvertLayerColorsFromEntities(t *testing.T) {
+	red := jww.EntityBase{PenColor: 8, Layer: 1}  // ACI 1
+	cyan := jww.EntityBase{PenColor: 1, Layer: 1} // ACI 4
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: red, EndX: 1},
+		&jww.Line{EntityBase: red, EndX: 2},
+		&jww.Line{EntityBase: cyan, EndX: 3},
+	}
+
+	defaultColor := ConvertDocument(doc).GetLayer("0-1").Color
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{LayerColorsFromEntities: true})
+	if got := result.GetLayer("0-1").Color; got != 1 {
+		t.Errorf("layer 0-1 color: got %d, want 1 (red)", got)
+	}
+	if got := result.GetLayer("0-2").Color; got != defaultColor {
+		t.Errorf("empty layer color: got %d, want default %d", got, defaultColor)
+	}
+	if line := result.Entities[2].(*Line); line.Color != 4 {
+		t.Errorf("entity colors should be unchanged, got %d", line.Color)
+	}
+}
+
+func TestConvertEntityHook(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 1}, EndX: 10},
+		&jww.Text{EntityBase: jww.EntityBase{PenColor: 1}, Content: "A", SizeY: 3},
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 1}, EndX: 20},
+	}
+
+	t.Run("drop text", func(t *testing.T) {
+		result := ConvertDocumentWithOptions(doc, ConvertOptions{
+			EntityHook: func(src jww.Entity, e Entity) Entity {
+				if _, ok := src.(*jww.Text); ok {
+					return nil
+				}
+				return e
+			},
+		})
+		if counts := result.CountByType(); counts["TEXT"] != 0 || counts["LINE"] != 2 {
+			t.Errorf("got %v, want 2 LINE and no TEXT", counts)
+		}
+	})
+
+	t.Run("rewrite layer", func(t *testing.T) {
+		result := ConvertDocumentWithOptions(doc, ConvertOptions{
+			EntityHook: func(_ jww.Entity, e Entity) Entity {
+				if line, ok := e.(*Line); ok {
+					line.Layer = "0-F"
+				}
+				return e
+			},
+		})
+		for _, line := range EntitiesOfType[*Line](result) {
+			if line.Layer != "0-F" {
+				t.Errorf("line layer: got %q, want 0-F", line.Layer)
+			}
+		}
+		if text := EntitiesOfType[*Text](result); len(text) != 1 || text[0].Layer != "0-0" {
+			t.Errorf("text should keep its layer, got %+v", text)
+		}
+	})
+}
+
+func TestConvertArc_Clockwise(t *testing.T) {
+	// Quarter arcs from 90° clockwise to 0°: both cover the first quadrant
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Arc{EntityBase: jww.EntityBase{PenColor: 1}, Radius: 10, StartAngle: math.Pi / 2, ArcAngle: -math.Pi / 2, Flatness: 1},
+		&jww.Arc{EntityBase: jww.EntityBase{PenColor: 1}, Radius: 10, StartAngle: math.Pi / 2, ArcAngle: -math.Pi / 2, Flatness: 0.5},
+	}
+
+	result := ConvertDocument(doc)
+
+	arc, ok := result.Entities[0].(*Arc)
+	if !ok {
+		t.Fatalf("expected *Arc, got %T", result.Entities[0])
+	}
+	if !approxEqual(arc.StartAngle, 0) || !approxEqual(arc.EndAngle, 90) {
+		t.Errorf("arc: got %v° to %v°, want 0° to 90°", arc.StartAngle, arc.EndAngle)
+	}
+	if minX, minY, _, _ := arc.BoundingBox(); minX < -1e-9 || minY < -1e-9 {
+		t.Errorf("arc leaves the first quadrant: min (%v, %v)", minX, minY)
+	}
+
+	ellipse, ok := result.Entities[1].(*Ellipse)
+	if !ok {
+		t.Fatalf("expected *Ellipse, got %T", result.Entities[1])
+	}
+	if !approxEqual(ellipse.StartParam, 0) || !approxEqual(ellipse.EndParam, math.Pi/2) {
+		t.Errorf("ellipse: got params %v to %v, want 0 to π/2", ellipse.StartParam, ellipse.EndParam)
+	}
+}
+
+func TestConvertLayerFilters(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 1}, EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 2}, EndX: 2},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 3}, EndX: 3},
+	}
+
+	tests := []struct {
+		name string
+		opts ConvertOptions
+		want []float64 // end X of the kept lines
+	}{
+		{"no filter", ConvertOptions{}, []float64{1, 2, 3}},
+		{"include", ConvertOptions{IncludeLayers: []string{"0-1", "0-3"}}, []float64{1, 3}},
+		{"exclude", ConvertOptions{ExcludeLayers: []string{"0-2"}}, []float64{1, 3}},
+		{"both", ConvertOptions{IncludeLayers: []string{"0-1", "0-2"}, ExcludeLayers: []string{"0-2"}}, []float64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ConvertDocumentWithOptions(doc, tt.opts)
+			var got []float64
+			for _, e := range result.Entities {
+				got = append(got, e.(*Line).X2)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("kept lines: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertPruneUnusedLayers(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 1}, EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 2}, EndX: 2},
+	}
+	doc.BlockDefs = []jww.BlockDef{{
+		Number:   1,
+		Name:     "B",
+		Entities: []jww.Entity{&jww.Line{EntityBase: jww.EntityBase{LayerGroup: 1}, EndX: 1}},
+	}}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{
+		ExcludeLayers:     []string{"0-2"},
+		PruneUnusedLayers: true,
+	})
+
+	var names []string
+	for _, l := range result.Layers {
+		names = append(names, l.Name)
+	}
+	if !slices.Equal(names, []string{"0-1", "1-0"}) {
+		t.Errorf("layers: got %v, want [0-1 1-0]", names)
+	}
+}
+
+func TestConvertPruneUnusedLayers_Output(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 1}, EndX: 1},
+		&jww.Arc{EntityBase: jww.EntityBase{Layer: 1, LayerGroup: 2}, Radius: 1, Flatness: 1, IsFullCircle: true},
+	}
+
+	output := ToString(ConvertDocumentWithOptions(doc, ConvertOptions{PruneUnusedLayers: true}))
+
+	var names []string
+	for _, record := range strings.Split(output, "  0\nLAYER\n")[1:] {
+		_, rest, _ := strings.Cut(record, "  2\n")
+		name, _, _ := strings.Cut(rest, "\n")
+		names = append(names, name)
+	}
+	if !slices.Equal(names, []string{"0", "0-1", "2-1"}) {
+		t.Errorf("written layers: got %v, want [0 0-1 2-1]", names)
+	}
+}
+
+func TestConvertColorToLayer(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 8, Layer: 1}, EndX: 1}, // red, ACI 1
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 8, Layer: 2}, EndX: 2},
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 6, Layer: 1}, EndX: 3}, // blue, ACI 5
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{ColorToLayer: true})
+
+	for i, want := range []string{"COLOR_8", "COLOR_8", "COLOR_6"} {
+		if got := result.Entities[i].(*Line).Layer; got != want {
+			t.Errorf("entity %d layer: got %s, want %s", i, got, want)
+		}
+	}
+	red := result.GetLayer("COLOR_8")
+	if red == nil || red.Color != 1 {
+		t.Fatalf("expected layer COLOR_8 with color 1, got %+v", red)
+	}
+	if blue := result.GetLayer("COLOR_6"); blue == nil || blue.Color != 5 {
+		t.Errorf("expected layer COLOR_6 with color 5, got %+v", blue)
+	}
+	if result.GetLayer("COLOR_1") != nil {
+		t.Errorf("unused color layer COLOR_1 should not be created")
+	}
+	if issues := Validate(result); len(issues) > 0 {
+		t.Errorf("unexpected validation issues: %v", issues)
+	}
+}
+
+func TestConvertGroupToLayerSuffix(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[0].Layers[1].Protect = 1
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Group: 0, Layer: 1}, EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{Group: 3, Layer: 1}, EndX: 2},
+		&jww.Line{EntityBase: jww.EntityBase{Group: 5, Layer: 1}, EndX: 3},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{GroupToLayerSuffix: true})
+
+	for i, want := range []string{"0-1", "0-1_G3", "0-1_G5"} {
+		if got := result.Entities[i].(*Line).Layer; got != want {
+			t.Errorf("entity %d layer: got %s, want %s", i, got, want)
+		}
+	}
+	g3 := result.GetLayer("0-1_G3")
+	if g3 == nil || !g3.Locked {
+		t.Errorf("expected locked layer 0-1_G3 copied from 0-1, got %+v", g3)
+	}
+	if issues := Validate(result); len(issues) > 0 {
+		t.Errorf("unexpected validation issues: %v", issues)
+	}
+
+	plain := ConvertDocument(doc)
+	if got := plain.Entities[1].(*Line).Layer; got != "0-1" {
+		t.Errorf("without option: got layer %s, want 0-1", got)
+	}
+}
+
+func TestConvertSourceIDs(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{ID: 2}, EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{ID: 4}, EndX: 2},
+		&jww.Line{EndX: 3}, // built in code, no ID
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{SourceIDs: true})
+
+	want := []GroupCode{{1071, 4}}
+	if got := result.Entities[1].(*Line).XData[SourceIDAppName]; !slices.Equal(got, want) {
+		t.Errorf("xdata: got %v, want %v", got, want)
+	}
+	if xdata := result.Entities[2].(*Line).XData; xdata != nil {
+		t.Errorf("expected no xdata for an entity without ID, got %v", xdata)
+	}
+
+	output := ToString(result)
+	for _, s := range []string{
+		"  2\nAPPID\n",
+		"  2\nJWW_PARSER\n",
+		"1001\nJWW_PARSER\n1071\n2\n",
+	} {
+		if !strings.Contains(output, s) {
+			t.Errorf("expected output to contain %q", s)
+		}
+	}
+
+	if plain := ConvertDocument(doc); plain.Entities[0].(*Line).XData != nil || strings.Contains(ToString(plain), "APPID") {
+		t.Error("expected no xdata without SourceIDs")
+	}
+}
+
+func TestConvertCurrentLayer(t *testing.T) {
+	doc := createTestDocument()
+	doc.WriteLayerGroup = 2
+	doc.LayerGroups[2].WriteLayer = 0xB
+	doc.LayerGroups[2].Layers[0xB].Name = "Walls"
+
+	result := ConvertDocument(doc)
+	if result.CurrentLayer != "Walls" {
+		t.Errorf("CurrentLayer: got %q, want %q", result.CurrentLayer, "Walls")
+	}
+	if output := ToString(result); !strings.Contains(output, "  9\n$CLAYER\n  8\nWalls\n") {
+		t.Error("Expected $CLAYER to name the write layer")
+	}
+
+	// A pruned write layer falls back to layer 0
+	pruned := ConvertDocumentWithOptions(doc, ConvertOptions{PruneUnusedLayers: true})
+	if output := ToString(pruned); !strings.Contains(output, "  9\n$CLAYER\n  8\n0\n") {
+		t.Error("Expected $CLAYER 0 for a pruned write layer")
+	}
+}
+
+func TestConvertCustomLineTypes(t *testing.T) {
+	doc := createTestDocument()
+	doc.LineTypes = []jww.LineTypeDef{{PenStyle: 31, Name: "USER1", Pattern: []float64{4, 1, 0.5, 1}}}
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 31}, EndX: 10},
+		&jww.Line{EntityBase: jww.EntityBase{PenStyle: 32}, EndX: 10}, // not defined
+	}
+
+	result := ConvertDocument(doc)
+
+	if got := result.Entities[0].(*Line).LineType; got != "JWW_LT31" {
+		t.Errorf("line type: got %q, want JWW_LT31", got)
+	}
+	if got := result.Entities[1].(*Line).LineType; got != "CONTINUOUS" {
+		t.Errorf("undefined line type: got %q, want CONTINUOUS", got)
+	}
+	want := []LineType{{Name: "JWW_LT31", Description: "USER1", Pattern: []float64{4, -1, 0.5, -1}}}
+	if !reflect.DeepEqual(result.LineTypes, want) {
+		t.Errorf("LineTypes: got %+v, want %+v", result.LineTypes, want)
+	}
+
+	output := ToString(result)
+	ltype := "  0\nLTYPE\n  5\n"
+	if !strings.Contains(output, "  2\nJWW_LT31\n 70\n0\n  3\nUSER1\n 72\n65\n 73\n4\n 40\n6.500000\n 49\n4.000000\n") ||
+		strings.Count(output, ltype) != len(standardLinetypes)+1 {
+		t.Error("Expected the LTYPE table to define JWW_LT31")
+	}
+	if issues := Validate(result); len(issues) != 0 {
+		t.Errorf("Validate: %v", issues)
+	}
+}
+
+func TestConvertTextStyles(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Text{SizeY: 3, FontName: "ＭＳ ゴシック", Content: "平面図"},
+		&jww.Text{SizeY: 3, FontName: "ＭＳ 明朝", Content: "立面図"},
+		&jww.Text{SizeY: 3, FontName: "ＭＳ ゴシック", Content: "断面図"},
+		&jww.Text{SizeY: 3, Content: "A"},
+	}
+
+	result := ConvertDocument(doc)
+
+	for i, want := range []string{"MS_GOTHIC", "MS_MINCHO", "MS_GOTHIC", "STANDARD"} {
+		if got := result.Entities[i].(*Text).Style; got != want {
+			t.Errorf("text %d style: got %q, want %q", i, got, want)
+		}
+	}
+	want := []TextStyle{{Name: "MS_GOTHIC", Font: "msgothic.ttc"}, {Name: "MS_MINCHO", Font: "msmincho.ttc"}}
+	if !reflect.DeepEqual(result.TextStyles, want) {
+		t.Errorf("TextStyles: got %+v, want %+v", result.TextStyles, want)
+	}
+
+	output := ToString(result)
+	if strings.Count(output, "  0\nSTYLE\n") != 3 {
+		t.Error("Expected 3 STYLE table entries")
+	}
+	for _, style := range want {
+		if !strings.Contains(output, "  2\n"+style.Name+"\n 70\n0\n") ||
+			!strings.Contains(output, "  3\n"+style.Font+"\n") {
+			t.Errorf("Expected a STYLE table entry for %s with font %s", style.Name, style.Font)
+		}
+	}
+	if !strings.Contains(output, "  7\nMS_GOTHIC\n") || !strings.Contains(output, "  7\nMS_MINCHO\n") {
+		t.Error("Expected the texts to reference their styles")
+	}
+	if issues := Validate(result); len(issues) != 0 {
+		t.Errorf("Validate: %v", issues)
+	}
+}
+
+func TestConvertDimStyle(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[0].Scale = 100
+	doc.Entities = []jww.Entity{&jww.Dimension{
+		Line: jww.Line{EndX: 1000},
+		Text: jww.Text{EndX: 500, SizeY: 3, Content: "1000"},
+	}}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{DefaultTextHeight: 3})
+	if want := (DimStyle{ArrowSize: 3, TextHeight: 3}); result.DimStyle != want {
+		t.Errorf("DimStyle: got %+v, want %+v", result.DimStyle, want)
+	}
+	if result.DimScale != 1 {
+		t.Errorf("DimScale: got %v, want 1", result.DimScale)
+	}
+
+	result = ConvertDocumentWithOptions(doc, ConvertOptions{PaperSizesToWorld: true})
+	if result.DimScale != 100 {
+		t.Errorf("DimScale with PaperSizesToWorld: got %v, want 100", result.DimScale)
+	}
+	output := ToString(result)
+	if !strings.Contains(output, "$DIMSCALE\n 40\n100.000000\n") {
+		t.Error("Expected $DIMSCALE 100")
+	}
+	if !strings.Contains(output, "  2\nDIMSTYLE\n") || !strings.Contains(output, "  0\nDIMSTYLE\n105\n") {
+		t.Error("Expected a DIMSTYLE table with the STANDARD entry")
+	}
+}
+
+func TestTextStyleName(t *testing.T) {
+	tests := []struct {
+		font, want string
+	}{
+		{"ＭＳ ゴシック", "MS_GOTHIC"},
+		{"MS ゴシック", "MS_GOTHIC"},
+		{"ＭＳ　Ｐ明朝", "MS_PMINCHO"},
+		{"Arial", "Arial"},
+		{"", "STANDARD"},
+	}
+	for _, tt := range tests {
+		if got := TextStyleName(tt.font); got != tt.want {
+			t.Errorf("TextStyleName(%q): got %q, want %q", tt.font, got, tt.want)
+		}
+	}
+}
+
+func TestConvertCurveSegments(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Arc{EntityBase: jww.EntityBase{PenColor: 2}, CenterX: 5, CenterY: 5, Radius: 10, Flatness: 1, IsFullCircle: true},
+		&jww.Arc{Radius: 10, Flatness: 1, ArcAngle: math.Pi / 2},
+		&jww.Arc{Radius: 10, Flatness: 0.5, IsFullCircle: true},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{CurveSegments: 8})
+
+	circle, ok := result.Entities[0].(*LWPolyline)
+	if !ok || !circle.Closed || len(circle.Vertices) != 8 {
+		t.Fatalf("circle: expected a closed 8-vertex LWPOLYLINE, got %#v", result.Entities[0])
+	}
+	native := ConvertDocument(doc)
+	if c, ok := native.Entities[0].(*Circle); !ok {
+		t.Errorf("CurveSegments 0: expected a CIRCLE, got %T", native.Entities[0])
+	} else if circle.Layer != c.Layer || circle.Color != c.Color || circle.LineType != c.LineType {
+		t.Errorf("circle attributes: got %s/%d/%s, want %s/%d/%s", circle.Layer, circle.Color, circle.LineType, c.Layer, c.Color, c.LineType)
+	}
+	for i, v := range circle.Vertices {
+		if d := math.Hypot(v.X-5, v.Y-5); math.Abs(d-10) > 1e-9 {
+			t.Errorf("circle vertex %d: (%g, %g) is %g from the center, want 10", i, v.X, v.Y, d)
+		}
+	}
+
+	arc, ok := result.Entities[1].(*LWPolyline)
+	if !ok || arc.Closed || len(arc.Vertices) != 9 {
+		t.Fatalf("arc: expected an open 9-vertex LWPOLYLINE, got %#v", result.Entities[1])
+	}
+	first, last := arc.Vertices[0], arc.Vertices[8]
+	if math.Abs(first.X-10) > 1e-9 || math.Abs(first.Y) > 1e-9 || math.Abs(last.X) > 1e-9 || math.Abs(last.Y-10) > 1e-9 {
+		t.Errorf("arc endpoints: got %v and %v, want (10, 0) and (0, 10)", first, last)
+	}
+
+	ellipse, ok := result.Entities[2].(*LWPolyline)
+	if !ok || !ellipse.Closed || len(ellipse.Vertices) != 8 {
+		t.Fatalf("ellipse: expected a closed 8-vertex LWPOLYLINE, got %#v", result.Entities[2])
+	}
+
+}
+
+func TestConvertGroups(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Group: 3}, EndX: 10},
+		&jww.Line{EndY: 10},
+		&jww.Arc{EntityBase: jww.EntityBase{Group: 3}, Radius: 5, Flatness: 1, IsFullCircle: true},
+	}
+	doc.Groups = []jww.EntityGroup{{Number: 3, Entities: []int{0, 2}}}
+
+	result := ConvertDocument(doc)
+
+	if len(result.Groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(result.Groups))
+	}
+	g := result.Groups[0]
+	if g.Name != "JWW_GROUP3" || len(g.Entities) != 2 || g.Entities[0] != result.Entities[0] || g.Entities[1] != result.Entities[2] {
+		t.Fatalf("group: got %+v, want JWW_GROUP3 with the line and the circle", g)
+	}
+
+	output := ToString(result)
+	lineHandle := regexp.MustCompile(`  0\nLINE\n  5\n([0-9A-F]+)\n`).FindAllStringSubmatch(output, -1)
+	circleHandle := regexp.MustCompile(`  0\nCIRCLE\n  5\n([0-9A-F]+)\n`).FindStringSubmatch(output)
+	if len(lineHandle) != 1 || circleHandle == nil {
+		t.Fatalf("expected handles on the grouped LINE and CIRCLE only, got %v and %v", lineHandle, circleHandle)
+	}
+	group := "  3\nJWW_GROUP3\n"
+	members := "  0\nGROUP\n"
+	if !strings.Contains(output, group) || !strings.Contains(output, members) {
+		t.Fatal("Expected an ACAD_GROUP entry and a GROUP object")
+	}
+	object := output[strings.Index(output, members):]
+	want := " 71\n1\n340\n" + lineHandle[0][1] + "\n340\n" + circleHandle[1] + "\n"
+	if !strings.Contains(object, want) {
+		t.Errorf("Expected the GROUP to reference both members:\n%s", object)
+	}
+
+	r12 := ToStringWithOptions(result, WriteOptions{Version: R12})
+	if strings.Contains(r12, "AcDbGroup") {
+		t.Error("R12 output should have no groups")
+	}
+}
+
+func TestConvertAddExtentsRectangle(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{
+		&jww.Line{StartX: -10, StartY: 5, EndX: 40, EndY: 5},
+		&jww.Arc{Radius: 20, Flatness: 1, IsFullCircle: true},
+	}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{AddExtentsRectangle: true})
+
+	if len(result.Entities) != 3 {
+		t.Fatalf("expected 3 entities, got %d", len(result.Entities))
+	}
+	rect, ok := result.Entities[2].(*LWPolyline)
+	if !ok || rect.Layer != ExtentsLayer || !rect.Closed {
+		t.Fatalf("expected a closed LWPOLYLINE on %s, got %#v", ExtentsLayer, result.Entities[2])
+	}
+	minX, minY, maxX, maxY := result.BoundingBox()
+	want := []Vertex{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}}
+	if !slices.Equal(rect.Vertices, want) {
+		t.Errorf("corners: got %v, want %v", rect.Vertices, want)
+	}
+	if want := (Vertex{-20, -20}); rect.Vertices[0] != want {
+		t.Errorf("first corner: got %v, want %v", rect.Vertices[0], want)
+	}
+	if !slices.ContainsFunc(result.Layers, func(l Layer) bool { return l.Name == ExtentsLayer }) {
+		t.Errorf("expected layer %s", ExtentsLayer)
+	}
+
+	doc.Entities = nil
+	if empty := ConvertDocumentWithOptions(doc, ConvertOptions{AddExtentsRectangle: true}); len(empty.Entities) != 0 {
+		t.Errorf("expected no rectangle without entities, got %d entities", len(empty.Entities))
+	}
+}
+
+func TestConvertSourceAttributes(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Line{
+		EntityBase: jww.EntityBase{LayerGroup: 2, Layer: 11, PenColor: 105, PenStyle: 3, Group: 7},
+		EndX:       1,
+	}}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{SourceAttributes: true})
+
+	want := []GroupCode{{1070, 2}, {1070, 11}, {1070, 3}, {1071, 105}, {1071, 7}}
+	if got := result.Entities[0].(*Line).XData[SourceAttributesAppName]; !slices.Equal(got, want) {
+		t.Errorf("xdata: got %v, want %v", got, want)
+	}
+}
+
+func TestConvertGlobalTransform(t *testing.T) {
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Line{StartX: 10, StartY: 0, EndX: 20, EndY: 5}}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{
+		GlobalRotation:    90,
+		GlobalTranslation: Vertex{X: 100, Y: 200},
+	})
+
+	line := result.Entities[0].(*Line)
+	got := []float64{line.X1, line.Y1, line.X2, line.Y2}
+	want := []float64{100, 210, 95, 220} // (x, y) -> (-y + 100, x + 200)
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("line endpoints: got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestConvertDimension(t *testing.T) {
+	base := jww.EntityBase{PenColor: 8, Layer: 1}
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Dimension{
+		EntityBase: base,
+		Line:       jww.Line{EntityBase: base, StartX: 0, EndX: 100},
+		Text:       jww.Text{EntityBase: base, StartX: 45, StartY: 1, SizeY: 3, Content: "100"},
+		EndPoints:  [2]jww.Point{{X: 0}, {X: 100}},
+	}}
+
+	result := ConvertDocument(doc)
+
+	counts := result.CountByType()
+	if counts["LINE"] != 1 || counts["TEXT"] != 1 || counts["SOLID"] != 2 {
+		t.Fatalf("expected a line, a text and 2 solids, got %v", counts)
+	}
+
+	arrows := EntitiesOfType[*Solid](result)
+	for i, tip := range []float64{0, 100} {
+		a := arrows[i]
+		if a.X1 != tip || a.Y1 != 0 {
+			t.Errorf("arrow %d tip: got (%v, %v), want (%v, 0)", i, a.X1, a.Y1, tip)
+		}
+		if !a.IsTriangle() {
+			t.Errorf("arrow %d is not a triangle", i)
+		}
+		// Arrowheads point outward, so their bases lie inside the line
+		if baseX := a.X2; math.Abs(baseX-tip) != 3 || (baseX-tip)*(50-tip) < 0 {
+			t.Errorf("arrow %d base: got x %v, want 3 inside %v", i, baseX, tip)
+		}
+		if a.Layer != "0-1" || a.Color != 1 {
+			t.Errorf("arrow %d: got layer %s color %d, want 0-1 color 1", i, a.Layer, a.Color)
+		}
+	}
+}
+
+func TestConvertDimensionEntities(t *testing.T) {
+	base := jww.EntityBase{PenColor: 8, Layer: 1}
+	doc := createTestDocument()
+	doc.Entities = []jww.Entity{&jww.Dimension{
+		EntityBase: base,
+		Line:       jww.Line{EntityBase: base, StartX: 0, StartY: 5, EndX: 100, EndY: 5},
+		Text:       jww.Text{EntityBase: base, StartX: 45, StartY: 6, EndX: 55, EndY: 6, SizeY: 3, Content: "100"},
+		EndPoints:  [2]jww.Point{{X: 0}, {X: 100}},
+	}}
+
+	result := ConvertDocumentWithOptions(doc, ConvertOptions{DimensionEntities: true, PruneUnusedLayers: true})
+	if len(result.Entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(result.Entities))
+	}
+	dim, ok := result.Entities[0].(*Dimension)
+	if !ok {
+		t.Fatalf("expected a DIMENSION, got %T", result.Entities[0])
+	}
+	if dim.Layer != "0-1" || dim.Style != "" || dim.Text != "100" {
+		t.Errorf("dimension: got layer %s style %q text %q", dim.Layer, dim.Style, dim.Text)
+	}
+	if dim.X1 != 0 || dim.Y1 != 0 || dim.X2 != 100 || dim.Y2 != 0 || dim.LineY != 5 || dim.TextX != 50 {
+		t.Errorf("dimension points: got %+v", dim)
+	}
+	if n := len(dim.Entities); n != 4 {
+		t.Errorf("expected a line, a text and 2 arrowheads drawing the dimension, got %d entities", n)
+	}
+	if issues := Validate(result); len(issues) != 0 {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+
+	// The DIMENSION refers to its anonymous block and the STANDARD style
+	output := ToString(result)
+	for _, want := range []string{
+		"  0\nBLOCK\n  8\n0\n  2\n*D1\n 70\n1\n",
+		"  0\nDIMENSION\n  8\n0-1\n 62\n1\n  6\nCONTINUOUS\n  2\n*D1\n  3\nSTANDARD\n",
+		"  0\nDIMSTYLE\n105\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestConvertComments(t *testing.T) {
+	doc := &jww.Document{Memo: "敷地図\r\nrev 2"}
+
+	result := ConvertDocument(doc)
+	if !slices.Equal(result.Comments, []string{"敷地図\r\nrev 2"}) {
+		t.Errorf("comments: got %q", result.Comments)
+	}
+
+	// Callers add further comments to the converted document
+	result.Comments = append(result.Comments, "checked")
+	want := "999\n\\U+6577\\U+5730\\U+56F3\n999\nrev 2\n999\nchecked\n  0\nSECTION\n"
+	if output := ToString(result); !strings.HasPrefix(output, want) {
+		t.Errorf("expected the comments before the first section, got:\n%.120s", output)
+	}
+
+	if comments := ConvertDocument(&jww.Document{Memo: " "}).Comments; len(comments) != 0 {
+		t.Errorf("blank memo: got %q", comments)
+	}
+}
+
+func TestConvertSkipHiddenLayerEntities(t *testing.T) {
+	doc := createTestDocument()
+	doc.LayerGroups[0].Layers[2].State = 0 // hidden layer
+	doc.LayerGroups[3].State = 0           // hidden group
+	doc.Entities = []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 1}, EndX: 1},
+		&jww.Line{EntityBase: jww.EntityBase{Layer: 2}, EndX: 2},
+		&jww.Line{EntityBase: jww.EntityBase{LayerGroup: 3}, EndX: 3},
+	}
+	doc.BlockDefs = []jww.BlockDef{{
+		Number:   1,
+		Name:     "B",
+		Entities: []jww.Entity{&jww.Line{EntityBase: jww.EntityBase{Layer: 2}, EndX: 1}},
+	}}
+
+	tests := []struct {
+		name string
+		skip bool
+		want []float64 // end X of the kept lines
+	}{
+		{"default", false, []float64{1, 2, 3}},
+		{"skip hidden", true, []float64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ConvertDocumentWithOptions(doc, ConvertOptions{SkipHiddenLayerEntities: tt.skip})
+			var got []float64
+			for _, e := range result.Entities {
+				got = append(got, e.(*Line).X2)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("kept lines: got %v, want %v", got, tt.want)
+			}
+			if n := len(result.Blocks[0].Entities); n != 1 {
+				t.Errorf("expected the block entity to be kept, got %d entities", n)
+			}
+		})
+	}
+}
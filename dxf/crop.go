@@ -0,0 +1,126 @@
+package dxf
+
+// CropOptions controls optional behavior of CropWithOptions.
+// The zero value matches Crop.
+type CropOptions struct {
+	// KeepStraddling keeps entities other than lines whose bounding box
+	// crosses the crop box edge. By default they are dropped. Lines are
+	// always clipped to the box instead.
+	KeepStraddling bool
+}
+
+// Crop keeps the model space entities inside the box (minX, minY)-(maxX, maxY)
+// and returns the document for chaining. It is equivalent to
+// CropWithOptions with zero options.
+//
+// Example:
+//
+//	doc.Crop(0, 0, 1000, 500) // keep the area of the first sheet
+func (d *Document) Crop(minX, minY, maxX, maxY float64) *Document {
+	return d.CropWithOptions(minX, minY, maxX, maxY, CropOptions{})
+}
+
+// CropWithOptions removes model space entities outside the box
+// (minX, minY)-(maxX, maxY) in place:
+//   - entities whose bounding box lies inside the box are kept
+//   - entities whose bounding box lies outside the box are dropped
+//   - lines crossing the box edge are clipped to it (Liang-Barsky)
+//   - other entities crossing the box edge are dropped, or kept unchanged
+//     when opts.KeepStraddling is set
+//
+// Inserts are kept when their insertion point lies inside the box. Paper
+// space entities and block definitions are not changed.
+func (d *Document) CropWithOptions(minX, minY, maxX, maxY float64, opts CropOptions) *Document {
+	box := cropBox{minX, minY, maxX, maxY}
+
+	kept := d.Entities[:0]
+	for _, entity := range d.Entities {
+		if box.keep(entity, opts) {
+			kept = append(kept, entity)
+		}
+	}
+	for i := len(kept); i < len(d.Entities); i++ {
+		d.Entities[i] = nil // release dropped entities
+	}
+	d.Entities = kept
+
+	return d
+}
+
+// cropBox is an axis-aligned crop rectangle.
+type cropBox struct {
+	minX, minY, maxX, maxY float64
+}
+
+// keep reports whether entity remains after cropping, clipping lines in place.
+func (b cropBox) keep(entity Entity, opts CropOptions) bool {
+	if insert, ok := entity.(*Insert); ok {
+		return b.contains(insert.X, insert.Y)
+	}
+
+	eMinX, eMinY, eMaxX, eMaxY, ok := boundsOf(entity)
+	if !ok {
+		return opts.KeepStraddling
+	}
+	switch {
+	case eMinX >= b.minX && eMaxX <= b.maxX && eMinY >= b.minY && eMaxY <= b.maxY:
+		return true
+	case eMaxX < b.minX || eMinX > b.maxX || eMaxY < b.minY || eMinY > b.maxY:
+		return false
+	}
+
+	if line, ok := entity.(*Line); ok {
+		return b.clipLine(line)
+	}
+	return opts.KeepStraddling
+}
+
+// contains reports whether (x, y) lies inside the box or on its edge.
+func (b cropBox) contains(x, y float64) bool {
+	return x >= b.minX && x <= b.maxX && y >= b.minY && y <= b.maxY
+}
+
+// clipLine clips l to the box with the Liang-Barsky algorithm.
+// It returns false, leaving l unchanged, if no part of l lies inside.
+func (b cropBox) clipLine(l *Line) bool {
+	dx, dy := l.X2-l.X1, l.Y2-l.Y1
+	t0, t1 := 0.0, 1.0
+
+	// Each edge constrains t through p*t <= q
+	edges := [4][2]float64{
+		{-dx, l.X1 - b.minX},
+		{dx, b.maxX - l.X1},
+		{-dy, l.Y1 - b.minY},
+		{dy, b.maxY - l.Y1},
+	}
+	for _, edge := range edges {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return false // parallel to and outside this edge
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			if t > t1 {
+				return false
+			}
+			if t > t0 {
+				t0 = t
+			}
+		} else {
+			if t < t0 {
+				return false
+			}
+			if t < t1 {
+				t1 = t
+			}
+		}
+	}
+
+	x1, y1 := l.X1, l.Y1
+	l.X1, l.Y1 = x1+t0*dx, y1+t0*dy
+	l.X2, l.Y2 = x1+t1*dx, y1+t1*dy
+	return true
+}
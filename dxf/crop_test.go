@@ -0,0 +1,46 @@
+package dxf
+
+import "testing"
+
+func TestCrop(t *testing.T) {
+	doc := NewDocument().
+		AddLine(10, 10, 20, 20).   // inside
+		AddLine(50, 50, 150, 50).  // crosses the right edge
+		AddLine(200, 200, 300, 0). // outside
+		AddLine(90, 120, 120, 90). // bounding box overlaps the corner, line misses it
+		AddCircle(95, 50, 10).     // straddles the right edge
+		AddCircle(500, 500, 10).   // outside
+		AddInsert("B", 50, 50)     // insertion point inside
+
+	doc.Crop(0, 0, 100, 100)
+
+	if len(doc.Entities) != 3 {
+		t.Fatalf("expected 3 entities, got %d: %v", len(doc.Entities), doc.CountByType())
+	}
+	if l := doc.Entities[0].(*Line); l.X1 != 10 || l.X2 != 20 {
+		t.Errorf("inside line changed: %+v", l)
+	}
+	clipped := doc.Entities[1].(*Line)
+	if clipped.X1 != 50 || clipped.Y1 != 50 || clipped.X2 != 100 || clipped.Y2 != 50 {
+		t.Errorf("clipped line: got (%v, %v)-(%v, %v), want (50, 50)-(100, 50)",
+			clipped.X1, clipped.Y1, clipped.X2, clipped.Y2)
+	}
+	if _, ok := doc.Entities[2].(*Insert); !ok {
+		t.Errorf("expected the insert to be kept, got %T", doc.Entities[2])
+	}
+}
+
+func TestCropWithOptions_KeepStraddling(t *testing.T) {
+	doc := NewDocument().
+		AddCircle(95, 50, 10).
+		AddCircle(500, 500, 10)
+
+	doc.CropWithOptions(0, 0, 100, 100, CropOptions{KeepStraddling: true})
+
+	if len(doc.Entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(doc.Entities))
+	}
+	if c := doc.Entities[0].(*Circle); c.CenterX != 95 || c.Radius != 10 {
+		t.Errorf("straddling circle changed: %+v", c)
+	}
+}
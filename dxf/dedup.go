@@ -0,0 +1,128 @@
+package dxf
+
+import (
+	"math"
+	"sort"
+)
+
+// Deduplicate removes model space entities that repeat an earlier entity
+// of the same type on the same layer with the same geometry, comparing
+// coordinates, radii, heights, and angles (in degrees) within tolerance.
+// Lines match in either direction, and texts also need the same content.
+// Of each set of duplicates the first in document order is kept.
+//
+// Lines, circles, arcs, points, texts, and solids are compared; other
+// entity types are never removed. It returns the number of entities removed.
+//
+// Example:
+//
+//	removed := doc.Deduplicate(1e-6) // drop copy-paste artifacts
+func (d *Document) Deduplicate(tolerance float64) (removed int) {
+	type candidate struct {
+		index int
+		group string // entity type and layer
+		minX  float64
+	}
+
+	var candidates []candidate
+	for i, entity := range d.Entities {
+		if !dedupable(entity) {
+			continue
+		}
+		layer, _ := layerOf(entity)
+		minX, _, _, _, _ := boundsOf(entity)
+		candidates = append(candidates, candidate{i, entity.EntityType() + "\x00" + layer, minX})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		if a.minX != b.minX {
+			return a.minX < b.minX
+		}
+		return a.index < b.index
+	})
+
+	// Duplicates have bounding boxes starting within tolerance of each
+	// other, so each entity is only compared with its sorted neighbors.
+	drop := make(map[int]bool)
+	for i, a := range candidates {
+		for _, b := range candidates[i+1:] {
+			if b.group != a.group || b.minX-a.minX > tolerance {
+				break
+			}
+			if drop[b.index] || drop[a.index] {
+				continue
+			}
+			if sameGeometry(d.Entities[a.index], d.Entities[b.index], tolerance) {
+				drop[max(a.index, b.index)] = true
+			}
+		}
+	}
+	if len(drop) == 0 {
+		return 0
+	}
+
+	kept := d.Entities[:0]
+	for i, entity := range d.Entities {
+		if !drop[i] {
+			kept = append(kept, entity)
+		}
+	}
+	for i := len(kept); i < len(d.Entities); i++ {
+		d.Entities[i] = nil // release dropped entities
+	}
+	d.Entities = kept
+
+	return len(drop)
+}
+
+// dedupable reports whether Deduplicate compares entities of this type.
+func dedupable(entity Entity) bool {
+	switch entity.(type) {
+	case *Line, *Circle, *Arc, *Point, *Text, *Solid:
+		return true
+	}
+	return false
+}
+
+// sameGeometry reports whether a and b, of the same type, have the same
+// geometry within tolerance.
+func sameGeometry(a, b Entity, tolerance float64) bool {
+	near := func(pairs ...float64) bool {
+		for i := 0; i < len(pairs); i += 2 {
+			if math.Abs(pairs[i]-pairs[i+1]) > tolerance {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch a := a.(type) {
+	case *Line:
+		b := b.(*Line)
+		return near(a.X1, b.X1, a.Y1, b.Y1, a.X2, b.X2, a.Y2, b.Y2) ||
+			near(a.X1, b.X2, a.Y1, b.Y2, a.X2, b.X1, a.Y2, b.Y1)
+	case *Circle:
+		b := b.(*Circle)
+		return near(a.CenterX, b.CenterX, a.CenterY, b.CenterY, a.Radius, b.Radius)
+	case *Arc:
+		b := b.(*Arc)
+		return near(a.CenterX, b.CenterX, a.CenterY, b.CenterY, a.Radius, b.Radius,
+			a.StartAngle, b.StartAngle, a.EndAngle, b.EndAngle)
+	case *Point:
+		b := b.(*Point)
+		return near(a.X, b.X, a.Y, b.Y)
+	case *Text:
+		b := b.(*Text)
+		return a.Content == b.Content && a.HAlign == b.HAlign && a.VAlign == b.VAlign &&
+			near(a.X, b.X, a.Y, b.Y, a.Height, b.Height, a.Rotation, b.Rotation,
+				a.AlignX, b.AlignX, a.AlignY, b.AlignY)
+	case *Solid:
+		b := b.(*Solid)
+		return near(a.X1, b.X1, a.Y1, b.Y1, a.X2, b.X2, a.Y2, b.Y2,
+			a.X3, b.X3, a.Y3, b.Y3, a.X4, b.X4, a.Y4, b.Y4)
+	}
+	return false
+}
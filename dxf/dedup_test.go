@@ -0,0 +1,79 @@
+package dxf
+
+import "testing"
+
+func TestDeduplicate(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *Document
+		removed int
+	}{
+		{
+			"identical lines",
+			NewDocument().AddLine(0, 0, 10, 10).AddLine(0, 0, 10, 10),
+			1,
+		},
+		{
+			"near-identical lines",
+			NewDocument().AddLine(0, 0, 10, 10).AddLine(0.0005, 0, 10, 9.9995),
+			1,
+		},
+		{
+			"reversed line",
+			NewDocument().AddLine(0, 0, 10, 10).AddLine(10, 10, 0, 0),
+			1,
+		},
+		{
+			"distinct lines",
+			NewDocument().AddLine(0, 0, 10, 10).AddLine(0, 0, 10, 11),
+			0,
+		},
+		{
+			"different layers",
+			NewDocument().AddLine(0, 0, 10, 10).AddLine(0, 0, 10, 10, WithLineLayer("B")),
+			0,
+		},
+		{
+			"different types",
+			NewDocument().AddCircle(5, 5, 1).AddArc(5, 5, 1, 0, 360),
+			0,
+		},
+		{
+			"three copies",
+			NewDocument().AddCircle(5, 5, 1).AddCircle(5, 5, 1).AddCircle(5, 5, 1),
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(tt.doc.Entities)
+			removed := tt.doc.Deduplicate(0.001)
+			if removed != tt.removed {
+				t.Errorf("removed: got %d, want %d", removed, tt.removed)
+			}
+			if len(tt.doc.Entities) != before-tt.removed {
+				t.Errorf("entities: got %d, want %d", len(tt.doc.Entities), before-tt.removed)
+			}
+		})
+	}
+}
+
+func TestDeduplicate_KeepsFirst(t *testing.T) {
+	doc := NewDocument().
+		AddLine(0, 0, 10, 0, WithLineColor(1)).
+		AddPoint(3, 3).
+		AddLine(0, 0, 10, 0, WithLineColor(2))
+
+	doc.Deduplicate(0)
+
+	if len(doc.Entities) != 2 {
+		t.Fatalf("expected 2 entities, got %d", len(doc.Entities))
+	}
+	if line := doc.Entities[0].(*Line); line.Color != 1 {
+		t.Errorf("expected the first line to be kept, got color %d", line.Color)
+	}
+	if _, ok := doc.Entities[1].(*Point); !ok {
+		t.Errorf("expected the point to keep its place, got %T", doc.Entities[1])
+	}
+}
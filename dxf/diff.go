@@ -0,0 +1,262 @@
+package dxf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// DiffKind classifies a Difference.
+type DiffKind int
+
+const (
+	// Added marks an entity, layer, or block only present in the second document.
+	Added DiffKind = iota
+	// Removed marks an entity, layer, or block only present in the first document.
+	Removed
+	// Changed marks a layer or entity present in both documents with different data.
+	Changed
+)
+
+// String returns "added", "removed", or "changed".
+func (k DiffKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return fmt.Sprintf("DiffKind(%d)", int(k))
+}
+
+// Difference describes one difference found by Diff.
+type Difference struct {
+	// Kind tells whether the item was added, removed, or changed.
+	Kind DiffKind
+
+	// Section locates the item: "layers", "model space", "paper space",
+	// "blocks" for whole blocks, or `block "NAME"` for block entities.
+	Section string
+
+	// Before and After are the entity in the first and second document.
+	// Before is nil for added entities and After for removed ones; both
+	// are nil for layer and block differences.
+	Before, After Entity
+
+	// Detail describes the item and, for changes, the first differing value.
+	Detail string
+}
+
+// String formats the difference as "<section>: <kind> <detail>".
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s %s", d.Section, d.Kind, d.Detail)
+}
+
+// DiffOptions controls optional behavior of DiffWithOptions.
+// The zero value matches Diff.
+type DiffOptions struct {
+	// Tolerance is the largest difference between two float values that
+	// still counts as equal. 0 uses 1e-9.
+	Tolerance float64
+}
+
+// defaultDiffTolerance is used when DiffOptions.Tolerance is 0.
+const defaultDiffTolerance = 1e-9
+
+// Diff compares two documents and reports added, removed, and changed
+// layers, blocks, and entities. It is equivalent to DiffWithOptions with
+// zero options. Identical documents yield no differences.
+//
+// Example:
+//
+//	for _, d := range dxf.Diff(golden, dxf.ConvertDocument(doc)) {
+//	    t.Error(d)
+//	}
+func Diff(a, b *Document) []Difference {
+	return DiffWithOptions(a, b, DiffOptions{})
+}
+
+// DiffWithOptions compares two documents like Diff, applying opts.
+//
+// Layers and blocks are matched by name. Entities of each section are
+// matched regardless of order: first an entity pairs with one of the same
+// type whose group codes all agree within the tolerance; the remaining
+// entities of the same type and layer then pair in document order and are
+// reported as changed, and any left over as added or removed.
+func DiffWithOptions(a, b *Document, opts DiffOptions) []Difference {
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultDiffTolerance
+	}
+
+	diffs := diffLayers(a.Layers, b.Layers)
+	diffs = append(diffs, diffEntities("model space", a.Entities, b.Entities, tolerance)...)
+	diffs = append(diffs, diffEntities("paper space", a.PaperSpaceEntities, b.PaperSpaceEntities, tolerance)...)
+	return append(diffs, diffBlocks(a.Blocks, b.Blocks, tolerance)...)
+}
+
+// diffLayers compares layer tables by layer name.
+func diffLayers(a, b []Layer) []Difference {
+	var diffs []Difference
+	inB := make(map[string]Layer, len(b))
+	for _, layer := range b {
+		inB[layer.Name] = layer
+	}
+	inA := make(map[string]bool, len(a))
+	for _, la := range a {
+		inA[la.Name] = true
+		lb, ok := inB[la.Name]
+		switch {
+		case !ok:
+			diffs = append(diffs, Difference{Kind: Removed, Section: "layers", Detail: "layer " + la.Name})
+		case la != lb:
+			diffs = append(diffs, Difference{Kind: Changed, Section: "layers",
+				Detail: fmt.Sprintf("layer %s: %+v -> %+v", la.Name, la, lb)})
+		}
+	}
+	for _, lb := range b {
+		if !inA[lb.Name] {
+			diffs = append(diffs, Difference{Kind: Added, Section: "layers", Detail: "layer " + lb.Name})
+		}
+	}
+	return diffs
+}
+
+// diffBlocks compares blocks by name, including their entities.
+func diffBlocks(a, b []Block, tolerance float64) []Difference {
+	var diffs []Difference
+	inB := make(map[string]*Block, len(b))
+	for i := range b {
+		inB[b[i].Name] = &b[i]
+	}
+	inA := make(map[string]bool, len(a))
+	for i := range a {
+		ba := &a[i]
+		inA[ba.Name] = true
+		bb, ok := inB[ba.Name]
+		if !ok {
+			diffs = append(diffs, Difference{Kind: Removed, Section: "blocks", Detail: "block " + ba.Name})
+			continue
+		}
+		section := fmt.Sprintf("block %q", ba.Name)
+		if math.Abs(ba.BaseX-bb.BaseX) > tolerance || math.Abs(ba.BaseY-bb.BaseY) > tolerance {
+			diffs = append(diffs, Difference{Kind: Changed, Section: section,
+				Detail: fmt.Sprintf("base point: (%v, %v) -> (%v, %v)", ba.BaseX, ba.BaseY, bb.BaseX, bb.BaseY)})
+		}
+		diffs = append(diffs, diffEntities(section, ba.Entities, bb.Entities, tolerance)...)
+	}
+	for i := range b {
+		if !inA[b[i].Name] {
+			diffs = append(diffs, Difference{Kind: Added, Section: "blocks", Detail: "block " + b[i].Name})
+		}
+	}
+	return diffs
+}
+
+// diffEntities matches the entities of one section and reports the rest.
+func diffEntities(section string, a, b []Entity, tolerance float64) []Difference {
+	codesA := make([][]GroupCode, len(a))
+	for i, e := range a {
+		codesA[i] = e.GroupCodes()
+	}
+	codesB := make([][]GroupCode, len(b))
+	for i, e := range b {
+		codesB[i] = e.GroupCodes()
+	}
+
+	// Equal entities have anchors within tolerance, so each entity of a is
+	// only compared with the entities of b near its anchor X.
+	order := make([]int, len(b))
+	anchorB := make([]float64, len(b))
+	for i := range b {
+		order[i] = i
+		anchorB[i], _ = anchorOf(b[i])
+	}
+	sort.Slice(order, func(i, j int) bool { return anchorB[order[i]] < anchorB[order[j]] })
+
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	for i, ea := range a {
+		x, _ := anchorOf(ea)
+		first := sort.Search(len(order), func(k int) bool { return anchorB[order[k]] >= x-tolerance })
+		for _, j := range order[first:] {
+			if anchorB[j] > x+tolerance {
+				break
+			}
+			if !matchedB[j] && ea.EntityType() == b[j].EntityType() &&
+				firstCodeDifference(codesA[i], codesB[j], tolerance) == "" {
+				matchedA[i], matchedB[j] = true, true
+				break
+			}
+		}
+	}
+
+	// Pair the remaining entities by type and layer in document order
+	key := func(e Entity) string {
+		layer, _ := layerOf(e)
+		return e.EntityType() + "\x00" + layer
+	}
+	pending := make(map[string][]int)
+	for j, eb := range b {
+		if !matchedB[j] {
+			pending[key(eb)] = append(pending[key(eb)], j)
+		}
+	}
+
+	var diffs []Difference
+	for i, ea := range a {
+		if matchedA[i] {
+			continue
+		}
+		k := key(ea)
+		if len(pending[k]) == 0 {
+			diffs = append(diffs, Difference{Kind: Removed, Section: section, Before: ea, Detail: describeEntity(ea)})
+			continue
+		}
+		j := pending[k][0]
+		pending[k] = pending[k][1:]
+		matchedB[j] = true
+		diffs = append(diffs, Difference{Kind: Changed, Section: section, Before: ea, After: b[j],
+			Detail: describeEntity(ea) + ": " + firstCodeDifference(codesA[i], codesB[j], tolerance)})
+	}
+	for j, eb := range b {
+		if !matchedB[j] {
+			diffs = append(diffs, Difference{Kind: Added, Section: section, After: eb, Detail: describeEntity(eb)})
+		}
+	}
+	return diffs
+}
+
+// describeEntity names an entity by type, layer, and anchor point.
+func describeEntity(e Entity) string {
+	x, y := anchorOf(e)
+	layer, _ := layerOf(e)
+	return fmt.Sprintf("%s on layer %s at (%g, %g)", e.EntityType(), layer, x, y)
+}
+
+// firstCodeDifference describes the first group code that differs between
+// a and b, or returns "" if they are equal within tolerance.
+func firstCodeDifference(a, b []GroupCode, tolerance float64) string {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := a[i], b[i]
+		if ca.Code != cb.Code {
+			return fmt.Sprintf("group code %d -> %d", ca.Code, cb.Code)
+		}
+		fa, aIsFloat := ca.Value.(float64)
+		fb, bIsFloat := cb.Value.(float64)
+		if aIsFloat && bIsFloat {
+			if math.Abs(fa-fb) > tolerance {
+				return fmt.Sprintf("group code %d: %v -> %v", ca.Code, fa, fb)
+			}
+		} else if !reflect.DeepEqual(ca.Value, cb.Value) {
+			return fmt.Sprintf("group code %d: %v -> %v", ca.Code, ca.Value, cb.Value)
+		}
+	}
+	if len(a) != len(b) {
+		return fmt.Sprintf("%d group codes -> %d", len(a), len(b))
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package dxf
+
+import "testing"
+
+func TestDiff_Identical(t *testing.T) {
+	build := func() *Document {
+		return NewDocument().
+			AddLayer("A", 1, "CONTINUOUS").
+			AddLine(0, 0, 10, 10, WithLineLayer("A")).
+			AddCircle(5, 5, 2).
+			AddText(1, 1, "注記")
+	}
+	a, b := build(), build()
+	b.Entities[0], b.Entities[2] = b.Entities[2], b.Entities[0] // order does not matter
+
+	if diffs := Diff(a, b); len(diffs) != 0 {
+		t.Errorf("expected no differences, got %v", diffs)
+	}
+}
+
+func TestDiff_AddedEntity(t *testing.T) {
+	a := NewDocument().AddLine(0, 0, 10, 0)
+	b := NewDocument().AddLine(0, 0, 10, 0).AddCircle(5, 5, 1)
+
+	diffs := Diff(a, b)
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 difference, got %v", diffs)
+	}
+	d := diffs[0]
+	if d.Kind != Added || d.Section != "model space" || d.Before != nil || d.After != b.Entities[1] {
+		t.Errorf("got %+v", d)
+	}
+
+	if diffs := Diff(b, a); len(diffs) != 1 || diffs[0].Kind != Removed {
+		t.Errorf("reverse: got %v", diffs)
+	}
+}
+
+func TestDiff_MovedEntity(t *testing.T) {
+	a := NewDocument().AddLine(0, 0, 10, 0).AddCircle(5, 5, 1)
+	b := NewDocument().AddLine(0, 0, 10, 0).AddCircle(5, 6, 1)
+
+	diffs := Diff(a, b)
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 difference, got %v", diffs)
+	}
+	d := diffs[0]
+	if d.Kind != Changed || d.Before != a.Entities[1] || d.After != b.Entities[1] {
+		t.Errorf("got %+v", d)
+	}
+	if want := "model space: changed CIRCLE on layer 0 at (5, 5): group code 20: 5 -> 6"; d.String() != want {
+		t.Errorf("got %q, want %q", d.String(), want)
+	}
+
+	// Within tolerance the circle has not moved
+	b.Entities[1].(*Circle).CenterY = 5.0001
+	if diffs := DiffWithOptions(a, b, DiffOptions{Tolerance: 0.001}); len(diffs) != 0 {
+		t.Errorf("expected no differences within tolerance, got %v", diffs)
+	}
+}
+
+func TestDiff_LayersAndBlocks(t *testing.T) {
+	a := NewDocument().
+		AddLayer("A", 1, "CONTINUOUS").
+		AddLayer("B", 2, "CONTINUOUS").
+		AddBlock(Block{Name: "X", Entities: []Entity{NewPoint(0, 0)}})
+	b := NewDocument().
+		AddLayer("A", 3, "CONTINUOUS").
+		AddLayer("C", 2, "CONTINUOUS").
+		AddBlock(Block{Name: "X"}).
+		AddBlock(Block{Name: "Y"})
+
+	want := []string{
+		"layers: changed layer A: {Name:A Color:1 LineType:CONTINUOUS Frozen:false Locked:false} -> {Name:A Color:3 LineType:CONTINUOUS Frozen:false Locked:false}",
+		"layers: removed layer B",
+		"layers: added layer C",
+		`block "X": removed POINT on layer 0 at (0, 0)`,
+		"blocks: added block Y",
+	}
+	diffs := Diff(a, b)
+	if len(diffs) != len(want) {
+		t.Fatalf("expected %d differences, got %v", len(want), diffs)
+	}
+	for i, d := range diffs {
+		if d.String() != want[i] {
+			t.Errorf("difference %d: got %q, want %q", i, d, want[i])
+		}
+	}
+}
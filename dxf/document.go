@@ -210,6 +210,34 @@ func (d *Document) HasLayer(name string) bool {
 	return d.GetLayer(name) != nil
 }
 
+// ResolveColor returns the ACI color e is drawn in: its own color, or the
+// color of its layer when it is BYLAYER (0) or BYBLOCK (256). BYBLOCK
+// entities inside blocks take the color of each insert in CAD software,
+// which a single entity does not know, so they get the layer color too.
+// Layers missing from the table or without a valid color, and entity
+// types without a color, resolve to 7 (white/black), the default.
+//
+// Example:
+//
+//	doc := dxf.NewDocument().AddLayer("Walls", 1, "CONTINUOUS")
+//	line := dxf.NewLine(0, 0, 10, 0, dxf.WithLineLayer("Walls"))
+//	color := doc.ResolveColor(line) // Returns 1
+func (d *Document) ResolveColor(e Entity) int {
+	color := colorOf(e)
+	if color == nil {
+		return 7
+	}
+	if *color != 0 && *color != 256 {
+		return *color
+	}
+	if layer, ok := layerOf(e); ok {
+		if l := d.GetLayer(layer); l != nil && l.Color >= 1 && l.Color <= 255 {
+			return l.Color
+		}
+	}
+	return 7
+}
+
 // GetBlock returns a block by name, or nil if not found.
 //
 // Example:
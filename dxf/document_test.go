@@ -289,3 +289,24 @@ func TestDocumentFluentAPI(t *testing.T) {
 		t.Errorf("Expected 4 entities, got %d", doc.EntityCount())
 	}
 }
+
+func TestDocumentResolveColor(t *testing.T) {
+	doc := NewDocument().AddLayer("Walls", 1, "CONTINUOUS")
+
+	tests := []struct {
+		name   string
+		entity Entity
+		want   int
+	}{
+		{"BYLAYER", NewLine(0, 0, 10, 0, WithLineLayer("Walls")), 1},
+		{"BYBLOCK", &Circle{Layer: "Walls", Color: 256, Radius: 1}, 1},
+		{"explicit color", NewLine(0, 0, 10, 0, WithLineLayer("Walls"), WithLineColor(3)), 3},
+		{"default layer", NewLine(0, 0, 10, 0), 7},
+		{"undefined layer", &Text{Layer: "missing", Content: "A"}, 7},
+	}
+	for _, tt := range tests {
+		if got := doc.ResolveColor(tt.entity); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -39,6 +39,18 @@ func (l *Line) MidPoint() (x, y float64) {
 	return (l.X1 + l.X2) / 2, (l.Y1 + l.Y2) / 2
 }
 
+// PointAt returns the point at parameter t along the line, where t = 0 is
+// the start point and t = 1 the end point. Values outside [0, 1]
+// extrapolate along the line.
+//
+// Example:
+//
+//	line := dxf.NewLine(0, 0, 100, 0)
+//	x, y := line.PointAt(0.25) // Returns (25, 0)
+func (l *Line) PointAt(t float64) (x, y float64) {
+	return l.X1 + t*(l.X2-l.X1), l.Y1 + t*(l.Y2-l.Y1)
+}
+
 // Angle returns the angle of the line in degrees (0-360).
 // 0 degrees is to the right (positive X axis).
 //
@@ -166,66 +178,193 @@ func (a *Arc) BoundingBox() (minX, minY, maxX, maxY float64) {
 
 // containsAngle checks if the arc contains a specific angle.
 func (a *Arc) containsAngle(angle float64) bool {
-	start := a.StartAngle
-	end := a.EndAngle
+	if a.EndAngle-a.StartAngle >= 360 {
+		return true // full turn; normalization would collapse it to zero sweep
+	}
+
+	start := normalizeDeg(a.StartAngle)
+	end := normalizeDeg(a.EndAngle)
+	angle = normalizeDeg(angle)
 
-	// Normalize angles to 0-360
-	for start < 0 {
-		start += 360
+	if start <= end {
+		return angle >= start && angle <= end
 	}
-	for start >= 360 {
-		start -= 360
+	// Arc crosses 0°
+	return angle >= start || angle <= end
+}
+
+// ToArc returns the circle as a full 0-360° arc with the same center,
+// radius, and style.
+//
+// Example:
+//
+//	arc := dxf.NewCircle(50, 50, 25).ToArc()
+func (c *Circle) ToArc() *Arc {
+	return &Arc{
+		Layer:         c.Layer,
+		Color:         c.Color,
+		LineType:      c.LineType,
+		LineTypeScale: c.LineTypeScale,
+		CenterX:       c.CenterX,
+		CenterY:       c.CenterY,
+		Radius:        c.Radius,
+		StartAngle:    0,
+		EndAngle:      360,
 	}
-	for end < 0 {
-		end += 360
+}
+
+// ToEllipse returns the circle as a full ellipse with a minor ratio of 1.
+//
+// Example:
+//
+//	ellipse := dxf.NewCircle(50, 50, 25).ToEllipse()
+func (c *Circle) ToEllipse() *Ellipse {
+	return c.ToArc().ToEllipse()
+}
+
+// ToEllipse returns the arc as an elliptical arc with a minor ratio of 1.
+// The major axis points along +X, so the start and end parameters are the
+// arc angles in radians.
+//
+// Example:
+//
+//	ellipse := dxf.NewArc(50, 50, 25, 0, 90).ToEllipse()
+func (a *Arc) ToEllipse() *Ellipse {
+	return &Ellipse{
+		Layer:         a.Layer,
+		Color:         a.Color,
+		LineType:      a.LineType,
+		LineTypeScale: a.LineTypeScale,
+		CenterX:       a.CenterX,
+		CenterY:       a.CenterY,
+		MajorAxisX:    a.Radius,
+		MajorAxisY:    0,
+		MinorRatio:    1,
+		StartParam:    a.StartAngle * math.Pi / 180.0,
+		EndParam:      a.EndAngle * math.Pi / 180.0,
 	}
-	for end >= 360 {
-		end -= 360
+}
+
+// Flatten approximates the arc with a polyline whose chords deviate from
+// the arc by at most maxSagitta. The first and last vertices are the exact
+// arc endpoints. A maxSagitta <= 0 uses 0.1% of the radius.
+//
+// Example:
+//
+//	arc := dxf.NewArc(0, 0, 10, 0, 90)
+//	vertices := arc.Flatten(0.01)
+func (a *Arc) Flatten(maxSagitta float64) []Vertex {
+	start := a.StartAngle * math.Pi / 180.0
+	sweep := (a.EndAngle - a.StartAngle) * math.Pi / 180.0
+	sweep = math.Mod(sweep, 2*math.Pi)
+	if sweep <= 0 {
+		sweep += 2 * math.Pi
 	}
-	for angle < 0 {
-		angle += 360
+
+	return flattenCurve(start, sweep, a.Radius, maxSagitta, func(t float64) Vertex {
+		return Vertex{
+			X: a.CenterX + a.Radius*math.Cos(t),
+			Y: a.CenterY + a.Radius*math.Sin(t),
+		}
+	})
+}
+
+// Flatten approximates the ellipse (or elliptical arc) with a polyline whose
+// chords deviate from the curve by at most maxSagitta. The first and last
+// vertices are the exact endpoints; for a full ellipse they coincide.
+// A maxSagitta <= 0 uses 0.1% of the major radius.
+//
+// Example:
+//
+//	ellipse := &dxf.Ellipse{MajorAxisX: 100, MinorRatio: 0.5, EndParam: 2 * math.Pi}
+//	vertices := ellipse.Flatten(0.01)
+func (e *Ellipse) Flatten(maxSagitta float64) []Vertex {
+	sweep := e.EndParam - e.StartParam
+	if sweep <= 0 {
+		sweep += 2 * math.Pi
+	}
+
+	// The ellipse is a circle of the major radius scaled down along the minor
+	// axis, which can only shrink chord deviations, so the circle step is safe.
+	majorRadius := math.Hypot(e.MajorAxisX, e.MajorAxisY)
+	minorX, minorY := -e.MajorAxisY*e.MinorRatio, e.MajorAxisX*e.MinorRatio
+
+	return flattenCurve(e.StartParam, sweep, majorRadius, maxSagitta, func(t float64) Vertex {
+		cos, sin := math.Cos(t), math.Sin(t)
+		return Vertex{
+			X: e.CenterX + e.MajorAxisX*cos + minorX*sin,
+			Y: e.CenterY + e.MajorAxisY*cos + minorY*sin,
+		}
+	})
+}
+
+// flattenCurve samples point(t) for t from start to start+sweep in equal steps
+// small enough that a circle of the given radius deviates from each chord by
+// at most maxSagitta. The final vertex is evaluated at the exact end parameter.
+func flattenCurve(start, sweep, radius, maxSagitta float64, point func(t float64) Vertex) []Vertex {
+	if maxSagitta <= 0 {
+		maxSagitta = radius * 0.001
+	}
+
+	// Sagitta of a chord spanning angle θ: s = r(1 - cos(θ/2))
+	step := math.Pi
+	if radius > 0 && maxSagitta < radius {
+		step = 2 * math.Acos(1-maxSagitta/radius)
 	}
-	for angle >= 360 {
-		angle -= 360
+	n := int(math.Ceil(sweep / step))
+	if n < 1 {
+		n = 1
 	}
 
-	if start <= end {
-		return angle >= start && angle <= end
+	vertices := make([]Vertex, n+1)
+	for i := 0; i < n; i++ {
+		vertices[i] = point(start + sweep*float64(i)/float64(n))
 	}
-	// Arc crosses 0°
-	return angle >= start || angle <= end
+	vertices[n] = point(start + sweep)
+	return vertices
 }
 
 // BoundingBox returns the bounding box of an Ellipse entity.
-// Returns (minX, minY, maxX, maxY).
+// Returns (minX, minY, maxX, maxY). For an elliptical arc only the swept
+// portion from StartParam to EndParam counts: its endpoints and the axis
+// extrema that fall within it.
 //
 // Example:
 //
 //	ellipse := &dxf.Ellipse{CenterX: 50, CenterY: 50, MajorAxisX: 100, MajorAxisY: 0, MinorRatio: 0.5}
 //	minX, minY, maxX, maxY := ellipse.BoundingBox()
 func (e *Ellipse) BoundingBox() (minX, minY, maxX, maxY float64) {
-	// Calculate major axis length
-	majorLength := math.Sqrt(e.MajorAxisX*e.MajorAxisX + e.MajorAxisY*e.MajorAxisY)
-	minorLength := majorLength * e.MinorRatio
-
-	// Get angle of major axis
-	tilt := math.Atan2(e.MajorAxisY, e.MajorAxisX)
-	cos := math.Cos(tilt)
-	sin := math.Sin(tilt)
-
-	// Calculate bounding box considering rotation
-	a := majorLength * cos
-	b := minorLength * sin
-	c := majorLength * sin
-	d := minorLength * cos
-
-	halfWidth := math.Sqrt(a*a + b*b)
-	halfHeight := math.Sqrt(c*c + d*d)
-
-	minX = e.CenterX - halfWidth
-	maxX = e.CenterX + halfWidth
-	minY = e.CenterY - halfHeight
-	maxY = e.CenterY + halfHeight
+	sweep := e.EndParam - e.StartParam
+	if sweep <= 0 {
+		sweep += 2 * math.Pi
+	}
+
+	minorX, minorY := -e.MajorAxisY*e.MinorRatio, e.MajorAxisX*e.MinorRatio
+	point := func(t float64) (float64, float64) {
+		cos, sin := math.Cos(t), math.Sin(t)
+		return e.CenterX + e.MajorAxisX*cos + minorX*sin, e.CenterY + e.MajorAxisY*cos + minorY*sin
+	}
+
+	// Start with the endpoints
+	minX, minY = point(e.StartParam)
+	maxX, maxY = minX, minY
+	extend := func(x, y float64) {
+		minX = math.Min(minX, x)
+		maxX = math.Max(maxX, x)
+		minY = math.Min(minY, y)
+		maxY = math.Max(maxY, y)
+	}
+	extend(point(e.StartParam + sweep))
+
+	// X is extreme where its derivative -MajorAxisX*sin(t) + minorX*cos(t)
+	// vanishes, and likewise Y; each has two solutions half a turn apart
+	tx := math.Atan2(minorX, e.MajorAxisX)
+	ty := math.Atan2(minorY, e.MajorAxisY)
+	for _, t := range [4]float64{tx, tx + math.Pi, ty, ty + math.Pi} {
+		if sweep >= 2*math.Pi || normalizeRad(t-e.StartParam) <= sweep {
+			extend(point(t))
+		}
+	}
 	return
 }
 
@@ -240,8 +379,8 @@ func (p *Point) BoundingBox() (minX, minY, maxX, maxY float64) {
 	return p.X, p.Y, p.X, p.Y
 }
 
-// BoundingBox returns the approximate bounding box of a Text entity.
-// Note: This is a simplified calculation that doesn't account for actual font metrics.
+// BoundingBox returns the approximate bounding box of a Text entity,
+// measuring the text width with DefaultTextMetrics.
 // Returns (minX, minY, maxX, maxY).
 //
 // Example:
@@ -249,11 +388,57 @@ func (p *Point) BoundingBox() (minX, minY, maxX, maxY float64) {
 //	text := dxf.NewText(10, 10, "Hello", dxf.WithTextHeight(5))
 //	minX, minY, maxX, maxY := text.BoundingBox()
 func (t *Text) BoundingBox() (minX, minY, maxX, maxY float64) {
-	// Simplified: estimate width as height * length * 0.6 (typical aspect ratio)
-	estimatedWidth := t.Height * float64(len(t.Content)) * 0.6
+	return t.BoundingBoxWithMetrics(DefaultTextMetrics)
+}
+
+// BoundingBoxWithMetrics returns the approximate bounding box of a Text entity,
+// measuring the text width with the given metrics provider. Justified text
+// is placed relative to its alignment point; aligned and fit text spans
+// from the insertion point to the alignment point.
+// Returns (minX, minY, maxX, maxY).
+//
+// Example:
+//
+//	text := dxf.NewText(10, 10, "図面", dxf.WithTextHeight(5))
+//	minX, minY, maxX, maxY := text.BoundingBoxWithMetrics(dxf.MonospaceMetrics{CharWidth: 0.6})
+func (t *Text) BoundingBoxWithMetrics(metrics TextMetrics) (minX, minY, maxX, maxY float64) {
+	// Multi-line text is as wide as its longest line and extends
+	// downward from the first line's baseline.
+	lines := t.Lines()
+	estimatedWidth := 0.0
+	for _, line := range lines {
+		estimatedWidth = math.Max(estimatedWidth, metrics.TextWidth(line, t.Height))
+	}
+	bottom := -t.Height * TextLineSpacing * float64(len(lines)-1)
+
+	// Justified text is placed relative to the alignment point: left is the
+	// offset of the text start and shift that of the baseline from it
+	originX, originY := t.X, t.Y
+	left, shift := 0.0, 0.0
+	if t.justified() && t.HAlign != TextHAlignAligned && t.HAlign != TextHAlignFit {
+		originX, originY = t.AlignX, t.AlignY
+	}
+	switch t.HAlign {
+	case TextHAlignCenter:
+		left = -estimatedWidth / 2
+	case TextHAlignRight:
+		left = -estimatedWidth
+	case TextHAlignMiddle:
+		left, shift = -estimatedWidth/2, -t.Height/2
+	case TextHAlignAligned, TextHAlignFit:
+		estimatedWidth = math.Hypot(t.AlignX-t.X, t.AlignY-t.Y)
+	}
+	if t.HAlign <= TextHAlignRight {
+		switch t.VAlign {
+		case TextVAlignMiddle:
+			shift = -t.Height / 2
+		case TextVAlignTop:
+			shift = -t.Height
+		}
+	}
 
 	if t.Rotation == 0 {
-		return t.X, t.Y, t.X + estimatedWidth, t.Y + t.Height
+		return originX + left, originY + shift + bottom, originX + left + estimatedWidth, originY + shift + t.Height
 	}
 
 	// For rotated text, calculate the corners and find min/max
@@ -263,18 +448,18 @@ func (t *Text) BoundingBox() (minX, minY, maxX, maxY float64) {
 
 	// Four corners of the text box
 	corners := [][2]float64{
-		{0, 0},
-		{estimatedWidth, 0},
-		{estimatedWidth, t.Height},
-		{0, t.Height},
+		{left, shift + bottom},
+		{left + estimatedWidth, shift + bottom},
+		{left + estimatedWidth, shift + t.Height},
+		{left, shift + t.Height},
 	}
 
 	minX, minY = math.Inf(1), math.Inf(1)
 	maxX, maxY = math.Inf(-1), math.Inf(-1)
 
 	for _, corner := range corners {
-		x := t.X + corner[0]*cos - corner[1]*sin
-		y := t.Y + corner[0]*sin + corner[1]*cos
+		x := originX + corner[0]*cos - corner[1]*sin
+		y := originY + corner[0]*sin + corner[1]*cos
 		minX = math.Min(minX, x)
 		maxX = math.Max(maxX, x)
 		minY = math.Min(minY, y)
@@ -299,23 +484,97 @@ func (s *Solid) BoundingBox() (minX, minY, maxX, maxY float64) {
 	return
 }
 
-// Area calculates the area of a Solid entity using the Shoelace formula.
+// Area calculates the area of a Solid entity using the Shoelace formula
+// over the outline DXF draws, which visits the corners in the order 1, 2, 4, 3.
+// A bowtie (self-intersecting) solid has its halves partly cancel out;
+// call NormalizeWinding first to avoid that.
 //
 // Example:
 //
 //	solid := dxf.NewSolid(0, 0, 100, 0, 50, 100, 50, 100)
 //	area := solid.Area()
 func (s *Solid) Area() float64 {
-	// Shoelace formula for a quadrilateral
-	// Area = 0.5 * |x1(y2-y4) + x2(y3-y1) + x3(y4-y2) + x4(y1-y3)|
+	// Shoelace formula for the quadrilateral 1-2-4-3
+	// Area = 0.5 * |x1(y2-y3) + x2(y4-y1) + x4(y3-y2) + x3(y1-y4)|
 	area := 0.5 * math.Abs(
-		s.X1*(s.Y2-s.Y4)+
-			s.X2*(s.Y3-s.Y1)+
-			s.X3*(s.Y4-s.Y2)+
-			s.X4*(s.Y1-s.Y3))
+		s.X1*(s.Y2-s.Y3)+
+			s.X2*(s.Y4-s.Y1)+
+			s.X4*(s.Y3-s.Y2)+
+			s.X3*(s.Y1-s.Y4))
 	return area
 }
 
+// Centroid returns the area-weighted centroid of the outline DXF draws
+// (corners 1, 2, 4, 3), which for a triangle is the mean of its corners.
+// A solid without area returns the mean of its four corners.
+//
+// Example:
+//
+//	solid := dxf.NewSolid(0, 0, 1, 0, 0, 1, 1, 1)
+//	x, y := solid.Centroid() // Returns (0.5, 0.5)
+func (s *Solid) Centroid() (x, y float64) {
+	xs := [4]float64{s.X1, s.X2, s.X4, s.X3}
+	ys := [4]float64{s.Y1, s.Y2, s.Y4, s.Y3}
+
+	var area2, cx, cy float64 // area2 is twice the signed area
+	for i := range xs {
+		j := (i + 1) % len(xs)
+		cross := xs[i]*ys[j] - xs[j]*ys[i]
+		area2 += cross
+		cx += (xs[i] + xs[j]) * cross
+		cy += (ys[i] + ys[j]) * cross
+	}
+	if math.Abs(area2) < 1e-12 {
+		return (s.X1 + s.X2 + s.X3 + s.X4) / 4, (s.Y1 + s.Y2 + s.Y3 + s.Y4) / 4
+	}
+	return cx / (3 * area2), cy / (3 * area2)
+}
+
+// NormalizeWinding reorders the corners of a quadrilateral solid so that
+// its DXF outline (1, 2, 4, 3) does not intersect itself. Corners given in
+// perimeter order (1, 2, 3, 4), which DXF draws as an hourglass, get
+// corners 3 and 4 swapped. Triangles and well-ordered solids are left
+// unchanged. Returns the solid for chaining.
+//
+// Example:
+//
+//	// Perimeter order renders as a bowtie in DXF
+//	solid := dxf.NewSolid(0, 0, 100, 0, 100, 100, 0, 100).NormalizeWinding()
+//	// solid.X3, solid.Y3 is now (0, 100)
+func (s *Solid) NormalizeWinding() *Solid {
+	if s.IsTriangle() || !s.selfIntersecting() {
+		return s
+	}
+
+	s.X3, s.Y3, s.X4, s.Y4 = s.X4, s.Y4, s.X3, s.Y3
+	if s.selfIntersecting() {
+		// Corners 1 and 2 are diagonal to each other: restore and swap 2/4
+		s.X3, s.Y3, s.X4, s.Y4 = s.X4, s.Y4, s.X3, s.Y3
+		s.X2, s.Y2, s.X4, s.Y4 = s.X4, s.Y4, s.X2, s.Y2
+	}
+	return s
+}
+
+// selfIntersecting reports whether the DXF outline 1-2-4-3 of the solid
+// crosses itself.
+func (s *Solid) selfIntersecting() bool {
+	return segmentsCross(s.X1, s.Y1, s.X2, s.Y2, s.X4, s.Y4, s.X3, s.Y3) ||
+		segmentsCross(s.X2, s.Y2, s.X4, s.Y4, s.X3, s.Y3, s.X1, s.Y1)
+}
+
+// segmentsCross reports whether segments p1-p2 and p3-p4 properly intersect,
+// that is, cross at a single point interior to both.
+func segmentsCross(x1, y1, x2, y2, x3, y3, x4, y4 float64) bool {
+	orient := func(ax, ay, bx, by, cx, cy float64) float64 {
+		return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+	}
+	d1 := orient(x3, y3, x4, y4, x1, y1)
+	d2 := orient(x3, y3, x4, y4, x2, y2)
+	d3 := orient(x1, y1, x2, y2, x3, y3)
+	d4 := orient(x1, y1, x2, y2, x4, y4)
+	return d1*d2 < 0 && d3*d4 < 0
+}
+
 // IsTriangle checks if a Solid entity is a triangle (4th point equals 3rd point).
 //
 // Example:
@@ -326,6 +585,137 @@ func (s *Solid) IsTriangle() bool {
 	return s.X3 == s.X4 && s.Y3 == s.Y4
 }
 
+// BoundingBox returns the bounding box of an LWPolyline entity's vertices.
+// Returns (minX, minY, maxX, maxY), or all zeros for a polyline without vertices.
+func (p *LWPolyline) BoundingBox() (minX, minY, maxX, maxY float64) {
+	if len(p.Vertices) == 0 {
+		return 0, 0, 0, 0
+	}
+	minX, minY = p.Vertices[0].X, p.Vertices[0].Y
+	maxX, maxY = minX, minY
+	for _, v := range p.Vertices[1:] {
+		minX = math.Min(minX, v.X)
+		maxX = math.Max(maxX, v.X)
+		minY = math.Min(minY, v.Y)
+		maxY = math.Max(maxY, v.Y)
+	}
+	return
+}
+
+// BoundingBox returns the bounding box of a Hatch entity's boundary vertices.
+// Returns (minX, minY, maxX, maxY), or all zeros for a hatch without vertices.
+func (h *Hatch) BoundingBox() (minX, minY, maxX, maxY float64) {
+	var vertices []Vertex
+	for _, boundary := range h.Boundaries {
+		vertices = append(vertices, boundary...)
+	}
+	return (&LWPolyline{Vertices: vertices}).BoundingBox()
+}
+
+// BoundingBox returns the bounding box of a Dimension entity's measured
+// points and the entities drawing it.
+// Returns (minX, minY, maxX, maxY).
+func (d *Dimension) BoundingBox() (minX, minY, maxX, maxY float64) {
+	vertices := []Vertex{{d.X1, d.Y1}, {d.X2, d.Y2}}
+	for _, e := range d.Entities {
+		if x0, y0, x1, y1, ok := boundsOf(e); ok {
+			vertices = append(vertices, Vertex{x0, y0}, Vertex{x1, y1})
+		}
+	}
+	return (&LWPolyline{Vertices: vertices}).BoundingBox()
+}
+
+// Sample evaluates the spline at segments+1 evenly spaced parameter values
+// using de Boor's algorithm. The first and last vertices are the curve's
+// endpoints, which coincide with the end control points for clamped knots.
+//
+// Example:
+//
+//	spline := &dxf.Spline{Degree: 3, ControlPoints: []dxf.Vertex{{0, 0}, {10, 20}, {30, 20}, {40, 0}}}
+//	vertices := spline.Sample(32)
+func (s *Spline) Sample(segments int) []Vertex {
+	n := len(s.ControlPoints)
+	if n < 2 {
+		return append([]Vertex(nil), s.ControlPoints...)
+	}
+	if segments < 1 {
+		segments = 1
+	}
+
+	p := s.degree()
+	knots := s.knotVector()
+	if len(knots) != n+p+1 {
+		// Malformed knot vector: fall back to the control polygon
+		return append([]Vertex(nil), s.ControlPoints...)
+	}
+
+	u0, u1 := knots[p], knots[n]
+	vertices := make([]Vertex, segments+1)
+	for i := range vertices {
+		u := u0 + (u1-u0)*float64(i)/float64(segments)
+		vertices[i] = s.evaluate(u, p, knots)
+	}
+	return vertices
+}
+
+// evaluate returns the point at parameter u using de Boor's algorithm.
+func (s *Spline) evaluate(u float64, p int, knots []float64) Vertex {
+	n := len(s.ControlPoints)
+
+	// Find the knot span k with knots[k] <= u < knots[k+1], using the last
+	// non-empty span for the end parameter.
+	k := p
+	for k < n-1 && u >= knots[k+1] {
+		k++
+	}
+
+	d := make([]Vertex, p+1)
+	copy(d, s.ControlPoints[k-p:k+1])
+	for r := 1; r <= p; r++ {
+		for j := p; j >= r; j-- {
+			i := k - p + j
+			denom := knots[i+p-r+1] - knots[i]
+			alpha := 0.0
+			if denom != 0 {
+				alpha = (u - knots[i]) / denom
+			}
+			d[j] = Vertex{
+				X: (1-alpha)*d[j-1].X + alpha*d[j].X,
+				Y: (1-alpha)*d[j-1].Y + alpha*d[j].Y,
+			}
+		}
+	}
+	return d[p]
+}
+
+// BoundingBox returns the bounding box of a Spline entity's control points.
+// By the convex hull property this box contains the whole curve, though it
+// may be larger than the curve itself.
+// Returns (minX, minY, maxX, maxY).
+func (s *Spline) BoundingBox() (minX, minY, maxX, maxY float64) {
+	return (&LWPolyline{Vertices: s.ControlPoints}).BoundingBox()
+}
+
+// BoundingBox returns the bounding box of an Image entity,
+// taking its rotation around the lower-left corner into account.
+// Returns (minX, minY, maxX, maxY).
+func (i *Image) BoundingBox() (minX, minY, maxX, maxY float64) {
+	angle := i.Rotation * math.Pi / 180.0
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	xs := [4]float64{0, i.Width * cos, i.Width*cos - i.Height*sin, -i.Height * sin}
+	ys := [4]float64{0, i.Width * sin, i.Width*sin + i.Height*cos, i.Height * cos}
+
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for k := range xs {
+		minX = math.Min(minX, i.X+xs[k])
+		maxX = math.Max(maxX, i.X+xs[k])
+		minY = math.Min(minY, i.Y+ys[k])
+		maxY = math.Max(maxY, i.Y+ys[k])
+	}
+	return
+}
+
 // BoundingBox returns the bounding box of the entire Document.
 // Returns (minX, minY, maxX, maxY) encompassing all entities.
 //
@@ -342,24 +732,8 @@ func (d *Document) BoundingBox() (minX, minY, maxX, maxY float64) {
 	maxX, maxY = math.Inf(-1), math.Inf(-1)
 
 	for _, entity := range d.Entities {
-		var eMinX, eMinY, eMaxX, eMaxY float64
-
-		switch e := entity.(type) {
-		case *Line:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		case *Circle:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		case *Arc:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		case *Ellipse:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		case *Point:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		case *Text:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		case *Solid:
-			eMinX, eMinY, eMaxX, eMaxY = e.BoundingBox()
-		default:
+		eMinX, eMinY, eMaxX, eMaxY, ok := boundsOf(entity)
+		if !ok {
 			continue
 		}
 
@@ -372,6 +746,123 @@ func (d *Document) BoundingBox() (minX, minY, maxX, maxY float64) {
 	return
 }
 
+// ComputeExtents returns the extents of the model space entities like
+// BoundingBox, but resolves inserts to the geometry of their blocks,
+// transformed by the insertion point, scale, and rotation. Nested inserts
+// are resolved recursively; inserts of missing blocks and recursive block
+// references contribute nothing. The writer uses it for $EXTMIN/$EXTMAX.
+//
+// Entities are transformed like Document.Transform, so the extents are exact
+// for uniformly scaled inserts. Under non-uniform scaling the transformed
+// corners of each entity's own bounding box are used, which may overestimate.
+//
+// Example:
+//
+//	minX, minY, maxX, maxY := doc.ComputeExtents()
+func (d *Document) ComputeExtents() (minX, minY, maxX, maxY float64) {
+	minX, minY, maxX, maxY, ok := d.extents()
+	if !ok {
+		return 0, 0, 0, 0
+	}
+	return minX, minY, maxX, maxY
+}
+
+// extents computes ComputeExtents; ok is false if no entity has a bounding box.
+func (d *Document) extents() (minX, minY, maxX, maxY float64, ok bool) {
+	e := extentsBuilder{doc: d, active: make(map[string]bool)}
+	e.minX, e.minY = math.Inf(1), math.Inf(1)
+	e.maxX, e.maxY = math.Inf(-1), math.Inf(-1)
+	e.add(d.Entities, IdentityMatrix())
+	return e.minX, e.minY, e.maxX, e.maxY, e.ok
+}
+
+// extentsBuilder accumulates the extents of transformed entities.
+type extentsBuilder struct {
+	doc                    *Document
+	active                 map[string]bool // blocks being resolved, to stop recursion
+	minX, minY, maxX, maxY float64
+	ok                     bool
+}
+
+// add extends the extents by entities transformed by m.
+func (e *extentsBuilder) add(entities []Entity, m Matrix2D) {
+	identity := m == IdentityMatrix()
+	for _, entity := range entities {
+		if insert, isInsert := entity.(*Insert); isInsert {
+			block := e.doc.GetBlock(insert.BlockName)
+			if block == nil || e.active[block.Name] {
+				continue
+			}
+			e.active[block.Name] = true
+			e.add(block.Entities, insertMatrix(insert, block).Then(m))
+			delete(e.active, block.Name)
+			continue
+		}
+
+		exact := identity || m.conformal()
+		if exact && !identity {
+			clone := cloneEntities([]Entity{entity})
+			transformEntities(clone, m)
+			entity = clone[0]
+		}
+		minX, minY, maxX, maxY, ok := boundsOf(entity)
+		if !ok {
+			continue
+		}
+		if exact {
+			e.extend(minX, minY)
+			e.extend(maxX, maxY)
+			continue
+		}
+		for _, corner := range [4][2]float64{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}} {
+			e.extend(m.Apply(corner[0], corner[1]))
+		}
+	}
+}
+
+// extend grows the extents to include (x, y).
+func (e *extentsBuilder) extend(x, y float64) {
+	e.minX = math.Min(e.minX, x)
+	e.minY = math.Min(e.minY, y)
+	e.maxX = math.Max(e.maxX, x)
+	e.maxY = math.Max(e.maxY, y)
+	e.ok = true
+}
+
+// boundsOf returns the bounding box of a known entity type.
+// ok is false for inserts and unrecognized entity types.
+func boundsOf(entity Entity) (minX, minY, maxX, maxY float64, ok bool) {
+	switch e := entity.(type) {
+	case *Line:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Circle:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Arc:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Ellipse:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Point:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Text:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Solid:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *LWPolyline:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Spline:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Image:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Hatch:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	case *Dimension:
+		minX, minY, maxX, maxY = e.BoundingBox()
+	default:
+		return 0, 0, 0, 0, false
+	}
+	return minX, minY, maxX, maxY, true
+}
+
 // FilterByLayer returns all entities on a specific layer.
 //
 // Example:
@@ -382,34 +873,198 @@ func (d *Document) FilterByLayer(layerName string) []Entity {
 	var filtered []Entity
 
 	for _, entity := range d.Entities {
-		var layer string
+		if layer, ok := layerOf(entity); ok && layer == layerName {
+			filtered = append(filtered, entity)
+		}
+	}
+
+	return filtered
+}
+
+// TotalLengthByLayer returns the summed length of the model space lines,
+// arcs, and circles on each layer, e.g. to estimate cutting length. Other
+// entity types do not contribute; layers without such entities are absent.
+//
+// Example:
+//
+//	for layer, length := range doc.TotalLengthByLayer() {
+//	    fmt.Printf("%s: %.1f mm\n", layer, length)
+//	}
+func (d *Document) TotalLengthByLayer() map[string]float64 {
+	totals := make(map[string]float64)
+
+	for _, entity := range d.Entities {
 		switch e := entity.(type) {
 		case *Line:
-			layer = e.Layer
-		case *Circle:
-			layer = e.Layer
+			totals[e.Layer] += e.Length()
 		case *Arc:
-			layer = e.Layer
-		case *Ellipse:
-			layer = e.Layer
-		case *Point:
-			layer = e.Layer
-		case *Text:
-			layer = e.Layer
-		case *Solid:
-			layer = e.Layer
-		case *Insert:
-			layer = e.Layer
-		default:
-			continue
+			totals[e.Layer] += e.ArcLength()
+		case *Circle:
+			totals[e.Layer] += e.Circumference()
 		}
+	}
 
-		if layer == layerName {
-			filtered = append(filtered, entity)
+	return totals
+}
+
+// EntitiesOfType returns the model space entities of type T in document order.
+//
+// Example:
+//
+//	for _, line := range dxf.EntitiesOfType[*dxf.Line](doc) {
+//	    total += line.Length()
+//	}
+func EntitiesOfType[T Entity](doc *Document) []T {
+	var typed []T
+	for _, entity := range doc.Entities {
+		if e, ok := entity.(T); ok {
+			typed = append(typed, e)
 		}
 	}
+	return typed
+}
 
-	return filtered
+// layerOf returns the layer name of a known entity type.
+// ok is false if the entity type is not recognized.
+func layerOf(entity Entity) (layer string, ok bool) {
+	switch e := entity.(type) {
+	case *Line:
+		return e.Layer, true
+	case *Circle:
+		return e.Layer, true
+	case *Arc:
+		return e.Layer, true
+	case *Ellipse:
+		return e.Layer, true
+	case *Point:
+		return e.Layer, true
+	case *Text:
+		return e.Layer, true
+	case *Solid:
+		return e.Layer, true
+	case *Insert:
+		return e.Layer, true
+	case *LWPolyline:
+		return e.Layer, true
+	case *Spline:
+		return e.Layer, true
+	case *Image:
+		return e.Layer, true
+	case *Hatch:
+		return e.Layer, true
+	case *Dimension:
+		return e.Layer, true
+	}
+	return "", false
+}
+
+// lineTypeOf returns the linetype name of a known entity type.
+// ok is false if the entity type is not recognized.
+func lineTypeOf(entity Entity) (lineType string, ok bool) {
+	switch e := entity.(type) {
+	case *Line:
+		return e.LineType, true
+	case *Circle:
+		return e.LineType, true
+	case *Arc:
+		return e.LineType, true
+	case *Ellipse:
+		return e.LineType, true
+	case *Point:
+		return e.LineType, true
+	case *Text:
+		return e.LineType, true
+	case *Solid:
+		return e.LineType, true
+	case *Insert:
+		return e.LineType, true
+	case *LWPolyline:
+		return e.LineType, true
+	case *Spline:
+		return e.LineType, true
+	case *Image:
+		return e.LineType, true
+	case *Hatch:
+		return e.LineType, true
+	case *Dimension:
+		return e.LineType, true
+	}
+	return "", false
+}
+
+// anchorOf returns a representative point of a known entity type: the start
+// point, center, insertion point, or first vertex. Unknown types and
+// entities without vertices return (0, 0).
+func anchorOf(entity Entity) (x, y float64) {
+	switch e := entity.(type) {
+	case *Line:
+		return e.X1, e.Y1
+	case *Circle:
+		return e.CenterX, e.CenterY
+	case *Arc:
+		return e.CenterX, e.CenterY
+	case *Ellipse:
+		return e.CenterX, e.CenterY
+	case *Point:
+		return e.X, e.Y
+	case *Text:
+		return e.X, e.Y
+	case *Solid:
+		return e.X1, e.Y1
+	case *Insert:
+		return e.X, e.Y
+	case *LWPolyline:
+		if len(e.Vertices) > 0 {
+			return e.Vertices[0].X, e.Vertices[0].Y
+		}
+	case *Spline:
+		if len(e.ControlPoints) > 0 {
+			return e.ControlPoints[0].X, e.ControlPoints[0].Y
+		}
+	case *Image:
+		return e.X, e.Y
+	case *Hatch:
+		if len(e.Boundaries) > 0 && len(e.Boundaries[0]) > 0 {
+			return e.Boundaries[0][0].X, e.Boundaries[0][0].Y
+		}
+	case *Dimension:
+		return e.X1, e.Y1
+	}
+	return 0, 0
+}
+
+// colorOf returns a pointer to the Color field of a known entity type,
+// or nil if the entity type is not recognized.
+func colorOf(entity Entity) *int {
+	switch e := entity.(type) {
+	case *Line:
+		return &e.Color
+	case *Circle:
+		return &e.Color
+	case *Arc:
+		return &e.Color
+	case *Ellipse:
+		return &e.Color
+	case *Point:
+		return &e.Color
+	case *Text:
+		return &e.Color
+	case *Solid:
+		return &e.Color
+	case *Insert:
+		return &e.Color
+	case *LWPolyline:
+		return &e.Color
+	case *Spline:
+		return &e.Color
+	case *Image:
+		return &e.Color
+	case *Hatch:
+		return &e.Color
+	case *Dimension:
+		return &e.Color
+	}
+	return nil
 }
 
 // CountByType returns a map of entity type names to their counts.
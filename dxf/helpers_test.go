@@ -42,6 +42,21 @@ func TestLineMidPoint(t *testing.T) {
 	}
 }
 
+func TestLinePointAt(t *testing.T) {
+	line := NewLine(10, 20, 110, 70)
+
+	mx, my := line.MidPoint()
+	if x, y := line.PointAt(0.5); x != mx || y != my {
+		t.Errorf("PointAt(0.5) = (%v, %v), want MidPoint (%v, %v)", x, y, mx, my)
+	}
+	if x, y := line.PointAt(0); x != 10 || y != 20 {
+		t.Errorf("PointAt(0) = (%v, %v), want (10, 20)", x, y)
+	}
+	if x, y := line.PointAt(1); x != 110 || y != 70 {
+		t.Errorf("PointAt(1) = (%v, %v), want (110, 70)", x, y)
+	}
+}
+
 func TestLineAngle(t *testing.T) {
 	line := NewLine(0, 0, 100, 0)
 	angle := line.Angle()
@@ -142,6 +157,67 @@ func TestSolidArea(t *testing.T) {
 	}
 }
 
+func TestSolidCentroid(t *testing.T) {
+	tests := []struct {
+		name   string
+		solid  *Solid
+		wx, wy float64
+	}{
+		{"unit square", NewSolid(0, 0, 1, 0, 0, 1, 1, 1), 0.5, 0.5},
+		{"triangle", NewSolid(0, 0, 3, 0, 0, 3, 0, 3), 1, 1},
+		{"degenerate", NewSolid(0, 0, 2, 0, 4, 0, 2, 0), 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := tt.solid.Centroid()
+			if math.Abs(x-tt.wx) > 1e-9 || math.Abs(y-tt.wy) > 1e-9 {
+				t.Errorf("Centroid() = (%v, %v), want (%v, %v)", x, y, tt.wx, tt.wy)
+			}
+		})
+	}
+}
+
+func TestSolidNormalizeWinding(t *testing.T) {
+	tests := []struct {
+		name   string
+		solid  *Solid
+		x3, y3 float64
+	}{
+		{"DXF order", NewSolid(0, 0, 100, 0, 0, 100, 100, 100), 0, 100},
+		{"bowtie", NewSolid(0, 0, 100, 0, 100, 100, 0, 100), 0, 100},
+		{"triangle", NewSolid(0, 0, 100, 0, 50, 100, 50, 100), 50, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.solid.NormalizeWinding()
+
+			if tt.solid.X3 != tt.x3 || tt.solid.Y3 != tt.y3 {
+				t.Errorf("corner 3: got (%v, %v), want (%v, %v)", tt.solid.X3, tt.solid.Y3, tt.x3, tt.y3)
+			}
+			if tt.solid.selfIntersecting() {
+				t.Errorf("solid still self-intersecting: %+v", tt.solid)
+			}
+			if area := tt.solid.Area(); area <= 0 {
+				t.Errorf("expected positive area, got %v", area)
+			}
+		})
+	}
+}
+
+func TestSolidNormalizeWinding_DiagonalFirstEdge(t *testing.T) {
+	// Corners 1 and 2 are opposite corners of the square
+	solid := NewSolid(0, 0, 100, 100, 100, 0, 0, 100).NormalizeWinding()
+
+	if solid.selfIntersecting() {
+		t.Errorf("solid still self-intersecting: %+v", solid)
+	}
+	if area := solid.Area(); math.Abs(area-10000) > 0.0001 {
+		t.Errorf("area: got %v, want 10000", area)
+	}
+}
+
 func TestSolidIsTriangle(t *testing.T) {
 	triangle := NewSolid(0, 0, 100, 0, 50, 100, 50, 100)
 	if !triangle.IsTriangle() {
@@ -186,6 +262,26 @@ func TestDocumentFilterByLayer(t *testing.T) {
 	}
 }
 
+func TestDocumentTotalLengthByLayer(t *testing.T) {
+	doc := NewDocument().
+		AddLine(0, 0, 100, 0, WithLineLayer("Cut")).
+		AddLine(0, 0, 30, 40, WithLineLayer("Cut")).
+		AddArc(0, 0, 10, 0, 90, WithArcLayer("Engrave")).
+		AddPoint(1, 1)
+
+	totals := doc.TotalLengthByLayer()
+
+	if math.Abs(totals["Cut"]-150) > 1e-9 {
+		t.Errorf("Cut: got %v, want 150", totals["Cut"])
+	}
+	if want := 5 * math.Pi; math.Abs(totals["Engrave"]-want) > 1e-9 {
+		t.Errorf("Engrave: got %v, want %v", totals["Engrave"], want)
+	}
+	if len(totals) != 2 {
+		t.Errorf("expected 2 layers, got %v", totals)
+	}
+}
+
 func TestDocumentCountByType(t *testing.T) {
 	doc := NewDocument().
 		AddLine(0, 0, 100, 100).
@@ -205,3 +301,242 @@ func TestDocumentCountByType(t *testing.T) {
 		t.Errorf("Expected 1 point, got %d", counts["POINT"])
 	}
 }
+
+func TestCircleToArc(t *testing.T) {
+	arc := NewCircle(50, 50, 25, WithCircleLayer("A")).ToArc()
+
+	if arc.CenterX != 50 || arc.CenterY != 50 || arc.Radius != 25 || arc.Layer != "A" {
+		t.Errorf("Expected center (50, 50), radius 25 on layer A, got %+v", arc)
+	}
+	if arc.StartAngle != 0 || arc.EndAngle != 360 {
+		t.Errorf("Expected a full 0-360 arc, got %v-%v", arc.StartAngle, arc.EndAngle)
+	}
+	if length := arc.ArcLength(); math.Abs(length-2*math.Pi*25) > 1e-9 {
+		t.Errorf("Expected the circumference as arc length, got %f", length)
+	}
+}
+
+func TestArcToEllipse(t *testing.T) {
+	arc := NewArc(5, 5, 10, 30, 120, WithArcLayer("A"))
+	ellipse := arc.ToEllipse()
+
+	if ellipse.CenterX != 5 || ellipse.CenterY != 5 || ellipse.MinorRatio != 1 || ellipse.Layer != "A" {
+		t.Errorf("Expected a circular ellipse at (5, 5) on layer A, got %+v", ellipse)
+	}
+	if r := math.Hypot(ellipse.MajorAxisX, ellipse.MajorAxisY); r != 10 {
+		t.Errorf("Expected major radius 10, got %f", r)
+	}
+
+	// The ellipse must start and end where the arc does
+	arcPoints := arc.Flatten(0.01)
+	ellipsePoints := ellipse.Flatten(0.01)
+	for _, pair := range [][2]Vertex{
+		{arcPoints[0], ellipsePoints[0]},
+		{arcPoints[len(arcPoints)-1], ellipsePoints[len(ellipsePoints)-1]},
+	} {
+		if math.Abs(pair[0].X-pair[1].X) > 1e-9 || math.Abs(pair[0].Y-pair[1].Y) > 1e-9 {
+			t.Errorf("Endpoint mismatch: arc %v, ellipse %v", pair[0], pair[1])
+		}
+	}
+}
+
+func TestCircleToEllipse(t *testing.T) {
+	ellipse := NewCircle(-3, 4, 2).ToEllipse()
+
+	minX, minY, maxX, maxY := ellipse.BoundingBox()
+	if minX != -5 || minY != 2 || maxX != -1 || maxY != 6 {
+		t.Errorf("Expected bounds (-5, 2)-(-1, 6), got (%v, %v)-(%v, %v)", minX, minY, maxX, maxY)
+	}
+	if ellipse.StartParam != 0 || ellipse.EndParam != 2*math.Pi {
+		t.Errorf("Expected a full ellipse, got params %v-%v", ellipse.StartParam, ellipse.EndParam)
+	}
+}
+
+func TestArcFlatten(t *testing.T) {
+	arc := NewArc(0, 0, 10, 0, 90)
+
+	coarse := arc.Flatten(1)
+	fine := arc.Flatten(0.01)
+
+	if len(fine) <= len(coarse) {
+		t.Errorf("Expected more vertices for tighter tolerance, got %d (0.01) vs %d (1)", len(fine), len(coarse))
+	}
+
+	for _, vertices := range [][]Vertex{coarse, fine} {
+		first, last := vertices[0], vertices[len(vertices)-1]
+		if first != (Vertex{10, 0}) {
+			t.Errorf("Expected first vertex (10, 0), got %v", first)
+		}
+		if math.Abs(last.X) > 1e-12 || last.Y != 10 {
+			t.Errorf("Expected last vertex (0, 10), got %v", last)
+		}
+	}
+
+	// Chord midpoints must stay within tolerance of the arc
+	for i := 1; i < len(fine); i++ {
+		mx := (fine[i-1].X + fine[i].X) / 2
+		my := (fine[i-1].Y + fine[i].Y) / 2
+		if sagitta := 10 - math.Hypot(mx, my); sagitta > 0.01+1e-12 {
+			t.Errorf("Segment %d sagitta %f exceeds tolerance", i, sagitta)
+		}
+	}
+}
+
+func TestEllipseFlatten(t *testing.T) {
+	ellipse := &Ellipse{
+		CenterX:    5,
+		CenterY:    5,
+		MajorAxisX: 100,
+		MinorRatio: 0.5,
+		StartParam: 0,
+		EndParam:   math.Pi,
+	}
+
+	coarse := ellipse.Flatten(5)
+	fine := ellipse.Flatten(0.05)
+
+	if len(fine) <= len(coarse) {
+		t.Errorf("Expected more vertices for tighter tolerance, got %d (0.05) vs %d (5)", len(fine), len(coarse))
+	}
+
+	first, last := fine[0], fine[len(fine)-1]
+	if first != (Vertex{105, 5}) {
+		t.Errorf("Expected first vertex (105, 5), got %v", first)
+	}
+	if last.X != -95 || math.Abs(last.Y-5) > 1e-12 {
+		t.Errorf("Expected last vertex (-95, 5), got %v", last)
+	}
+}
+
+func TestEllipseBoundingBox(t *testing.T) {
+	tests := []struct {
+		name                   string
+		ellipse                Ellipse
+		minX, minY, maxX, maxY float64
+	}{
+		{"full", Ellipse{CenterX: 1, CenterY: 2, MajorAxisX: 10, MinorRatio: 0.5, EndParam: 2 * math.Pi}, -9, -3, 11, 7},
+		{"full tilted", Ellipse{MajorAxisX: 3, MajorAxisY: 4, MinorRatio: 0.5, EndParam: 2 * math.Pi},
+			-math.Sqrt(13), -math.Sqrt(18.25), math.Sqrt(13), math.Sqrt(18.25)},
+		{"quarter", Ellipse{MajorAxisX: 10, MinorRatio: 0.5, EndParam: math.Pi / 2}, 0, 0, 10, 5},
+		{"quarter tilted", Ellipse{MajorAxisY: 10, MinorRatio: 0.5, EndParam: math.Pi / 2}, -5, 0, 0, 10},
+		{"across an extremum", Ellipse{MajorAxisX: 10, MinorRatio: 0.5, StartParam: -math.Pi / 4, EndParam: math.Pi / 4},
+			10 / math.Sqrt2, -5 / math.Sqrt2, 10, 5 / math.Sqrt2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minX, minY, maxX, maxY := tt.ellipse.BoundingBox()
+			if !approxEqual(minX, tt.minX) || !approxEqual(minY, tt.minY) || !approxEqual(maxX, tt.maxX) || !approxEqual(maxY, tt.maxY) {
+				t.Errorf("got (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+					minX, minY, maxX, maxY, tt.minX, tt.minY, tt.maxX, tt.maxY)
+			}
+		})
+	}
+}
+
+func TestSplineSample(t *testing.T) {
+	spline := &Spline{
+		Degree:        3,
+		ControlPoints: []Vertex{{0, 0}, {10, 20}, {30, 20}, {40, 0}},
+	}
+
+	vertices := spline.Sample(10)
+
+	if len(vertices) != 11 {
+		t.Fatalf("Expected 11 vertices, got %d", len(vertices))
+	}
+	// Clamped knots: the curve starts and ends on the end control points
+	if vertices[0] != (Vertex{0, 0}) || vertices[10] != (Vertex{40, 0}) {
+		t.Errorf("Expected endpoints (0,0) and (40,0), got %v and %v", vertices[0], vertices[10])
+	}
+	// Single-span cubic is a Bezier curve: midpoint is (20, 15)
+	if math.Abs(vertices[5].X-20) > 1e-9 || math.Abs(vertices[5].Y-15) > 1e-9 {
+		t.Errorf("Expected midpoint (20, 15), got %v", vertices[5])
+	}
+}
+
+func TestEntitiesOfType(t *testing.T) {
+	doc := NewDocument().
+		AddLine(0, 0, 1, 1).
+		AddText(5, 5, "A").
+		AddCircle(0, 0, 1).
+		AddLine(2, 2, 3, 3).
+		AddText(6, 6, "B")
+
+	lines := EntitiesOfType[*Line](doc)
+	if len(lines) != 2 || lines[0].X1 != 0 || lines[1].X1 != 2 {
+		t.Errorf("lines: got %+v", lines)
+	}
+	texts := EntitiesOfType[*Text](doc)
+	if len(texts) != 2 || texts[0].Content != "A" || texts[1].Content != "B" {
+		t.Errorf("texts: got %+v", texts)
+	}
+	if images := EntitiesOfType[*Image](doc); len(images) != 0 {
+		t.Errorf("images: got %d, want 0", len(images))
+	}
+}
+
+func TestComputeExtents(t *testing.T) {
+	// A circle far from the block base point, inserted near the origin with
+	// scale 2 and rotation 90: its center (10, 0) relative to the base
+	// point lands at (0, 20) with radius 10.
+	far := Block{Name: "FAR", BaseX: 1000, BaseY: 1000, Entities: []Entity{
+		&Circle{Layer: "0", CenterX: 1010, CenterY: 1000, Radius: 5},
+	}}
+	nested := Block{Name: "NESTED", Entities: []Entity{
+		&Insert{BlockName: "FAR", X: 100, Y: 0, ScaleX: 1, ScaleY: 1},
+	}}
+	loop := Block{Name: "LOOP", Entities: []Entity{
+		&Insert{BlockName: "LOOP", ScaleX: 1, ScaleY: 1},
+		&Point{X: 50, Y: 50},
+	}}
+
+	tests := []struct {
+		name                   string
+		entities               []Entity
+		minX, minY, maxX, maxY float64
+	}{
+		{
+			"rotated and scaled insert",
+			[]Entity{
+				&Line{X1: -5, Y1: 0, X2: 0, Y2: 0},
+				&Insert{BlockName: "FAR", ScaleX: 2, ScaleY: 2, Rotation: 90},
+			},
+			-10, 0, 10, 30,
+		},
+		{
+			"nested insert",
+			[]Entity{&Insert{BlockName: "NESTED", ScaleX: 1, ScaleY: 1}},
+			105, -5, 115, 5,
+		},
+		{
+			"non-uniform scale",
+			[]Entity{&Insert{BlockName: "FAR", ScaleX: 2, ScaleY: 1}},
+			10, -5, 30, 5,
+		},
+		{
+			"recursive block",
+			[]Entity{&Insert{BlockName: "LOOP", X: 1, Y: 1, ScaleX: 1, ScaleY: 1}},
+			51, 51, 51, 51,
+		},
+		{
+			"missing block",
+			[]Entity{&Insert{BlockName: "MISSING", X: 1, Y: 1, ScaleX: 1, ScaleY: 1}},
+			0, 0, 0, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Entities: tt.entities, Blocks: []Block{far, nested, loop}}
+			minX, minY, maxX, maxY := doc.ComputeExtents()
+			got := []float64{minX, minY, maxX, maxY}
+			want := []float64{tt.minX, tt.minY, tt.maxX, tt.maxY}
+			for i := range got {
+				if math.Abs(got[i]-want[i]) > 1e-9 {
+					t.Fatalf("got %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
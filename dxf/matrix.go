@@ -0,0 +1,358 @@
+package dxf
+
+import (
+	"math"
+	"slices"
+)
+
+// Matrix2D is a 2D affine transformation. A point (x, y) maps to
+//
+//	x' = A*x + C*y + E
+//	y' = B*x + D*y + F
+//
+// The zero value is not the identity; use IdentityMatrix.
+type Matrix2D struct {
+	A, B, C, D, E, F float64
+}
+
+// IdentityMatrix returns the transformation that leaves points unchanged.
+func IdentityMatrix() Matrix2D {
+	return Matrix2D{A: 1, D: 1}
+}
+
+// TranslationMatrix returns a transformation moving points by (dx, dy).
+func TranslationMatrix(dx, dy float64) Matrix2D {
+	return Matrix2D{A: 1, D: 1, E: dx, F: dy}
+}
+
+// ScalingMatrix returns a uniform scaling about the origin.
+//
+// Example:
+//
+//	doc.Transform(dxf.ScalingMatrix(1 / 25.4)) // millimeters to inches
+func ScalingMatrix(factor float64) Matrix2D {
+	return Matrix2D{A: factor, D: factor}
+}
+
+// RotationMatrix returns a counter-clockwise rotation about the origin by
+// the given angle in degrees.
+func RotationMatrix(angleDeg float64) Matrix2D {
+	sin, cos := math.Sincos(angleDeg * math.Pi / 180.0)
+	return Matrix2D{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// Then returns the transformation that applies m first and n second.
+//
+// Example:
+//
+//	// Rotate 90° around (10, 10)
+//	m := dxf.TranslationMatrix(-10, -10).
+//		Then(dxf.RotationMatrix(90)).
+//		Then(dxf.TranslationMatrix(10, 10))
+func (m Matrix2D) Then(n Matrix2D) Matrix2D {
+	return Matrix2D{
+		A: n.A*m.A + n.C*m.B,
+		B: n.B*m.A + n.D*m.B,
+		C: n.A*m.C + n.C*m.D,
+		D: n.B*m.C + n.D*m.D,
+		E: n.A*m.E + n.C*m.F + n.E,
+		F: n.B*m.E + n.D*m.F + n.F,
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix2D) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.E, m.B*x + m.D*y + m.F
+}
+
+// applyVector transforms the direction (x, y), ignoring translation.
+func (m Matrix2D) applyVector(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y, m.B*x + m.D*y
+}
+
+// det returns the determinant of the linear part; it is negative for
+// transformations that mirror.
+func (m Matrix2D) det() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// scale returns the factor by which m scales lengths. For non-uniform
+// scaling this is the geometric mean of the axis factors.
+func (m Matrix2D) scale() float64 {
+	return math.Sqrt(math.Abs(m.det()))
+}
+
+// rotationDeg returns the angle in degrees by which m rotates the X axis.
+func (m Matrix2D) rotationDeg() float64 {
+	return math.Atan2(m.B, m.A) * 180.0 / math.Pi
+}
+
+// angleDeg returns the direction in degrees of the angle angleDeg after
+// transformation by m.
+func (m Matrix2D) angleDeg(angleDeg float64) float64 {
+	sin, cos := math.Sincos(angleDeg * math.Pi / 180.0)
+	x, y := m.applyVector(cos, sin)
+	return math.Atan2(y, x) * 180.0 / math.Pi
+}
+
+// conformal reports whether m preserves angles, i.e. scales uniformly,
+// possibly with rotation and mirroring.
+func (m Matrix2D) conformal() bool {
+	const eps = 1e-9
+	rotates := math.Abs(m.A-m.D) <= eps && math.Abs(m.B+m.C) <= eps
+	mirrors := math.Abs(m.A+m.D) <= eps && math.Abs(m.B-m.C) <= eps
+	return rotates || mirrors
+}
+
+// insertMatrix returns the transformation from the block coordinates of
+// b to the world coordinates of insert i: the block base point moves to
+// the insertion point, scaled by ScaleX and ScaleY and rotated by Rotation.
+func insertMatrix(i *Insert, b *Block) Matrix2D {
+	return TranslationMatrix(-b.BaseX, -b.BaseY).
+		Then(Matrix2D{A: i.ScaleX, D: i.ScaleY}).
+		Then(RotationMatrix(i.Rotation)).
+		Then(TranslationMatrix(i.X, i.Y))
+}
+
+// ResolvedEntities returns copies of the entities of the insert's block,
+// transformed to world coordinates by the insertion point, scale, and
+// rotation, for rendering or hit-testing without changing the document.
+// Nested inserts are resolved recursively; inserts of missing or
+// recursively nested blocks are left out, as are entity types defined
+// outside this package unless they implement Clone() Entity and
+// ApplyMatrix(Matrix2D). It returns nil if blocks has no block named BlockName.
+//
+// Example:
+//
+//	for _, e := range insert.ResolvedEntities(doc.Blocks) {
+//	    draw(e)
+//	}
+func (i *Insert) ResolvedEntities(blocks []Block) []Entity {
+	return resolveInsert(i, blocks, IdentityMatrix(), make(map[string]bool))
+}
+
+// matrixApplier is implemented by entities that can transform themselves.
+type matrixApplier interface {
+	Entity
+	ApplyMatrix(m Matrix2D)
+}
+
+// resolveInsert returns the world entities of insert i, whose own
+// coordinates are transformed by m. active holds the blocks being resolved.
+func resolveInsert(i *Insert, blocks []Block, m Matrix2D, active map[string]bool) []Entity {
+	index := slices.IndexFunc(blocks, func(b Block) bool { return b.Name == i.BlockName })
+	if index < 0 || active[i.BlockName] {
+		return nil
+	}
+	block := &blocks[index]
+	m = insertMatrix(i, block).Then(m)
+	active[block.Name] = true
+	defer delete(active, block.Name)
+
+	var resolved []Entity
+	for _, entity := range block.Entities {
+		if nested, ok := entity.(*Insert); ok {
+			resolved = append(resolved, resolveInsert(nested, blocks, m, active)...)
+			continue
+		}
+		c, ok := entity.(cloner)
+		if !ok {
+			continue
+		}
+		clone, ok := c.Clone().(matrixApplier)
+		if !ok {
+			continue
+		}
+		clone.ApplyMatrix(m)
+		resolved = append(resolved, clone)
+	}
+	return resolved
+}
+
+// ApplyMatrix transforms the line in place.
+func (l *Line) ApplyMatrix(m Matrix2D) {
+	l.X1, l.Y1 = m.Apply(l.X1, l.Y1)
+	l.X2, l.Y2 = m.Apply(l.X2, l.Y2)
+}
+
+// ApplyMatrix transforms the circle in place. Circles stay circles, so
+// non-uniform scaling uses the mean scale factor for the radius.
+func (c *Circle) ApplyMatrix(m Matrix2D) {
+	c.CenterX, c.CenterY = m.Apply(c.CenterX, c.CenterY)
+	c.Radius *= m.scale()
+}
+
+// ApplyMatrix transforms the arc in place. Arcs stay circular, so
+// non-uniform scaling uses the mean scale factor for the radius. Mirroring
+// swaps the start and end angles to keep the arc counter-clockwise.
+func (a *Arc) ApplyMatrix(m Matrix2D) {
+	a.CenterX, a.CenterY = m.Apply(a.CenterX, a.CenterY)
+	a.Radius *= m.scale()
+	start, end := normalizeDeg(m.angleDeg(a.StartAngle)), normalizeDeg(m.angleDeg(a.EndAngle))
+	if m.det() < 0 {
+		start, end = end, start
+	}
+	a.StartAngle, a.EndAngle = start, end
+}
+
+// ApplyMatrix transforms the ellipse in place. The major axis is
+// transformed exactly; the minor ratio is kept, which is exact for
+// uniform scaling, rotation, and mirroring.
+func (e *Ellipse) ApplyMatrix(m Matrix2D) {
+	e.CenterX, e.CenterY = m.Apply(e.CenterX, e.CenterY)
+	e.MajorAxisX, e.MajorAxisY = m.applyVector(e.MajorAxisX, e.MajorAxisY)
+	if m.det() < 0 {
+		// Mirroring reverses the parameter direction
+		e.StartParam, e.EndParam = -e.EndParam, -e.StartParam
+	}
+}
+
+// ApplyMatrix transforms the point in place.
+func (p *Point) ApplyMatrix(m Matrix2D) {
+	p.X, p.Y = m.Apply(p.X, p.Y)
+}
+
+// ApplyMatrix transforms the text in place, scaling its height by the mean
+// scale factor and adding the matrix rotation. Mirrored text is not
+// representable and keeps reading left to right.
+func (t *Text) ApplyMatrix(m Matrix2D) {
+	t.X, t.Y = m.Apply(t.X, t.Y)
+	t.AlignX, t.AlignY = m.Apply(t.AlignX, t.AlignY)
+	t.Height *= m.scale()
+	t.Rotation += m.rotationDeg()
+}
+
+// ApplyMatrix transforms the solid in place.
+func (s *Solid) ApplyMatrix(m Matrix2D) {
+	s.X1, s.Y1 = m.Apply(s.X1, s.Y1)
+	s.X2, s.Y2 = m.Apply(s.X2, s.Y2)
+	s.X3, s.Y3 = m.Apply(s.X3, s.Y3)
+	s.X4, s.Y4 = m.Apply(s.X4, s.Y4)
+}
+
+// ApplyMatrix transforms the insert in place, scaling its scale factors by
+// the mean scale factor and adding the matrix rotation. Mirroring negates
+// ScaleY.
+func (i *Insert) ApplyMatrix(m Matrix2D) {
+	i.X, i.Y = m.Apply(i.X, i.Y)
+	s := m.scale()
+	i.ScaleX *= s
+	i.ScaleY *= s
+	if m.det() < 0 {
+		i.ScaleY = -i.ScaleY
+	}
+	i.Rotation += m.rotationDeg()
+	transformAttributes(i.Attributes, m)
+}
+
+// transformAttributes transforms attribute positions, heights, and
+// rotations in place.
+func transformAttributes(attrs []Attribute, m Matrix2D) {
+	s, rot := m.scale(), m.rotationDeg()
+	for i := range attrs {
+		a := &attrs[i]
+		a.X, a.Y = m.Apply(a.X, a.Y)
+		a.Height *= s
+		a.Rotation += rot
+	}
+}
+
+// ApplyMatrix transforms the polyline vertices in place.
+func (p *LWPolyline) ApplyMatrix(m Matrix2D) {
+	for i := range p.Vertices {
+		p.Vertices[i].X, p.Vertices[i].Y = m.Apply(p.Vertices[i].X, p.Vertices[i].Y)
+	}
+}
+
+// ApplyMatrix transforms the spline in place. B-splines are affine
+// invariant, so transforming the control points is exact.
+func (s *Spline) ApplyMatrix(m Matrix2D) {
+	for i := range s.ControlPoints {
+		s.ControlPoints[i].X, s.ControlPoints[i].Y = m.Apply(s.ControlPoints[i].X, s.ControlPoints[i].Y)
+	}
+}
+
+// ApplyMatrix transforms the image in place, scaling its size by the mean
+// scale factor and adding the matrix rotation.
+func (img *Image) ApplyMatrix(m Matrix2D) {
+	img.X, img.Y = m.Apply(img.X, img.Y)
+	s := m.scale()
+	img.Width *= s
+	img.Height *= s
+	img.Rotation += m.rotationDeg()
+}
+
+// ApplyMatrix transforms the hatch boundary vertices in place.
+func (h *Hatch) ApplyMatrix(m Matrix2D) {
+	for _, boundary := range h.Boundaries {
+		for i := range boundary {
+			boundary[i].X, boundary[i].Y = m.Apply(boundary[i].X, boundary[i].Y)
+		}
+	}
+}
+
+// ApplyMatrix transforms the dimension points and the entities drawing the
+// dimension in place.
+func (d *Dimension) ApplyMatrix(m Matrix2D) {
+	d.X1, d.Y1 = m.Apply(d.X1, d.Y1)
+	d.X2, d.Y2 = m.Apply(d.X2, d.Y2)
+	d.LineX, d.LineY = m.Apply(d.LineX, d.LineY)
+	d.TextX, d.TextY = m.Apply(d.TextX, d.TextY)
+	transformEntities(d.Entities, m)
+}
+
+// Transform applies m in place to every entity of the document, including
+// paper space entities, block definition entities, and block base points.
+//
+// Because block contents are transformed along with everything else,
+// inserts only move their insertion point and keep their own scale and
+// rotation. This is exact for translations, uniform scaling, and rotations.
+//
+// Example:
+//
+//	doc.Transform(dxf.ScalingMatrix(1 / 25.4)) // millimeters to inches
+func (d *Document) Transform(m Matrix2D) {
+	transformEntities(d.Entities, m)
+	transformEntities(d.PaperSpaceEntities, m)
+	for i := range d.Blocks {
+		b := &d.Blocks[i]
+		b.BaseX, b.BaseY = m.Apply(b.BaseX, b.BaseY)
+		transformEntities(b.Entities, m)
+		transformAttributes(b.Attributes, m)
+	}
+}
+
+// transformEntities applies m to each entity of a known type.
+func transformEntities(entities []Entity, m Matrix2D) {
+	for _, entity := range entities {
+		switch e := entity.(type) {
+		case *Line:
+			e.ApplyMatrix(m)
+		case *Circle:
+			e.ApplyMatrix(m)
+		case *Arc:
+			e.ApplyMatrix(m)
+		case *Ellipse:
+			e.ApplyMatrix(m)
+		case *Point:
+			e.ApplyMatrix(m)
+		case *Text:
+			e.ApplyMatrix(m)
+		case *Solid:
+			e.ApplyMatrix(m)
+		case *Insert:
+			e.X, e.Y = m.Apply(e.X, e.Y)
+			transformAttributes(e.Attributes, m)
+		case *LWPolyline:
+			e.ApplyMatrix(m)
+		case *Spline:
+			e.ApplyMatrix(m)
+		case *Image:
+			e.ApplyMatrix(m)
+		case *Hatch:
+			e.ApplyMatrix(m)
+		case *Dimension:
+			e.ApplyMatrix(m)
+		}
+	}
+}
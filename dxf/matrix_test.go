@@ -0,0 +1,108 @@
+package dxf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrix2D_Then(t *testing.T) {
+	// Rotate 90° around (10, 10)
+	m := TranslationMatrix(-10, -10).
+		Then(RotationMatrix(90)).
+		Then(TranslationMatrix(10, 10))
+
+	x, y := m.Apply(20, 10)
+	if !approxEqual(x, 10) || !approxEqual(y, 20) {
+		t.Errorf("Apply: got (%v, %v), want (10, 20)", x, y)
+	}
+
+	x, y = IdentityMatrix().Apply(3, 4)
+	if x != 3 || y != 4 {
+		t.Errorf("identity: got (%v, %v), want (3, 4)", x, y)
+	}
+}
+
+func TestDocument_Transform(t *testing.T) {
+	doc := NewDocument().
+		AddLine(0, 0, 254, 127).
+		AddCircle(25.4, 0, 25.4).
+		AddText(0, 50.8, "A", WithTextHeight(2.54)).
+		AddInsert("B", 254, 0).
+		AddBlock(Block{Name: "B", BaseX: 50.8, BaseY: 25.4, Entities: []Entity{NewLine(0, 0, 25.4, 0)}})
+
+	doc.Transform(ScalingMatrix(1 / 25.4))
+
+	line := doc.Entities[0].(*Line)
+	if !approxEqual(line.X2, 10) || !approxEqual(line.Y2, 5) {
+		t.Errorf("line end: got (%v, %v), want (10, 5)", line.X2, line.Y2)
+	}
+	circle := doc.Entities[1].(*Circle)
+	if !approxEqual(circle.CenterX, 1) || !approxEqual(circle.Radius, 1) {
+		t.Errorf("circle: got center x %v radius %v, want 1 and 1", circle.CenterX, circle.Radius)
+	}
+	if text := doc.Entities[2].(*Text); !approxEqual(text.Height, 0.1) {
+		t.Errorf("text height: got %v, want 0.1", text.Height)
+	}
+	insert := doc.Entities[3].(*Insert)
+	if !approxEqual(insert.X, 10) || insert.ScaleX != 1 {
+		t.Errorf("insert: got x %v scale %v, want 10 and 1", insert.X, insert.ScaleX)
+	}
+
+	block := doc.Blocks[0]
+	if !approxEqual(block.BaseX, 2) || !approxEqual(block.BaseY, 1) {
+		t.Errorf("block base: got (%v, %v), want (2, 1)", block.BaseX, block.BaseY)
+	}
+	if l := block.Entities[0].(*Line); !approxEqual(l.X2, 1) {
+		t.Errorf("block line end x: got %v, want 1", l.X2)
+	}
+}
+
+func TestArc_ApplyMatrixMirror(t *testing.T) {
+	arc := NewArc(0, 0, 10, 0, 90)
+
+	// Mirror across the Y axis
+	arc.ApplyMatrix(Matrix2D{A: -1, D: 1})
+
+	if !approxEqual(arc.StartAngle, 90) || !approxEqual(arc.EndAngle, 180) {
+		t.Errorf("mirrored arc: got %v° to %v°, want 90° to 180°", arc.StartAngle, arc.EndAngle)
+	}
+}
+
+func TestEllipse_ApplyMatrixRotate(t *testing.T) {
+	e := &Ellipse{MajorAxisX: 10, MinorRatio: 0.5, EndParam: 2 * math.Pi}
+
+	e.ApplyMatrix(RotationMatrix(90))
+
+	if !approxEqual(e.MajorAxisX, 0) || !approxEqual(e.MajorAxisY, 10) {
+		t.Errorf("major axis: got (%v, %v), want (0, 10)", e.MajorAxisX, e.MajorAxisY)
+	}
+}
+
+// approxEqual reports whether a and b differ by less than 1e-9.
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestInsert_ResolvedEntities(t *testing.T) {
+	blocks := []Block{{Name: "B", BaseX: 1, Entities: []Entity{
+		&Line{Layer: "0", X1: 1, Y1: 0, X2: 3, Y2: 0},
+		&Insert{Layer: "0", BlockName: "B", ScaleX: 1, ScaleY: 1}, // recursive, left out
+	}}}
+	insert := &Insert{Layer: "0", BlockName: "B", X: 10, Y: 10, ScaleX: 2, ScaleY: 2, Rotation: 90}
+
+	resolved := insert.ResolvedEntities(blocks)
+	if len(resolved) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(resolved))
+	}
+	line := resolved[0].(*Line)
+	if !approxEqual(line.X1, 10) || !approxEqual(line.Y1, 10) || !approxEqual(line.X2, 10) || !approxEqual(line.Y2, 14) {
+		t.Errorf("line: got (%v, %v)-(%v, %v), want (10, 10)-(10, 14)", line.X1, line.Y1, line.X2, line.Y2)
+	}
+	if block := blocks[0].Entities[0].(*Line); block.X2 != 3 || block.Y2 != 0 {
+		t.Errorf("block entity was modified: %+v", block)
+	}
+
+	if missing := (&Insert{BlockName: "MISSING"}).ResolvedEntities(blocks); missing != nil {
+		t.Errorf("expected nil for a missing block, got %v", missing)
+	}
+}
@@ -0,0 +1,81 @@
+package dxf
+
+import "unicode/utf8"
+
+// TextMetrics measures the rendered width of text strings.
+// It is used by Text.BoundingBoxWithMetrics to size text extents.
+type TextMetrics interface {
+	// TextWidth returns the width of s when drawn at the given text height.
+	TextWidth(s string, height float64) float64
+}
+
+// MonospaceMetrics measures text as if every character had the same width.
+type MonospaceMetrics struct {
+	// CharWidth is the width of one character as a fraction of the text height.
+	CharWidth float64
+}
+
+// TextWidth returns height * CharWidth for each rune in s.
+func (m MonospaceMetrics) TextWidth(s string, height float64) float64 {
+	return height * m.CharWidth * float64(utf8.RuneCountInString(s))
+}
+
+// CJKMetrics measures text with separate widths for full-width characters
+// (kanji, kana, full-width forms, Hangul) and half-width characters.
+type CJKMetrics struct {
+	// FullWidth is the width of a full-width character as a fraction of the text height.
+	FullWidth float64
+
+	// HalfWidth is the width of a half-width character as a fraction of the text height.
+	HalfWidth float64
+}
+
+// TextWidth sums the width of each rune in s, classifying runes as full-width or half-width.
+func (m CJKMetrics) TextWidth(s string, height float64) float64 {
+	var em float64
+	for _, r := range s {
+		if isFullWidth(r) {
+			em += m.FullWidth
+		} else {
+			em += m.HalfWidth
+		}
+	}
+	return height * em
+}
+
+// DefaultTextMetrics is the TextMetrics used by Text.BoundingBox.
+// Full-width characters are 1.0 em wide and half-width characters 0.5 em.
+var DefaultTextMetrics TextMetrics = CJKMetrics{FullWidth: 1.0, HalfWidth: 0.5}
+
+// isFullWidth reports whether r is drawn as a full-width (double-cell) character.
+// The ranges follow the East Asian Wide and Fullwidth blocks commonly found in
+// Japanese drawings; half-width katakana (U+FF61-U+FF9F) are half-width.
+func isFullWidth(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F: // Hangul Jamo
+		return true
+	case r >= 0x2E80 && r <= 0x303E: // CJK radicals, symbols and punctuation
+		return true
+	case r >= 0x3041 && r <= 0x33FF: // Hiragana, Katakana, CJK compatibility
+		return true
+	case r >= 0x3400 && r <= 0x4DBF: // CJK Unified Ideographs Extension A
+		return true
+	case r >= 0x4E00 && r <= 0x9FFF: // CJK Unified Ideographs
+		return true
+	case r >= 0xA000 && r <= 0xA4CF: // Yi
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3: // Hangul syllables
+		return true
+	case r >= 0xF900 && r <= 0xFAFF: // CJK Compatibility Ideographs
+		return true
+	case r >= 0xFE30 && r <= 0xFE4F: // CJK Compatibility Forms
+		return true
+	case r >= 0xFF00 && r <= 0xFF60: // Fullwidth forms
+		return true
+	case r >= 0xFFE0 && r <= 0xFFE6: // Fullwidth signs
+		return true
+	case r >= 0x20000 && r <= 0x3FFFD: // CJK extensions B and beyond
+		return true
+	}
+	return false
+}
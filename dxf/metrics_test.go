@@ -0,0 +1,92 @@
+package dxf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCJKMetricsTextWidth(t *testing.T) {
+	metrics := CJKMetrics{FullWidth: 1.0, HalfWidth: 0.5}
+
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"ABCD", 20}, // 4 half-width * 0.5 * 10
+		{"図面作成", 40}, // 4 full-width * 1.0 * 10
+		{"A図", 15},   // mixed
+		{"ｱｲｳｴ", 20}, // half-width katakana
+		{"ＡＢ", 20},   // full-width Latin
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := metrics.TextWidth(tt.content, 10); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TextWidth(%q): got %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestMonospaceMetricsTextWidth(t *testing.T) {
+	metrics := MonospaceMetrics{CharWidth: 0.6}
+
+	// Runes, not bytes, are counted: both strings have 4 characters
+	ascii := metrics.TextWidth("ABCD", 10)
+	japanese := metrics.TextWidth("図面作成", 10)
+
+	if math.Abs(ascii-24) > 1e-9 {
+		t.Errorf("ASCII width: got %v, want 24", ascii)
+	}
+	if ascii != japanese {
+		t.Errorf("Expected equal widths, got ASCII %v and Japanese %v", ascii, japanese)
+	}
+}
+
+func TestTextBoundingBox_Japanese(t *testing.T) {
+	ascii := NewText(0, 0, "ABCD", WithTextHeight(10))
+	japanese := NewText(0, 0, "図面作成", WithTextHeight(10))
+
+	_, _, asciiMaxX, _ := ascii.BoundingBox()
+	_, _, japaneseMaxX, maxY := japanese.BoundingBox()
+
+	if math.Abs(japaneseMaxX-2*asciiMaxX) > 1e-9 {
+		t.Errorf("Expected Japanese text twice as wide as ASCII, got %v and %v", japaneseMaxX, asciiMaxX)
+	}
+	if maxY != 10 {
+		t.Errorf("Expected maxY 10, got %v", maxY)
+	}
+
+	_, _, monoMaxX, _ := japanese.BoundingBoxWithMetrics(MonospaceMetrics{CharWidth: 0.6})
+	if math.Abs(monoMaxX-24) > 1e-9 {
+		t.Errorf("Expected monospace width 24, got %v", monoMaxX)
+	}
+}
+
+func TestTextBoundingBox_Alignment(t *testing.T) {
+	metrics := MonospaceMetrics{CharWidth: 0.5} // "ABCD" at height 10 is 20 wide
+
+	tests := []struct {
+		name                   string
+		h                      TextHAlign
+		v                      TextVAlign
+		alignX, alignY         float64
+		minX, minY, maxX, maxY float64
+	}{
+		{"left", TextHAlignLeft, TextVAlignBaseline, 100, 100, 0, 0, 20, 10},
+		{"center", TextHAlignCenter, TextVAlignBaseline, 100, 100, 90, 100, 110, 110},
+		{"right top", TextHAlignRight, TextVAlignTop, 100, 100, 80, 90, 100, 100},
+		{"middle", TextHAlignMiddle, TextVAlignBaseline, 100, 100, 90, 95, 110, 105},
+		{"fit", TextHAlignFit, TextVAlignBaseline, 50, 0, 0, 0, 50, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := NewText(0, 0, "ABCD", WithTextHeight(10), WithTextAlignment(tt.h, tt.v, tt.alignX, tt.alignY))
+			minX, minY, maxX, maxY := text.BoundingBoxWithMetrics(metrics)
+			if minX != tt.minX || minY != tt.minY || maxX != tt.maxX || maxY != tt.maxY {
+				t.Errorf("got (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+					minX, minY, maxX, maxY, tt.minX, tt.minY, tt.maxX, tt.maxY)
+			}
+		})
+	}
+}
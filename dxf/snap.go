@@ -0,0 +1,97 @@
+package dxf
+
+import "math"
+
+// SnapVertices rounds the positions of all entities to multiples of grid,
+// so points that differ only by floating-point noise (e.g. line endpoints
+// off by 1e-9) become exactly equal, which endpoint matching and
+// Deduplicate rely on. It covers model and paper space, block entities,
+// block base points, and attribute positions. Only positions are snapped:
+// radii, axis vectors, sizes, and angles keep their values.
+//
+// Points closer than grid usually snap to the same grid point, but two
+// points on either side of a grid cell boundary can still snap apart, so
+// pick a grid well above the noise and below the drawing precision. A grid
+// <= 0 leaves the document unchanged.
+//
+// Example:
+//
+//	doc.SnapVertices(1e-6)
+//	removed := doc.Deduplicate(0)
+func (d *Document) SnapVertices(grid float64) {
+	if grid <= 0 || math.IsNaN(grid) || math.IsInf(grid, 0) {
+		return
+	}
+	snap := func(x, y float64) (float64, float64) {
+		return math.Round(x/grid) * grid, math.Round(y/grid) * grid
+	}
+
+	snapEntities(d.Entities, snap)
+	snapEntities(d.PaperSpaceEntities, snap)
+	for i := range d.Blocks {
+		b := &d.Blocks[i]
+		b.BaseX, b.BaseY = snap(b.BaseX, b.BaseY)
+		snapEntities(b.Entities, snap)
+		snapAttributes(b.Attributes, snap)
+	}
+}
+
+// snapEntities applies snap to the positions of each entity of a known type.
+func snapEntities(entities []Entity, snap func(x, y float64) (float64, float64)) {
+	for _, entity := range entities {
+		switch e := entity.(type) {
+		case *Line:
+			e.X1, e.Y1 = snap(e.X1, e.Y1)
+			e.X2, e.Y2 = snap(e.X2, e.Y2)
+		case *Circle:
+			e.CenterX, e.CenterY = snap(e.CenterX, e.CenterY)
+		case *Arc:
+			e.CenterX, e.CenterY = snap(e.CenterX, e.CenterY)
+		case *Ellipse:
+			e.CenterX, e.CenterY = snap(e.CenterX, e.CenterY)
+		case *Point:
+			e.X, e.Y = snap(e.X, e.Y)
+		case *Text:
+			e.X, e.Y = snap(e.X, e.Y)
+			e.AlignX, e.AlignY = snap(e.AlignX, e.AlignY)
+		case *Solid:
+			e.X1, e.Y1 = snap(e.X1, e.Y1)
+			e.X2, e.Y2 = snap(e.X2, e.Y2)
+			e.X3, e.Y3 = snap(e.X3, e.Y3)
+			e.X4, e.Y4 = snap(e.X4, e.Y4)
+		case *Insert:
+			e.X, e.Y = snap(e.X, e.Y)
+			snapAttributes(e.Attributes, snap)
+		case *LWPolyline:
+			snapVertices(e.Vertices, snap)
+		case *Spline:
+			snapVertices(e.ControlPoints, snap)
+		case *Image:
+			e.X, e.Y = snap(e.X, e.Y)
+		case *Hatch:
+			for _, boundary := range e.Boundaries {
+				snapVertices(boundary, snap)
+			}
+		case *Dimension:
+			e.X1, e.Y1 = snap(e.X1, e.Y1)
+			e.X2, e.Y2 = snap(e.X2, e.Y2)
+			e.LineX, e.LineY = snap(e.LineX, e.LineY)
+			e.TextX, e.TextY = snap(e.TextX, e.TextY)
+			snapEntities(e.Entities, snap)
+		}
+	}
+}
+
+// snapVertices applies snap to each vertex in place.
+func snapVertices(vertices []Vertex, snap func(x, y float64) (float64, float64)) {
+	for i := range vertices {
+		vertices[i].X, vertices[i].Y = snap(vertices[i].X, vertices[i].Y)
+	}
+}
+
+// snapAttributes applies snap to each attribute position in place.
+func snapAttributes(attrs []Attribute, snap func(x, y float64) (float64, float64)) {
+	for i := range attrs {
+		attrs[i].X, attrs[i].Y = snap(attrs[i].X, attrs[i].Y)
+	}
+}
@@ -0,0 +1,32 @@
+package dxf
+
+import "testing"
+
+func TestDocumentSnapVertices(t *testing.T) {
+	a := NewLine(0, 0, 10+1e-9, 5-1e-9)
+	b := NewLine(10, 5, 20, 0)
+	doc := NewDocument().
+		AddEntity(a).
+		AddEntity(b).
+		AddEntity(&LWPolyline{Vertices: []Vertex{{1.0000004, 2}, {3, 3.9999996}}}).
+		AddBlock(Block{Name: "B", BaseX: 0.3000001, Entities: []Entity{NewPoint(-1e-10, 7)}})
+
+	doc.SnapVertices(1e-6)
+
+	if a.X2 != b.X1 || a.Y2 != b.Y1 {
+		t.Errorf("expected a shared endpoint, got (%v, %v) and (%v, %v)", a.X2, a.Y2, b.X1, b.Y1)
+	}
+	if v := doc.Entities[2].(*LWPolyline).Vertices; !approxEqual(v[0].X, 1) || !approxEqual(v[1].Y, 4) {
+		t.Errorf("polyline vertices not snapped: %v", v)
+	}
+	if p := doc.Blocks[0].Entities[0].(*Point); p.X != 0 {
+		t.Errorf("block point not snapped: %v", p.X)
+	}
+
+	// A zero grid leaves the document unchanged
+	c := NewLine(0, 0, 1+1e-9, 0)
+	NewDocument().AddEntity(c).SnapVertices(0)
+	if c.X2 != 1+1e-9 {
+		t.Errorf("expected no snapping with a zero grid, got %v", c.X2)
+	}
+}
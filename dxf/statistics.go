@@ -0,0 +1,54 @@
+package dxf
+
+// Statistics summarizes the contents of a Document.
+type Statistics struct {
+	// EntityCount is the number of model space entities.
+	EntityCount int
+
+	// PaperSpaceEntityCount is the number of paper space entities.
+	PaperSpaceEntityCount int
+
+	// EntitiesByType counts model space entities by DXF type, e.g. "LINE".
+	EntitiesByType map[string]int
+
+	// EntitiesByLayer counts model space entities by layer name.
+	EntitiesByLayer map[string]int
+
+	// LayerCount is the number of layer definitions.
+	LayerCount int
+
+	// BlockCount is the number of block definitions.
+	BlockCount int
+
+	// MinX, MinY, MaxX, MaxY is the bounding box of the model space
+	// entities, as returned by Document.BoundingBox.
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Statistics returns entity counts, table sizes, and the overall bounding
+// box of the document. Entities inside block definitions are not counted.
+//
+// Example:
+//
+//	stats := doc.Statistics()
+//	fmt.Printf("%d lines on %d layers\n", stats.EntitiesByType["LINE"], stats.LayerCount)
+func (d *Document) Statistics() Statistics {
+	stats := Statistics{
+		EntityCount:           len(d.Entities),
+		PaperSpaceEntityCount: len(d.PaperSpaceEntities),
+		EntitiesByType:        make(map[string]int),
+		EntitiesByLayer:       make(map[string]int),
+		LayerCount:            len(d.Layers),
+		BlockCount:            len(d.Blocks),
+	}
+
+	for _, e := range d.Entities {
+		stats.EntitiesByType[e.EntityType()]++
+		if layer, ok := layerOf(e); ok {
+			stats.EntitiesByLayer[layer]++
+		}
+	}
+
+	stats.MinX, stats.MinY, stats.MaxX, stats.MaxY = d.BoundingBox()
+	return stats
+}
@@ -0,0 +1,31 @@
+package dxf
+
+import "testing"
+
+func TestDocument_Statistics(t *testing.T) {
+	doc := NewDocument().
+		AddLayer("A", 1, "CONTINUOUS").
+		AddLine(0, 0, 100, 50, WithLineLayer("A")).
+		AddLine(-10, 0, 0, 0, WithLineLayer("A")).
+		AddCircle(0, 0, 5).
+		AddText(0, 0, "note").
+		AddBlock(Block{Name: "B", Entities: []Entity{NewLine(0, 0, 1, 1)}})
+
+	stats := doc.Statistics()
+
+	if stats.EntityCount != 4 {
+		t.Errorf("EntityCount: got %d, want 4", stats.EntityCount)
+	}
+	if stats.EntitiesByType["LINE"] != 2 || stats.EntitiesByType["CIRCLE"] != 1 || stats.EntitiesByType["TEXT"] != 1 {
+		t.Errorf("EntitiesByType: got %v", stats.EntitiesByType)
+	}
+	if stats.EntitiesByLayer["A"] != 2 || stats.EntitiesByLayer["0"] != 2 {
+		t.Errorf("EntitiesByLayer: got %v", stats.EntitiesByLayer)
+	}
+	if stats.LayerCount != 2 || stats.BlockCount != 1 {
+		t.Errorf("LayerCount/BlockCount: got %d/%d, want 2/1", stats.LayerCount, stats.BlockCount)
+	}
+	if stats.MinX != -10 || stats.MaxX != 100 || stats.MinY != -5 || stats.MaxY != 50 {
+		t.Errorf("bounding box: got (%v, %v)-(%v, %v)", stats.MinX, stats.MinY, stats.MaxX, stats.MaxY)
+	}
+}
@@ -0,0 +1,121 @@
+package dxf
+
+// sxfColorBase is added to an SXF color number to form the JWW pen color:
+// Jw_cad stores SXF extended colors 1-256 as pen colors 101-356.
+const sxfColorBase = 100
+
+// sxfPredefinedColors are the 16 predefined colors of the SXF specification,
+// indexed by SXF color number - 1. Colors 17-256 are user-defined and stored
+// in the drawing, which the parser does not read.
+var sxfPredefinedColors = [16][3]uint8{
+	{0, 0, 0},       // 1 black
+	{255, 0, 0},     // 2 red
+	{0, 255, 0},     // 3 green
+	{0, 0, 255},     // 4 blue
+	{255, 255, 0},   // 5 yellow
+	{255, 0, 255},   // 6 magenta
+	{0, 255, 255},   // 7 cyan
+	{255, 255, 255}, // 8 white
+	{192, 0, 128},   // 9 deeppink
+	{192, 128, 64},  // 10 brown
+	{255, 128, 0},   // 11 orange
+	{128, 192, 128}, // 12 lightgreen
+	{0, 128, 255},   // 13 lightblue
+	{128, 64, 255},  // 14 lavender
+	{192, 192, 192}, // 15 lightgray
+	{128, 128, 128}, // 16 darkgray
+}
+
+// SXFColorRGB returns the RGB value of a JWW pen color that selects one of
+// the predefined SXF colors (pen colors 101-116). ok is false for other
+// pen colors, including user-defined SXF colors.
+//
+// Example:
+//
+//	r, g, b, _ := dxf.SXFColorRGB(111) // SXF orange: 255, 128, 0
+func SXFColorRGB(penColor uint16) (r, g, b uint8, ok bool) {
+	n := int(penColor) - sxfColorBase
+	if n < 1 || n > len(sxfPredefinedColors) {
+		return 0, 0, 0, false
+	}
+	c := sxfPredefinedColors[n-1]
+	return c[0], c[1], c[2], true
+}
+
+// mapSXFColor converts a JWW pen color to DXF ACI like mapColor, but maps
+// the predefined SXF colors to the ACI color nearest to their RGB value.
+// Black and white become ACI 7, which CAD software draws in the foreground
+// color. Other pen colors fall back to mapColor.
+func mapSXFColor(penColor uint16) int {
+	r, g, b, ok := SXFColorRGB(penColor)
+	if !ok {
+		return mapColor(penColor)
+	}
+	if r == g && g == b && (r == 0 || r == 255) {
+		return 7
+	}
+	return nearestACI(r, g, b)
+}
+
+// nearestACI returns the ACI color (1-255) closest to r, g, b by squared
+// RGB distance. Ties go to the lower ACI number.
+func nearestACI(r, g, b uint8) int {
+	best, bestDist := 1, -1
+	for aci := 1; aci <= 255; aci++ {
+		ar, ag, ab := aciRGB(aci)
+		dr, dg, db := int(r)-int(ar), int(g)-int(ag), int(b)-int(ab)
+		if dist := dr*dr + dg*dg + db*db; bestDist < 0 || dist < bestDist {
+			best, bestDist = aci, dist
+		}
+	}
+	return best
+}
+
+// aciStandardColors are the RGB values of ACI colors 1-9.
+var aciStandardColors = [9][3]uint8{
+	{255, 0, 0}, {255, 255, 0}, {0, 255, 0}, {0, 255, 255}, {0, 0, 255},
+	{255, 0, 255}, {255, 255, 255}, {128, 128, 128}, {192, 192, 192},
+}
+
+// aciRGB returns the RGB value of ACI color aci (1-255) in the standard
+// AutoCAD palette. Colors 10-249 cycle through 24 hues in 15 degree steps,
+// each in five shades with a saturated and a pastel variant; 250-255 are grays.
+func aciRGB(aci int) (r, g, b uint8) {
+	switch {
+	case aci >= 1 && aci <= 9:
+		c := aciStandardColors[aci-1]
+		return c[0], c[1], c[2]
+	case aci >= 250 && aci <= 255:
+		v := [6]uint8{51, 91, 132, 173, 214, 255}[aci-250]
+		return v, v, v
+	case aci < 10 || aci > 255:
+		return 0, 0, 0
+	}
+
+	hue, shade := (aci-10)/10, (aci-10)%10
+	hi := [5]int{255, 165, 127, 76, 38}[shade/2]
+	lo := 0
+	if shade%2 == 1 {
+		lo = hi / 2
+	}
+	sector, f := hue/4, hue%4 // 60 degree sectors of four 15 degree steps
+	rise := lo + (hi-lo)*f/4
+	fall := hi - (hi-lo)*f/4
+
+	var c [3]int
+	switch sector {
+	case 0:
+		c = [3]int{hi, rise, lo}
+	case 1:
+		c = [3]int{fall, hi, lo}
+	case 2:
+		c = [3]int{lo, hi, rise}
+	case 3:
+		c = [3]int{lo, fall, hi}
+	case 4:
+		c = [3]int{rise, lo, hi}
+	default:
+		c = [3]int{hi, lo, fall}
+	}
+	return uint8(c[0]), uint8(c[1]), uint8(c[2])
+}
@@ -0,0 +1,73 @@
+package dxf
+
+import (
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestSXFColorRGB(t *testing.T) {
+	tests := []struct {
+		penColor uint16
+		rgb      [3]uint8
+		ok       bool
+	}{
+		{101, [3]uint8{0, 0, 0}, true},       // black
+		{102, [3]uint8{255, 0, 0}, true},     // red
+		{109, [3]uint8{192, 0, 128}, true},   // deeppink
+		{111, [3]uint8{255, 128, 0}, true},   // orange
+		{116, [3]uint8{128, 128, 128}, true}, // darkgray
+		{117, [3]uint8{}, false},             // user-defined
+		{100, [3]uint8{}, false},             // below the SXF range
+		{8, [3]uint8{}, false},               // standard JWW color
+	}
+
+	for _, tt := range tests {
+		r, g, b, ok := SXFColorRGB(tt.penColor)
+		if ok != tt.ok || [3]uint8{r, g, b} != tt.rgb {
+			t.Errorf("SXFColorRGB(%d) = %d, %d, %d, %v; want %v, %v", tt.penColor, r, g, b, ok, tt.rgb, tt.ok)
+		}
+	}
+}
+
+func TestMapSXFColor(t *testing.T) {
+	tests := []struct {
+		penColor uint16
+		expected int
+		name     string
+	}{
+		{101, 7, "black -> foreground"},
+		{102, 1, "red"},
+		{103, 3, "green"},
+		{104, 5, "blue"},
+		{108, 7, "white -> foreground"},
+		{111, 30, "orange"},
+		{115, 9, "lightgray"},
+		{116, 8, "darkgray"},
+		{150, 60, "user-defined keeps legacy mapping"},
+		{8, 1, "standard color unchanged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapSXFColor(tt.penColor); got != tt.expected {
+				t.Errorf("mapSXFColor(%d) = %d, want %d", tt.penColor, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertSXFPalette(t *testing.T) {
+	doc := &jww.Document{Entities: []jww.Entity{
+		&jww.Line{EntityBase: jww.EntityBase{PenColor: 111}, EndX: 1},
+	}}
+
+	legacy := ConvertDocument(doc).Entities[0].(*Line)
+	if legacy.Color != 21 {
+		t.Errorf("legacy color: got %d, want 21", legacy.Color)
+	}
+	sxf := ConvertDocumentWithOptions(doc, ConvertOptions{SXFPalette: true}).Entities[0].(*Line)
+	if sxf.Color != 30 {
+		t.Errorf("SXF palette color: got %d, want 30 (orange)", sxf.Color)
+	}
+}
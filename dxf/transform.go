@@ -221,6 +221,10 @@ func (t *Text) Translate(dx, dy float64) *Text {
 		Rotation: t.Rotation,
 		Content:  t.Content,
 		Style:    t.Style,
+		HAlign:   t.HAlign,
+		VAlign:   t.VAlign,
+		AlignX:   t.AlignX + dx,
+		AlignY:   t.AlignY + dy,
 	}
 }
 
@@ -232,6 +236,11 @@ func (t *Text) Translate(dx, dy float64) *Text {
 //	text := dxf.NewText(10, 10, "Hello", dxf.WithTextRotation(0))
 //	rotated := text.Rotate(45) // Rotation becomes 45°
 func (t *Text) Rotate(angleDeg float64) *Text {
+	// The alignment point turns with the text about the insertion point
+	alignX, alignY := TranslationMatrix(-t.X, -t.Y).
+		Then(RotationMatrix(angleDeg)).
+		Then(TranslationMatrix(t.X, t.Y)).
+		Apply(t.AlignX, t.AlignY)
 	return &Text{
 		Layer:    t.Layer,
 		Color:    t.Color,
@@ -241,6 +250,10 @@ func (t *Text) Rotate(angleDeg float64) *Text {
 		Rotation: t.Rotation + angleDeg,
 		Content:  t.Content,
 		Style:    t.Style,
+		HAlign:   t.HAlign,
+		VAlign:   t.VAlign,
+		AlignX:   alignX,
+		AlignY:   alignY,
 	}
 }
 
@@ -261,6 +274,10 @@ func (t *Text) Scale(factor float64) *Text {
 		Rotation: t.Rotation,
 		Content:  t.Content,
 		Style:    t.Style,
+		HAlign:   t.HAlign,
+		VAlign:   t.VAlign,
+		AlignX:   t.AlignX,
+		AlignY:   t.AlignY,
 	}
 }
 
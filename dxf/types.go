@@ -24,6 +24,11 @@
 //	w.WriteDocument(doc)
 package dxf
 
+import (
+	"math"
+	"strings"
+)
+
 // Document represents a complete DXF document structure.
 // It contains layer definitions, drawing entities, and optional block definitions.
 type Document struct {
@@ -33,8 +38,134 @@ type Document struct {
 	// Entities contains all drawing entities in the document.
 	Entities []Entity
 
+	// PaperSpaceEntities contains entities drawn in the paper space layout,
+	// such as title blocks. They are written to the ENTITIES section with
+	// group code 67 set to 1.
+	PaperSpaceEntities []Entity
+
 	// Blocks contains reusable block definitions.
 	Blocks []Block
+
+	// Groups are written as GROUP objects in the OBJECTS section, so CAD
+	// software selects their members together. Members that are not
+	// written, such as entities removed from the document, are left out.
+	// R12 output has no groups.
+	Groups []Group
+
+	// LineTypes are linetypes written to the LTYPE table in addition to
+	// the standard ones (CONTINUOUS, DASHED, ...), which they cannot replace.
+	LineTypes []LineType
+
+	// TextStyles are text styles written to the STYLE table in addition to
+	// STANDARD, which they cannot replace.
+	TextStyles []TextStyle
+
+	// LineTypeScale is the global linetype scale written as $LTSCALE.
+	// 0 is written as 1.0.
+	LineTypeScale float64
+
+	// DimStyle holds the sizes of the STANDARD dimension style written to
+	// the DIMSTYLE table.
+	DimStyle DimStyle
+
+	// DimScale is the overall dimension scale written as $DIMSCALE and as
+	// the DIMSCALE of the STANDARD dimension style, which multiplies its
+	// sizes, e.g. 100 for dimensions drawn at 1:100. 0 is written as 1.0.
+	DimScale float64
+
+	// Comments are written as DXF comments (group code 999) at the start
+	// of the file, one per line. CAD software ignores them when loading.
+	Comments []string
+
+	// Units is the drawing unit, written as $INSUNITS.
+	// The zero value is Unitless.
+	Units Units
+
+	// PointMode is the display style of all POINT entities, written as
+	// $PDMODE (0 = dot, 3 = X, 34 = circle with a cross, ...). DXF has no
+	// per-point style, so it applies to the whole drawing.
+	PointMode int
+
+	// PointSize is the POINT display size written as $PDSIZE: positive
+	// values are drawing units, negative values a percentage of the
+	// viewport, and 0 means 5% of the viewport.
+	PointSize float64
+
+	// CurrentLayer is the layer that is current when the drawing is
+	// opened, written as $CLAYER. Empty means layer "0".
+	CurrentLayer string
+}
+
+// LineType is a custom DXF linetype definition.
+type LineType struct {
+	// Name is the linetype name entities refer to.
+	Name string
+
+	// Description is shown by CAD software next to the name.
+	Description string
+
+	// Pattern holds the dash lengths as positive and the gap lengths as
+	// negative values (0 is a dot), repeated along the line.
+	Pattern []float64
+}
+
+// Group is a named set of entities selected together, written as a DXF
+// GROUP object.
+type Group struct {
+	// Name is the group name in the ACAD_GROUP dictionary.
+	Name string
+
+	// Description is shown by CAD software next to the name.
+	Description string
+
+	// Entities are the member entities, which must be written as part of
+	// the same document.
+	Entities []Entity
+}
+
+// TextStyle is a DXF text style, which texts refer to by name.
+type TextStyle struct {
+	// Name is the style name texts refer to.
+	Name string
+
+	// Font is the primary font file, e.g. "msgothic.ttc" for a TrueType
+	// font or "txt" for the SHX font of STANDARD.
+	Font string
+}
+
+// DimStyle holds the sizes of a DXF dimension style in paper units, which
+// CAD software multiplies by the dimension scale. Zero values are written
+// as the ISO-25 defaults given below.
+type DimStyle struct {
+	// ArrowSize is the arrowhead length (DIMASZ, default 2.5).
+	ArrowSize float64
+
+	// TextHeight is the dimension text height (DIMTXT, default 2.5).
+	TextHeight float64
+
+	// ExtensionOffset is the gap between the measured point and the start
+	// of the extension line (DIMEXO, default 0.625).
+	ExtensionOffset float64
+
+	// ExtensionExtension is how far the extension line extends beyond the
+	// dimension line (DIMEXE, default 1.25).
+	ExtensionExtension float64
+}
+
+// withDefaults returns s with zero sizes replaced by their defaults.
+func (s DimStyle) withDefaults() DimStyle {
+	orDefault := func(v, def float64) float64 {
+		if v == 0 {
+			return def
+		}
+		return v
+	}
+	return DimStyle{
+		ArrowSize:          orDefault(s.ArrowSize, 2.5),
+		TextHeight:         orDefault(s.TextHeight, 2.5),
+		ExtensionOffset:    orDefault(s.ExtensionOffset, 0.625),
+		ExtensionExtension: orDefault(s.ExtensionExtension, 1.25),
+	}
 }
 
 // Layer represents a DXF layer definition.
@@ -75,9 +206,26 @@ type GroupCode struct {
 	Code int
 
 	// Value is the associated value (string, int, or float64).
+	// The writer rejects other types with ErrUnsupportedValue; the Str, Int
+	// and Float constructors guarantee a supported type.
 	Value interface{}
 }
 
+// Str returns a group code with a string value.
+func Str(code int, value string) GroupCode {
+	return GroupCode{code, value}
+}
+
+// Int returns a group code with an integer value.
+func Int(code int, value int) GroupCode {
+	return GroupCode{code, value}
+}
+
+// Float returns a group code with a floating point value.
+func Float(code int, value float64) GroupCode {
+	return GroupCode{code, value}
+}
+
 // Line represents a DXF LINE entity.
 // A line is defined by two points in 2D or 3D space.
 type Line struct {
@@ -90,11 +238,18 @@ type Line struct {
 	// LineType specifies the line pattern (e.g., "CONTINUOUS", "DASHED").
 	LineType string
 
+	// LineTypeScale is the entity linetype scale (group code 48).
+	// It is written only for non-continuous linetypes; 0 omits it.
+	LineTypeScale float64
+
 	// X1, Y1 are the coordinates of the line's start point.
 	X1, Y1 float64
 
 	// X2, Y2 are the coordinates of the line's end point.
 	X2, Y2 float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "LINE".
@@ -102,7 +257,7 @@ func (l *Line) EntityType() string { return "LINE" }
 
 // GroupCodes returns the DXF group codes for this line entity.
 func (l *Line) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineTypeScale([]GroupCode{
 		{0, "LINE"},
 		{8, l.Layer},
 		{62, l.Color},
@@ -113,7 +268,25 @@ func (l *Line) GroupCodes() []GroupCode {
 		{11, l.X2},
 		{21, l.Y2},
 		{31, 0.0},
+	}, l.LineType, l.LineTypeScale)
+}
+
+// withLineTypeScale inserts the linetype scale (group code 48) after the
+// linetype (group code 6) when the scale is set and the linetype is not
+// continuous, since a scale has no visible effect on solid lines.
+func withLineTypeScale(codes []GroupCode, lineType string, scale float64) []GroupCode {
+	if scale == 0 || lineType == "" || strings.EqualFold(lineType, "CONTINUOUS") {
+		return codes
 	}
+	for i, gc := range codes {
+		if gc.Code == 6 {
+			scaled := make([]GroupCode, 0, len(codes)+1)
+			scaled = append(scaled, codes[:i+1]...)
+			scaled = append(scaled, GroupCode{48, scale})
+			return append(scaled, codes[i+1:]...)
+		}
+	}
+	return codes
 }
 
 // Circle represents a DXF CIRCLE entity.
@@ -128,12 +301,19 @@ type Circle struct {
 	// LineType specifies the line pattern for the circle outline.
 	LineType string
 
+	// LineTypeScale is the entity linetype scale (group code 48).
+	// It is written only for non-continuous linetypes; 0 omits it.
+	LineTypeScale float64
+
 	// CenterX, CenterY are the coordinates of the circle's center point.
 	CenterX float64
 	CenterY float64
 
 	// Radius is the circle's radius.
 	Radius float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "CIRCLE".
@@ -141,7 +321,7 @@ func (c *Circle) EntityType() string { return "CIRCLE" }
 
 // GroupCodes returns the DXF group codes for this circle entity.
 func (c *Circle) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineTypeScale([]GroupCode{
 		{0, "CIRCLE"},
 		{8, c.Layer},
 		{62, c.Color},
@@ -150,7 +330,7 @@ func (c *Circle) GroupCodes() []GroupCode {
 		{20, c.CenterY},
 		{30, 0.0},
 		{40, c.Radius},
-	}
+	}, c.LineType, c.LineTypeScale)
 }
 
 // Arc represents a DXF ARC entity.
@@ -165,6 +345,10 @@ type Arc struct {
 	// LineType specifies the line pattern for the arc.
 	LineType string
 
+	// LineTypeScale is the entity linetype scale (group code 48).
+	// It is written only for non-continuous linetypes; 0 omits it.
+	LineTypeScale float64
+
 	// CenterX, CenterY are the coordinates of the arc's center point.
 	CenterX float64
 	CenterY float64
@@ -177,13 +361,16 @@ type Arc struct {
 
 	// EndAngle is the ending angle in degrees (0-360).
 	EndAngle float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "ARC".
 func (a *Arc) EntityType() string { return "ARC" }
 
 func (a *Arc) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineTypeScale([]GroupCode{
 		{0, "ARC"},
 		{8, a.Layer},
 		{62, a.Color},
@@ -194,7 +381,7 @@ func (a *Arc) GroupCodes() []GroupCode {
 		{40, a.Radius},
 		{50, a.StartAngle},
 		{51, a.EndAngle},
-	}
+	}, a.LineType, a.LineTypeScale)
 }
 
 // Ellipse represents a DXF ELLIPSE entity.
@@ -209,6 +396,10 @@ type Ellipse struct {
 	// LineType specifies the line pattern for the ellipse.
 	LineType string
 
+	// LineTypeScale is the entity linetype scale (group code 48).
+	// It is written only for non-continuous linetypes; 0 omits it.
+	LineTypeScale float64
+
 	// CenterX, CenterY are the coordinates of the ellipse's center point.
 	CenterX float64
 	CenterY float64
@@ -225,13 +416,16 @@ type Ellipse struct {
 
 	// EndParam is the end parameter in radians (2*PI for full ellipse).
 	EndParam float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "ELLIPSE".
 func (e *Ellipse) EntityType() string { return "ELLIPSE" }
 
 func (e *Ellipse) GroupCodes() []GroupCode {
-	return []GroupCode{
+	return withLineTypeScale([]GroupCode{
 		{0, "ELLIPSE"},
 		{8, e.Layer},
 		{62, e.Color},
@@ -245,7 +439,7 @@ func (e *Ellipse) GroupCodes() []GroupCode {
 		{40, e.MinorRatio},
 		{41, e.StartParam},
 		{42, e.EndParam},
-	}
+	}, e.LineType, e.LineTypeScale)
 }
 
 // Point represents a DXF POINT entity.
@@ -262,6 +456,9 @@ type Point struct {
 
 	// X, Y are the coordinates of the point.
 	X, Y float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "POINT".
@@ -306,34 +503,118 @@ type Text struct {
 
 	// Style is the text style name (e.g., "STANDARD").
 	Style string
+
+	// HAlign and VAlign are the horizontal and vertical justification
+	// (group codes 72 and 73). The zero values are left and baseline.
+	HAlign TextHAlign
+	VAlign TextVAlign
+
+	// AlignX, AlignY are the second alignment point (group codes 11 and
+	// 21), written only for justified text. The text is placed relative to
+	// it, e.g. centered on it for TextHAlignCenter; aligned and fit text
+	// runs from X, Y to it.
+	AlignX, AlignY float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
+}
+
+// TextHAlign is the horizontal justification of a TEXT entity. The values
+// are the codes of DXF group code 72.
+type TextHAlign int
+
+// Horizontal text justifications.
+const (
+	TextHAlignLeft    TextHAlign = 0
+	TextHAlignCenter  TextHAlign = 1
+	TextHAlignRight   TextHAlign = 2
+	TextHAlignAligned TextHAlign = 3 // between X, Y and AlignX, AlignY, height scaled to fit
+	TextHAlignMiddle  TextHAlign = 4 // centered horizontally and vertically
+	TextHAlignFit     TextHAlign = 5 // between X, Y and AlignX, AlignY, width scaled to fit
+)
+
+// TextVAlign is the vertical justification of a TEXT entity. The values
+// are the codes of DXF group code 73.
+type TextVAlign int
+
+// Vertical text justifications.
+const (
+	TextVAlignBaseline TextVAlign = 0
+	TextVAlignBottom   TextVAlign = 1
+	TextVAlignMiddle   TextVAlign = 2
+	TextVAlignTop      TextVAlign = 3
+)
+
+// justified reports whether the text has a non-default justification,
+// which places it by the alignment point.
+func (t *Text) justified() bool {
+	return t.HAlign != TextHAlignLeft || t.VAlign != TextVAlignBaseline
 }
 
 // EntityType returns "TEXT".
 func (t *Text) EntityType() string { return "TEXT" }
 
+// GroupCodes returns the DXF group codes for this text entity.
+// TEXT values cannot span lines, so content containing line breaks is
+// written as one TEXT entity per line, each offset below the previous one
+// by TextLineSpacing times the text height (perpendicular to the rotation).
 func (t *Text) GroupCodes() []GroupCode {
-	codes := []GroupCode{
-		{0, "TEXT"},
-		{8, EscapeUnicode(t.Layer)},
-		{62, t.Color},
-		{6, t.LineType},
-		{10, t.X},
-		{20, t.Y},
-		{30, 0.0},
-		{40, t.Height},
-		{1, EscapeUnicode(t.Content)},
-	}
-	if t.Rotation != 0 {
-		codes = append(codes, GroupCode{50, t.Rotation})
-	}
-	if t.Style != "" {
-		codes = append(codes, GroupCode{7, t.Style})
+	var codes []GroupCode
+	angle := t.Rotation * math.Pi / 180.0
+	step := t.Height * TextLineSpacing
+
+	for i, line := range t.Lines() {
+		offset := step * float64(i)
+		codes = append(codes,
+			GroupCode{0, "TEXT"},
+			GroupCode{8, EscapeUnicode(t.Layer)},
+			GroupCode{62, t.Color},
+			GroupCode{6, t.LineType},
+			GroupCode{10, t.X + offset*math.Sin(angle)},
+			GroupCode{20, t.Y - offset*math.Cos(angle)},
+			GroupCode{30, 0.0},
+			GroupCode{40, t.Height},
+			GroupCode{1, EscapeUnicode(line)},
+		)
+		if t.Rotation != 0 {
+			codes = append(codes, GroupCode{50, t.Rotation})
+		}
+		if t.Style != "" {
+			codes = append(codes, GroupCode{7, EscapeUnicode(t.Style)})
+		}
+		if t.justified() {
+			codes = append(codes,
+				GroupCode{72, int(t.HAlign)},
+				GroupCode{11, t.AlignX + offset*math.Sin(angle)},
+				GroupCode{21, t.AlignY - offset*math.Cos(angle)},
+				GroupCode{31, 0.0},
+				GroupCode{73, int(t.VAlign)},
+			)
+		}
 	}
 	return codes
 }
 
+// TextLineSpacing is the distance between the baselines of consecutive
+// lines of multi-line text, as a multiple of the text height.
+const TextLineSpacing = 5.0 / 3.0
+
+// Lines returns the text content split at line breaks ("\r\n", "\n" or "\r").
+// Content without line breaks is returned as a single line.
+func (t *Text) Lines() []string {
+	return splitLines(t.Content)
+}
+
+// splitLines splits s at line breaks ("\r\n", "\n" or "\r").
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.Split(s, "\n")
+}
+
 // Solid represents a DXF SOLID entity (filled triangle or quadrilateral).
 // Solids are used to create filled areas and hatching patterns.
+// DXF draws the outline through corners 1, 2, 4, 3; see NormalizeWinding.
 type Solid struct {
 	// Layer is the name of the layer this entity belongs to.
 	Layer string
@@ -355,6 +636,9 @@ type Solid struct {
 
 	// X4, Y4 are the coordinates of the fourth corner point (same as X3, Y3 for triangles).
 	X4, Y4 float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "SOLID".
@@ -408,14 +692,22 @@ type Insert struct {
 
 	// Rotation is the rotation angle in degrees.
 	Rotation float64
+
+	// Attributes are written as ATTRIB entities following the insert,
+	// ended by a SEQEND. Their positions are in world coordinates.
+	Attributes []Attribute
+
+	// XData is extended entity data, written after the entity.
+	XData XData
 }
 
 // EntityType returns "INSERT".
 func (i *Insert) EntityType() string { return "INSERT" }
 
-// GroupCodes returns the DXF group codes for this insert entity.
+// GroupCodes returns the DXF group codes for this insert entity,
+// followed by its attributes if it has any.
 func (i *Insert) GroupCodes() []GroupCode {
-	return []GroupCode{
+	codes := []GroupCode{
 		{0, "INSERT"},
 		{8, i.Layer},
 		{62, i.Color},
@@ -429,6 +721,495 @@ func (i *Insert) GroupCodes() []GroupCode {
 		{43, 1.0}, // ScaleZ
 		{50, i.Rotation},
 	}
+	if len(i.Attributes) == 0 {
+		return codes
+	}
+
+	codes = append(codes, GroupCode{66, 1}) // attributes follow
+	for _, a := range i.Attributes {
+		codes = append(codes,
+			GroupCode{0, "ATTRIB"},
+			GroupCode{8, i.Layer},
+			GroupCode{62, i.Color},
+		)
+		codes = append(codes, a.groupCodes()...)
+	}
+	return append(codes, GroupCode{0, "SEQEND"}, GroupCode{8, i.Layer})
+}
+
+// Attribute is a block attribute: a tagged text value attached to an
+// insert (ATTRIB), or its definition in a block (ATTDEF).
+type Attribute struct {
+	// Tag identifies the attribute, e.g. "TITLE". It cannot contain spaces.
+	Tag string
+
+	// Value is the attribute text. In a block definition it is the
+	// default value.
+	Value string
+
+	// X, Y are the coordinates of the text insertion point.
+	X, Y float64
+
+	// Height is the text height.
+	Height float64
+
+	// Rotation is the text rotation angle in degrees.
+	Rotation float64
+}
+
+// groupCodes returns the group codes shared by ATTRIB and ATTDEF, after
+// the entity type, layer, and color.
+func (a Attribute) groupCodes() []GroupCode {
+	return []GroupCode{
+		{10, a.X},
+		{20, a.Y},
+		{30, 0.0},
+		{40, a.Height},
+		{1, EscapeUnicode(a.Value)},
+		{50, a.Rotation},
+		{2, a.Tag},
+		{70, 0}, // visible, not constant
+	}
+}
+
+// Vertex is a 2D polyline vertex.
+type Vertex struct {
+	X, Y float64
+}
+
+// LWPolyline represents a DXF LWPOLYLINE entity (lightweight 2D polyline).
+// When writing R12 files, which have no LWPOLYLINE, the writer emits the
+// equivalent POLYLINE/VERTEX/SEQEND sequence instead.
+type LWPolyline struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// LineType specifies the line pattern for the polyline.
+	LineType string
+
+	// LineTypeScale is the entity linetype scale (group code 48).
+	// It is written only for non-continuous linetypes; 0 omits it.
+	LineTypeScale float64
+
+	// Vertices are the polyline's points in drawing order.
+	Vertices []Vertex
+
+	// Closed connects the last vertex back to the first.
+	Closed bool
+
+	// XData is extended entity data, written after the entity.
+	XData XData
+}
+
+// EntityType returns "LWPOLYLINE".
+func (p *LWPolyline) EntityType() string { return "LWPOLYLINE" }
+
+// GroupCodes returns the DXF group codes for this polyline entity.
+func (p *LWPolyline) GroupCodes() []GroupCode {
+	codes := []GroupCode{
+		{0, "LWPOLYLINE"},
+		{8, p.Layer},
+		{62, p.Color},
+		{6, p.LineType},
+		{90, len(p.Vertices)},
+		{70, p.flags()},
+	}
+	for _, v := range p.Vertices {
+		codes = append(codes, GroupCode{10, v.X}, GroupCode{20, v.Y})
+	}
+	return withLineTypeScale(codes, p.LineType, p.LineTypeScale)
+}
+
+// legacyGroupCodes returns the R12 POLYLINE/VERTEX/SEQEND representation.
+func (p *LWPolyline) legacyGroupCodes() []GroupCode {
+	codes := []GroupCode{
+		{0, "POLYLINE"},
+		{8, p.Layer},
+		{62, p.Color},
+		{6, p.LineType},
+		{66, 1}, // vertices follow
+		{10, 0.0},
+		{20, 0.0},
+		{30, 0.0},
+		{70, p.flags()},
+	}
+	for _, v := range p.Vertices {
+		codes = append(codes,
+			GroupCode{0, "VERTEX"},
+			GroupCode{8, p.Layer},
+			GroupCode{10, v.X},
+			GroupCode{20, v.Y},
+			GroupCode{30, 0.0},
+		)
+	}
+	return append(codes, GroupCode{0, "SEQEND"}, GroupCode{8, p.Layer})
+}
+
+// flags returns the polyline flag value (1 = closed).
+func (p *LWPolyline) flags() int {
+	if p.Closed {
+		return 1
+	}
+	return 0
+}
+
+// Spline represents a DXF SPLINE entity (planar B-spline).
+// When Knots is empty a clamped uniform knot vector is generated.
+// R12 output, which has no SPLINE, approximates the curve with a POLYLINE.
+//
+// Spline is a builder-only type: JWW has no spline entity, so the converter
+// never produces one, but programs building DXF documents directly can add
+// smooth curves with it.
+type Spline struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// LineType specifies the line pattern for the spline.
+	LineType string
+
+	// LineTypeScale is the entity linetype scale (group code 48).
+	// It is written only for non-continuous linetypes; 0 omits it.
+	LineTypeScale float64
+
+	// Degree is the spline degree (default 3, limited to len(ControlPoints)-1).
+	Degree int
+
+	// ControlPoints are the spline's control points in order.
+	ControlPoints []Vertex
+
+	// Knots is the knot vector; it must have len(ControlPoints)+Degree+1
+	// values when set.
+	Knots []float64
+
+	// XData is extended entity data, written after the entity.
+	XData XData
+}
+
+// EntityType returns "SPLINE".
+func (s *Spline) EntityType() string { return "SPLINE" }
+
+// GroupCodes returns the DXF group codes for this spline entity.
+func (s *Spline) GroupCodes() []GroupCode {
+	knots := s.knotVector()
+	codes := []GroupCode{
+		{0, "SPLINE"},
+		{8, s.Layer},
+		{62, s.Color},
+		{6, s.LineType},
+		{70, 8}, // planar
+		{71, s.degree()},
+		{72, len(knots)},
+		{73, len(s.ControlPoints)},
+		{74, 0}, // no fit points
+	}
+	for _, k := range knots {
+		codes = append(codes, GroupCode{40, k})
+	}
+	for _, v := range s.ControlPoints {
+		codes = append(codes, GroupCode{10, v.X}, GroupCode{20, v.Y}, GroupCode{30, 0.0})
+	}
+	return withLineTypeScale(codes, s.LineType, s.LineTypeScale)
+}
+
+// degree returns the effective degree: Degree (default 3) limited so that
+// there are more control points than the degree.
+func (s *Spline) degree() int {
+	d := s.Degree
+	if d <= 0 {
+		d = 3
+	}
+	if n := len(s.ControlPoints); d > n-1 {
+		d = n - 1
+	}
+	if d < 1 {
+		d = 1
+	}
+	return d
+}
+
+// knotVector returns Knots if set, otherwise a clamped uniform knot vector
+// running from 0 to 1.
+func (s *Spline) knotVector() []float64 {
+	if len(s.Knots) > 0 {
+		return s.Knots
+	}
+	n, p := len(s.ControlPoints), s.degree()
+	knots := make([]float64, n+p+1)
+	spans := n - p
+	for i := range knots {
+		switch {
+		case i <= p:
+			knots[i] = 0
+		case i >= n:
+			knots[i] = 1
+		default:
+			knots[i] = float64(i-p) / float64(spans)
+		}
+	}
+	return knots
+}
+
+// Image represents a DXF IMAGE entity (raster image reference).
+// The writer links each image to an IMAGEDEF object in the OBJECTS section,
+// sharing one definition between images that reference the same file.
+type Image struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// LineType specifies the line pattern applied to the image frame.
+	LineType string
+
+	// X, Y are the coordinates of the image's lower-left corner.
+	X, Y float64
+
+	// Width, Height are the displayed size of the image in drawing units.
+	Width, Height float64
+
+	// Rotation is the rotation angle in degrees around the lower-left corner.
+	Rotation float64
+
+	// FilePath is the path of the referenced image file.
+	FilePath string
+
+	// PixelWidth, PixelHeight are the image dimensions in pixels.
+	// Values <= 0 are written as 1.
+	PixelWidth, PixelHeight int
+
+	// XData is extended entity data, written after the entity.
+	XData XData
+}
+
+// EntityType returns "IMAGE".
+func (i *Image) EntityType() string { return "IMAGE" }
+
+// GroupCodes returns the DXF group codes for this image entity.
+// The handle, IMAGEDEF and reactor references are added by the Writer.
+func (i *Image) GroupCodes() []GroupCode {
+	pw, ph := i.pixelSize()
+	angle := i.Rotation * math.Pi / 180.0
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	uLen := i.Width / float64(pw)
+	vLen := i.Height / float64(ph)
+
+	return []GroupCode{
+		{0, "IMAGE"},
+		{8, i.Layer},
+		{62, i.Color},
+		{6, i.LineType},
+		{90, 0},
+		{10, i.X},
+		{20, i.Y},
+		{30, 0.0},
+		{11, uLen * cos},
+		{21, uLen * sin},
+		{31, 0.0},
+		{12, -vLen * sin},
+		{22, vLen * cos},
+		{32, 0.0},
+		{13, float64(pw)},
+		{23, float64(ph)},
+		{70, 7}, // show image, show when not aligned, use clipping boundary
+		{280, 0},
+		{281, 50},
+		{282, 50},
+		{283, 0},
+		{71, 1}, // rectangular clipping boundary
+		{91, 2},
+		{14, -0.5},
+		{24, -0.5},
+		{14, float64(pw) - 0.5},
+		{24, float64(ph) - 0.5},
+	}
+}
+
+// pixelSize returns the pixel dimensions, substituting 1 for unset values.
+func (i *Image) pixelSize() (int, int) {
+	pw, ph := i.PixelWidth, i.PixelHeight
+	if pw <= 0 {
+		pw = 1
+	}
+	if ph <= 0 {
+		ph = 1
+	}
+	return pw, ph
+}
+
+// Hatch represents a DXF HATCH entity with a solid fill.
+// Each boundary is a closed polygon; boundaries inside another one cut
+// holes into the fill (odd parity). R12 output, which has no HATCH, writes
+// the boundaries as closed POLYLINEs without fill.
+type Hatch struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// LineType specifies the line pattern applied to the hatch.
+	LineType string
+
+	// Boundaries are the closed boundary loops. The last vertex of a loop
+	// connects back to the first.
+	Boundaries [][]Vertex
+
+	// XData is extended entity data, written after the entity.
+	XData XData
+}
+
+// EntityType returns "HATCH".
+func (h *Hatch) EntityType() string { return "HATCH" }
+
+// GroupCodes returns the DXF group codes for this hatch entity.
+func (h *Hatch) GroupCodes() []GroupCode {
+	codes := []GroupCode{
+		{0, "HATCH"},
+		{8, h.Layer},
+		{62, h.Color},
+		{6, h.LineType},
+		{10, 0.0}, // elevation point
+		{20, 0.0},
+		{30, 0.0},
+		{210, 0.0}, // extrusion direction
+		{220, 0.0},
+		{230, 1.0},
+		{2, "SOLID"},
+		{70, 1}, // solid fill
+		{71, 0}, // not associative
+		{91, len(h.Boundaries)},
+	}
+	for i, boundary := range h.Boundaries {
+		pathType := 2 // polyline
+		if i == 0 {
+			pathType |= 1 // external
+		}
+		codes = append(codes,
+			GroupCode{92, pathType},
+			GroupCode{72, 0}, // no bulges
+			GroupCode{73, 1}, // closed
+			GroupCode{93, len(boundary)},
+		)
+		for _, v := range boundary {
+			codes = append(codes, GroupCode{10, v.X}, GroupCode{20, v.Y})
+		}
+		codes = append(codes, GroupCode{97, 0}) // no source objects
+	}
+	codes = append(codes,
+		GroupCode{75, 0}, // odd parity hatch style
+		GroupCode{76, 1}, // predefined pattern
+		GroupCode{98, 0}, // no seed points
+	)
+	return codes
+}
+
+// legacyGroupCodes returns the R12 representation: one closed POLYLINE per boundary.
+func (h *Hatch) legacyGroupCodes() []GroupCode {
+	var codes []GroupCode
+	for _, boundary := range h.Boundaries {
+		outline := &LWPolyline{
+			Layer:    h.Layer,
+			Color:    h.Color,
+			LineType: h.LineType,
+			Vertices: boundary,
+			Closed:   true,
+		}
+		codes = append(codes, outline.legacyGroupCodes()...)
+	}
+	return codes
+}
+
+// Dimension represents a DXF DIMENSION entity: an aligned linear dimension
+// between two measured points. CAD software draws a dimension with an
+// anonymous block; the Writer writes Entities as that block, named *D1,
+// *D2, ... in document order, so the dimension keeps its look while
+// staying editable as a dimension.
+type Dimension struct {
+	// Layer is the name of the layer this entity belongs to.
+	Layer string
+
+	// Color is the ACI color number (0 = BYLAYER).
+	Color int
+
+	// LineType specifies the line pattern applied to the dimension.
+	LineType string
+
+	// Style is the dimension style name. An empty name uses STANDARD,
+	// the style written to the DIMSTYLE table.
+	Style string
+
+	// X1, Y1 and X2, Y2 are the measured points, where the extension
+	// lines start.
+	X1, Y1 float64
+	X2, Y2 float64
+
+	// LineX, LineY is a point on the dimension line, written as its
+	// definition point.
+	LineX, LineY float64
+
+	// TextX, TextY is the middle point of the dimension text.
+	TextX, TextY float64
+
+	// Text is the dimension text. An empty text shows the measurement.
+	Text string
+
+	// Entities are the lines, texts, and arrowheads that draw the
+	// dimension, in world coordinates.
+	Entities []Entity
+
+	// XData is extended entity data, written after the entity.
+	XData XData
+}
+
+// EntityType returns "DIMENSION".
+func (d *Dimension) EntityType() string { return "DIMENSION" }
+
+// GroupCodes returns the DXF group codes for this dimension entity.
+// The name of the block drawing the dimension is added by the Writer.
+func (d *Dimension) GroupCodes() []GroupCode {
+	return d.groupCodes("")
+}
+
+// groupCodes returns the group codes of the dimension, referring to the
+// named block unless block is empty.
+func (d *Dimension) groupCodes(block string) []GroupCode {
+	style := d.Style
+	if style == "" {
+		style = "STANDARD"
+	}
+	codes := []GroupCode{
+		{0, "DIMENSION"},
+		{8, d.Layer},
+		{62, d.Color},
+		{6, d.LineType},
+	}
+	if block != "" {
+		codes = append(codes, GroupCode{2, block})
+	}
+	return append(codes,
+		GroupCode{3, style},
+		GroupCode{10, d.LineX},
+		GroupCode{20, d.LineY},
+		GroupCode{30, 0.0},
+		GroupCode{11, d.TextX},
+		GroupCode{21, d.TextY},
+		GroupCode{31, 0.0},
+		GroupCode{70, 1 | 32}, // aligned, block used by this dimension only
+		GroupCode{1, d.Text},
+		GroupCode{13, d.X1},
+		GroupCode{23, d.Y1},
+		GroupCode{33, 0.0},
+		GroupCode{14, d.X2},
+		GroupCode{24, d.Y2},
+		GroupCode{34, 0.0},
+	)
 }
 
 // Block represents a DXF block definition.
@@ -444,4 +1225,8 @@ type Block struct {
 
 	// Entities contains the entities that comprise this block.
 	Entities []Entity
+
+	// Attributes are written as ATTDEF entities after Entities. Their
+	// positions are in block coordinates and their values are defaults.
+	Attributes []Attribute
 }
@@ -0,0 +1,80 @@
+package dxf
+
+// Units is the drawing unit of a document. The values are the codes of the
+// DXF $INSUNITS header variable.
+type Units int
+
+// Drawing units. Unitless drawings are never scaled by ConvertUnits.
+const (
+	Unitless    Units = 0
+	Inches      Units = 1
+	Feet        Units = 2
+	Millimeters Units = 4
+	Centimeters Units = 5
+	Meters      Units = 6
+	Kilometers  Units = 7
+)
+
+// unitMillimeters is the length of one unit in millimeters.
+var unitMillimeters = map[Units]float64{
+	Inches:      25.4,
+	Feet:        304.8,
+	Millimeters: 1,
+	Centimeters: 10,
+	Meters:      1000,
+	Kilometers:  1000000,
+}
+
+// String returns the unit name, e.g. "millimeters".
+func (u Units) String() string {
+	switch u {
+	case Inches:
+		return "inches"
+	case Feet:
+		return "feet"
+	case Millimeters:
+		return "millimeters"
+	case Centimeters:
+		return "centimeters"
+	case Meters:
+		return "meters"
+	case Kilometers:
+		return "kilometers"
+	}
+	return "unitless"
+}
+
+// imperial reports whether u belongs to the imperial measurement system.
+func (u Units) imperial() bool {
+	return u == Inches || u == Feet
+}
+
+// ConvertUnits scales all geometry from the document's current units to
+// the given units and records them in Units. The global linetype scale and
+// an absolute point size are scaled too, so dash patterns and points keep
+// their physical size. If either unit is
+// Unitless, only Units is changed. Returns the document for chaining.
+//
+// Example:
+//
+//	doc := dxf.ConvertDocument(jwwDoc) // millimeters
+//	doc.ConvertUnits(dxf.Meters)
+func (d *Document) ConvertUnits(to Units) *Document {
+	fromMM, okFrom := unitMillimeters[d.Units]
+	toMM, okTo := unitMillimeters[to]
+	if okFrom && okTo && fromMM != toMM {
+		factor := fromMM / toMM
+		d.Transform(ScalingMatrix(factor))
+
+		ltScale := d.LineTypeScale
+		if ltScale == 0 {
+			ltScale = 1.0
+		}
+		d.LineTypeScale = ltScale * factor
+		if d.PointSize > 0 {
+			d.PointSize *= factor // absolute size in drawing units
+		}
+	}
+	d.Units = to
+	return d
+}
@@ -0,0 +1,64 @@
+package dxf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/jww"
+)
+
+func TestDocument_ConvertUnits(t *testing.T) {
+	doc := NewDocument().AddLine(0, 0, 1500, 250)
+	doc.Units = Millimeters
+
+	doc.ConvertUnits(Meters)
+
+	if doc.Units != Meters {
+		t.Errorf("Units: got %v, want meters", doc.Units)
+	}
+	line := doc.Entities[0].(*Line)
+	if !approxEqual(line.X2, 1.5) || !approxEqual(line.Y2, 0.25) {
+		t.Errorf("line end: got (%v, %v), want (1.5, 0.25)", line.X2, line.Y2)
+	}
+	if !approxEqual(doc.LineTypeScale, 0.001) {
+		t.Errorf("LineTypeScale: got %v, want 0.001", doc.LineTypeScale)
+	}
+}
+
+func TestDocument_ConvertUnitsUnitless(t *testing.T) {
+	doc := NewDocument().AddLine(0, 0, 10, 0)
+
+	doc.ConvertUnits(Inches)
+
+	if line := doc.Entities[0].(*Line); line.X2 != 10 {
+		t.Errorf("unitless geometry should not be scaled, got %v", line.X2)
+	}
+	if doc.Units != Inches {
+		t.Errorf("Units: got %v, want inches", doc.Units)
+	}
+}
+
+func TestWriteDocument_InsUnits(t *testing.T) {
+	doc := ConvertDocument(&jww.Document{})
+	if doc.Units != Millimeters {
+		t.Fatalf("converted Units: got %v, want millimeters", doc.Units)
+	}
+
+	output := ToString(doc)
+	if !strings.Contains(output, "$INSUNITS\n 70\n4\n") {
+		t.Errorf("Expected $INSUNITS 4 (millimeters)")
+	}
+	if !strings.Contains(output, "$MEASUREMENT\n 70\n1\n") {
+		t.Errorf("Expected metric $MEASUREMENT")
+	}
+
+	doc.Units = Inches
+	output = ToString(doc)
+	if !strings.Contains(output, "$INSUNITS\n 70\n1\n") || !strings.Contains(output, "$MEASUREMENT\n 70\n0\n") {
+		t.Errorf("Expected $INSUNITS 1 and imperial $MEASUREMENT for inches")
+	}
+
+	if strings.Contains(ToStringWithOptions(doc, WriteOptions{Version: R12}), "$INSUNITS") {
+		t.Errorf("Expected no $INSUNITS in R12 output")
+	}
+}
@@ -0,0 +1,161 @@
+package dxf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Severity classifies a ValidationIssue.
+type Severity int
+
+const (
+	// SeverityWarning marks output that CAD programs usually accept but may
+	// display differently than intended, such as an undefined layer.
+	SeverityWarning Severity = iota
+
+	// SeverityError marks output that CAD programs are likely to reject.
+	SeverityError
+)
+
+// String returns "warning" or "error".
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// ValidationIssue is a single integrity problem found by Validate.
+type ValidationIssue struct {
+	// Severity tells whether the problem is likely to break the output.
+	Severity Severity
+
+	// Message describes the problem and where it was found,
+	// e.g. "entity 3 (LINE): undefined layer \"A-1\"".
+	Message string
+}
+
+// String returns the issue as "severity: message".
+func (i ValidationIssue) String() string {
+	return i.Severity.String() + ": " + i.Message
+}
+
+// Validate checks a document for integrity problems before it is written:
+//   - entities referencing undefined layers
+//   - inserts referencing undefined blocks
+//   - entities or layers using non-continuous linetypes without an LTYPE definition
+//   - texts using a style other than STANDARD without a STYLE definition
+//   - dimensions using a dimension style other than STANDARD
+//   - NaN or infinite coordinates and other numeric values
+//   - circles with zero or negative radius
+//
+// Paper space entities, entities inside block definitions, and the
+// entities drawing dimensions are checked as well. An empty result means no problems were found.
+//
+// Example:
+//
+//	for _, issue := range dxf.Validate(doc) {
+//		log.Println(issue)
+//	}
+func Validate(doc *Document) []ValidationIssue {
+	v := validator{
+		layers:    map[string]bool{"0": true}, // layer 0 is always written
+		blocks:    make(map[string]bool),
+		lineTypes: make(map[string]bool),
+		styles:    map[string]bool{"STANDARD": true},
+	}
+	for _, l := range doc.Layers {
+		v.layers[l.Name] = true
+	}
+	for _, b := range doc.Blocks {
+		v.blocks[b.Name] = true
+	}
+	for _, lt := range standardLinetypes {
+		v.lineTypes[lt.name] = true
+	}
+	for _, lt := range doc.LineTypes {
+		v.lineTypes[strings.ToUpper(lt.Name)] = true
+	}
+	for _, style := range doc.TextStyles {
+		v.styles[strings.ToUpper(style.Name)] = true
+	}
+
+	for _, l := range doc.Layers {
+		if !v.lineTypeDefined(l.LineType) {
+			v.addf(SeverityError, "layer %q: undefined linetype %q", l.Name, l.LineType)
+		}
+	}
+	for i, e := range doc.Entities {
+		v.checkEntity(e, fmt.Sprintf("entity %d (%s)", i, e.EntityType()))
+	}
+	for i, e := range doc.PaperSpaceEntities {
+		v.checkEntity(e, fmt.Sprintf("paper space entity %d (%s)", i, e.EntityType()))
+	}
+	for _, b := range doc.Blocks {
+		for i, e := range b.Entities {
+			v.checkEntity(e, fmt.Sprintf("block %q entity %d (%s)", b.Name, i, e.EntityType()))
+		}
+	}
+
+	return v.issues
+}
+
+// validator collects issues while walking a document.
+type validator struct {
+	layers    map[string]bool
+	blocks    map[string]bool
+	lineTypes map[string]bool
+	styles    map[string]bool
+	issues    []ValidationIssue
+}
+
+func (v *validator) addf(severity Severity, format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lineTypeDefined reports whether a linetype name resolves to an LTYPE entry.
+// An empty name means the default (BYLAYER) and is always valid.
+func (v *validator) lineTypeDefined(name string) bool {
+	return name == "" || v.lineTypes[strings.ToUpper(name)]
+}
+
+func (v *validator) checkEntity(e Entity, where string) {
+	if layer, ok := layerOf(e); ok && !v.layers[layer] {
+		v.addf(SeverityWarning, "%s: undefined layer %q", where, layer)
+	}
+	if lineType, ok := lineTypeOf(e); ok && !v.lineTypeDefined(lineType) {
+		v.addf(SeverityError, "%s: undefined linetype %q", where, lineType)
+	}
+
+	switch ent := e.(type) {
+	case *Insert:
+		if !v.blocks[ent.BlockName] {
+			v.addf(SeverityError, "%s: undefined block %q", where, ent.BlockName)
+		}
+	case *Text:
+		if ent.Style != "" && !v.styles[strings.ToUpper(ent.Style)] {
+			v.addf(SeverityError, "%s: undefined text style %q", where, ent.Style)
+		}
+	case *Circle:
+		if ent.Radius <= 0 {
+			v.addf(SeverityError, "%s: radius %g is not positive", where, ent.Radius)
+		}
+	case *Dimension:
+		if ent.Style != "" && !strings.EqualFold(ent.Style, "STANDARD") {
+			v.addf(SeverityError, "%s: undefined dimension style %q", where, ent.Style)
+		}
+		for i, sub := range ent.Entities {
+			v.checkEntity(sub, fmt.Sprintf("%s entity %d (%s)", where, i, sub.EntityType()))
+		}
+	}
+
+	for _, gc := range e.GroupCodes() {
+		if f, ok := gc.Value.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			v.addf(SeverityError, "%s: group code %d has non-finite value %v", where, gc.Code, f)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package dxf
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidate_Clean(t *testing.T) {
+	doc := NewDocument().
+		AddLayer("A", 1, "DASHED").
+		AddBlock(Block{Name: "B"}).
+		AddLine(0, 0, 10, 10, WithLineLayer("A")).
+		AddCircle(5, 5, 2).
+		AddInsert("B", 0, 0)
+
+	if issues := Validate(doc); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestValidate_Issues(t *testing.T) {
+	tests := []struct {
+		name     string
+		entity   Entity
+		severity Severity
+		contains string
+	}{
+		{"undefined layer", &Line{Layer: "missing", X2: 1}, SeverityWarning, `undefined layer "missing"`},
+		{"undefined block", &Insert{Layer: "0", BlockName: "nowhere", ScaleX: 1, ScaleY: 1}, SeverityError, `undefined block "nowhere"`},
+		{"undefined linetype", &Line{Layer: "0", LineType: "ZIGZAG", X2: 1}, SeverityError, `undefined linetype "ZIGZAG"`},
+		{"undefined text style", &Text{Layer: "0", Height: 1, Content: "A", Style: "MS_GOTHIC"}, SeverityError, `undefined text style "MS_GOTHIC"`},
+		{"NaN coordinate", &Line{Layer: "0", X2: math.NaN()}, SeverityError, "non-finite value NaN"},
+		{"infinite coordinate", &Point{Layer: "0", Y: math.Inf(1)}, SeverityError, "non-finite value +Inf"},
+		{"zero radius", &Circle{Layer: "0", Radius: 0}, SeverityError, "radius 0 is not positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := NewDocument().AddEntity(tt.entity)
+
+			issues := Validate(doc)
+			if len(issues) != 1 {
+				t.Fatalf("Expected 1 issue, got %v", issues)
+			}
+			if issues[0].Severity != tt.severity {
+				t.Errorf("Severity: got %v, want %v", issues[0].Severity, tt.severity)
+			}
+			if !strings.Contains(issues[0].Message, tt.contains) {
+				t.Errorf("Message %q does not contain %q", issues[0].Message, tt.contains)
+			}
+		})
+	}
+}
+
+func TestValidate_LayerLinetypeAndBlockEntities(t *testing.T) {
+	doc := NewDocument().
+		AddLayer("A", 1, "ZIGZAG").
+		AddBlock(Block{Name: "B", Entities: []Entity{&Circle{Layer: "0", Radius: -1}}})
+
+	issues := Validate(doc)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %v", issues)
+	}
+	if !strings.Contains(issues[0].Message, `layer "A": undefined linetype`) {
+		t.Errorf("Unexpected first issue: %v", issues[0])
+	}
+	if !strings.Contains(issues[1].Message, `block "B" entity 0 (CIRCLE)`) {
+		t.Errorf("Unexpected second issue: %v", issues[1])
+	}
+}
@@ -1,9 +1,14 @@
 package dxf
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -14,13 +19,119 @@ import (
 type Writer struct {
 	w          io.Writer
 	nextHandle int
+	version    Version
+	maxSagitta float64
+	strict     bool
+	precision  int // decimal places for floats; -1 means shortest
+
+	// imageDefs holds one IMAGEDEF per referenced image file, in first-use order.
+	imageDefs []imageDef
+	// images records the handles assigned to each written IMAGE entity.
+	images []imageRef
+	// groupHandles maps each group member to the handles it was written
+	// with, one per TEXT for multi-line texts, or none until it is written.
+	groupHandles map[Entity][]string
+	// dimensionBlocks maps each written dimension to the name of the
+	// anonymous block drawing it.
+	dimensionBlocks map[*Dimension]string
+}
+
+// imageDef describes an IMAGEDEF object shared by images with the same file.
+type imageDef struct {
+	handle      string
+	path        string
+	pixelWidth  int
+	pixelHeight int
 }
 
+// imageRef records the handles linking an IMAGE entity to its objects.
+type imageRef struct {
+	handle  string
+	reactor string
+	def     string
+}
+
+// Version identifies a DXF file format version by its $ACADVER value.
+type Version string
+
+// Supported DXF versions.
+const (
+	R12   Version = "AC1009" // AutoCAD R12: no LWPOLYLINE, ELLIPSE or IMAGE
+	R2000 Version = "AC1015" // AutoCAD 2000 (default)
+	R2004 Version = "AC1018" // AutoCAD 2004
+	R2007 Version = "AC1021" // AutoCAD 2007
+	R2010 Version = "AC1024" // AutoCAD 2010
+	R2013 Version = "AC1027" // AutoCAD 2013
+	R2018 Version = "AC1032" // AutoCAD 2018
+)
+
+// WriteOptions controls optional behavior of the Writer.
+// The zero value produces the same output as NewWriter.
+type WriteOptions struct {
+	// Version is the DXF version to target (default R2000).
+	// It sets $ACADVER and selects which entity types may be emitted:
+	// R12 output approximates ellipses with POLYLINE entities, writes
+	// LWPOLYLINE as POLYLINE, and omits IMAGE entities.
+	Version Version
+
+	// MaxSagitta is the largest allowed distance between a curve and the
+	// polyline approximating it in R12 output. Values <= 0 use 0.1% of each
+	// curve's radius.
+	MaxSagitta float64
+
+	// RejectNonFinite makes the writer fail with ErrNonFinite when a NaN or
+	// infinite value is about to be written. By default such values are
+	// written as 0, since no DXF reader accepts "NaN" or "Inf".
+	RejectNonFinite bool
+
+	// Precision is the number of decimal places written for floating-point
+	// values. Values <= 0 keep the default of 6.
+	Precision int
+
+	// ShortestFloats writes each floating-point value with the fewest digits
+	// that read back as the same float64 (without exponent notation),
+	// ignoring Precision. This drops trailing zeros while keeping full
+	// precision for large coordinates.
+	ShortestFloats bool
+}
+
+// ErrNonFinite is returned by a writer created with RejectNonFinite when a
+// group code value is NaN or infinite.
+var ErrNonFinite = errors.New("dxf: non-finite value")
+
+// ErrUnsupportedValue is returned when a group code value is not a string,
+// int, or float64.
+var ErrUnsupportedValue = errors.New("dxf: unsupported group code value type")
+
 // NewWriter creates a new DXF writer that outputs to the provided io.Writer.
 // The writer starts with handle counter at 1 and will auto-increment for each
 // entity requiring a unique handle.
 func NewWriter(w io.Writer) *Writer {
-	return &Writer{w: w, nextHandle: 1}
+	return NewWriterWithOptions(w, WriteOptions{})
+}
+
+// NewWriterWithOptions creates a DXF writer like NewWriter,
+// applying the behavior selected in opts.
+func NewWriterWithOptions(w io.Writer, opts WriteOptions) *Writer {
+	version := opts.Version
+	if version == "" {
+		version = R2000
+	}
+	precision := opts.Precision
+	if precision <= 0 {
+		precision = 6
+	}
+	if opts.ShortestFloats {
+		precision = -1
+	}
+	return &Writer{
+		w:          w,
+		nextHandle: 1,
+		version:    version,
+		maxSagitta: opts.MaxSagitta,
+		strict:     opts.RejectNonFinite,
+		precision:  precision,
+	}
 }
 
 // getHandle returns the next available handle as a hexadecimal string.
@@ -54,20 +165,37 @@ func EscapeUnicode(s string) string {
 // WriteDocument writes a complete DXF document to the output stream.
 //
 // The DXF file structure consists of the following sections in order:
+//  0. Comments (group code 999), if any
 //  1. HEADER section - document settings and variables
+//     CLASSES section - image classes (only when images are present)
 //  2. TABLES section - layer, linetype, and text style definitions
-//  3. BLOCKS section - block definitions
+//  3. BLOCKS section - block definitions, then the blocks drawing dimensions
 //  4. ENTITIES section - drawing entities
-//  5. EOF marker
+//  5. OBJECTS section - image definitions (only when images are present)
+//  6. EOF marker
 //
 // This method orchestrates writing all sections in the correct order
 // and with proper DXF formatting.
 func (w *Writer) WriteDocument(doc *Document) error {
+	// Comments precede the first section
+	if err := w.writeComments(doc.Comments); err != nil {
+		return err
+	}
+
+	w.groupHandles = w.groupMembers(doc)
+
 	// HEADER section
-	if err := w.writeHeader(); err != nil {
+	if err := w.writeHeader(doc); err != nil {
 		return err
 	}
 
+	// CLASSES section, declaring the classes of the image objects
+	if w.version != R12 && hasImages(doc) {
+		if err := w.writeClasses(); err != nil {
+			return err
+		}
+	}
+
 	// TABLES section
 	if err := w.writeTables(doc); err != nil {
 		return err
@@ -83,6 +211,11 @@ func (w *Writer) WriteDocument(doc *Document) error {
 		return err
 	}
 
+	// OBJECTS section
+	if err := w.writeObjects(doc); err != nil {
+		return err
+	}
+
 	// End of file
 	if err := w.writeGroupCode(0, "EOF"); err != nil {
 		return err
@@ -91,7 +224,19 @@ func (w *Writer) WriteDocument(doc *Document) error {
 	return nil
 }
 
-func (w *Writer) writeHeader() error {
+// writeComments writes each line of comments as a 999 group code.
+func (w *Writer) writeComments(comments []string) error {
+	for _, comment := range comments {
+		for _, line := range splitLines(comment) {
+			if err := w.writeGroupCode(999, EscapeUnicode(line)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *Writer) writeHeader(doc *Document) error {
 	// Header section with essential variables for ODA compatibility
 	if err := w.writeSection("HEADER"); err != nil {
 		return err
@@ -101,7 +246,7 @@ func (w *Writer) writeHeader() error {
 	if err := w.writeGroupCode(9, "$ACADVER"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(1, "AC1015"); err != nil { // AutoCAD 2000
+	if err := w.writeGroupCode(1, string(w.version)); err != nil {
 		return err
 	}
 
@@ -113,14 +258,75 @@ func (w *Writer) writeHeader() error {
 		return err
 	}
 
-	// Measurement units (metric)
+	// Measurement system (0 = imperial, 1 = metric)
+	measurement := 1
+	if doc.Units.imperial() {
+		measurement = 0
+	}
 	if err := w.writeGroupCode(9, "$MEASUREMENT"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(70, 1); err != nil {
+	if err := w.writeGroupCode(70, measurement); err != nil {
+		return err
+	}
+
+	// Drawing units ($INSUNITS was added after R12)
+	if w.version != R12 {
+		if err := w.writeGroupCode(9, "$INSUNITS"); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(70, int(doc.Units)); err != nil {
+			return err
+		}
+	}
+
+	// Global linetype scale
+	ltScale := doc.LineTypeScale
+	if ltScale == 0 {
+		ltScale = 1.0
+	}
+	if err := w.writeGroupCode(9, "$LTSCALE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(40, ltScale); err != nil {
 		return err
 	}
 
+	// Overall dimension scale
+	if err := w.writeGroupCode(9, "$DIMSCALE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(40, doc.dimScale()); err != nil {
+		return err
+	}
+
+	// Point display style and size
+	if err := w.writeGroupCode(9, "$PDMODE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(70, doc.PointMode); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(9, "$PDSIZE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(40, doc.PointSize); err != nil {
+		return err
+	}
+
+	// Drawing extents, resolved through block inserts
+	if minX, minY, maxX, maxY, ok := doc.extents(); ok {
+		extents := []GroupCode{
+			{9, "$EXTMIN"}, {10, minX}, {20, minY}, {30, 0.0},
+			{9, "$EXTMAX"}, {10, maxX}, {20, maxY}, {30, 0.0},
+		}
+		for _, gc := range extents {
+			if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Text style
 	if err := w.writeGroupCode(9, "$TEXTSTYLE"); err != nil {
 		return err
@@ -130,10 +336,14 @@ func (w *Writer) writeHeader() error {
 	}
 
 	// Current layer
+	currentLayer := doc.CurrentLayer
+	if currentLayer == "" {
+		currentLayer = "0"
+	}
 	if err := w.writeGroupCode(9, "$CLAYER"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(8, "0"); err != nil {
+	if err := w.writeGroupCode(8, currentLayer); err != nil {
 		return err
 	}
 
@@ -156,13 +366,84 @@ func (w *Writer) writeHeader() error {
 	return w.writeEndSection()
 }
 
+// imageClasses declares the classes of IMAGE entities and of the IMAGEDEF
+// and IMAGEDEF_REACTOR objects they are linked to, which are not built into
+// the DXF format.
+var imageClasses = []GroupCode{
+	{0, "CLASS"},
+	{1, "IMAGE"},
+	{2, "AcDbRasterImage"},
+	{3, "ISM"},
+	{90, 127}, // proxy capabilities
+	{280, 0},  // not a proxy
+	{281, 1},  // entity
+	{0, "CLASS"},
+	{1, "IMAGEDEF"},
+	{2, "AcDbRasterImageDef"},
+	{3, "ISM"},
+	{90, 0},
+	{280, 0},
+	{281, 0},
+	{0, "CLASS"},
+	{1, "IMAGEDEF_REACTOR"},
+	{2, "AcDbRasterImageDefReactor"},
+	{3, "ISM"},
+	{90, 1},
+	{280, 0},
+	{281, 0},
+}
+
+// writeClasses writes the CLASSES section with the image classes. It is
+// written for documents with images, which get IMAGEDEF objects (imageDefs)
+// once their entities are written.
+func (w *Writer) writeClasses() error {
+	if err := w.writeSection("CLASSES"); err != nil {
+		return err
+	}
+	for _, gc := range imageClasses {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+			return err
+		}
+	}
+	return w.writeEndSection()
+}
+
+// hasImages reports whether doc has IMAGE entities among the entities the
+// writer writes: model and paper space entities, block definition entities,
+// and the entities drawing dimensions.
+func hasImages(doc *Document) bool {
+	var found func(entities []Entity) bool
+	found = func(entities []Entity) bool {
+		for _, e := range entities {
+			switch e := e.(type) {
+			case *Image:
+				return true
+			case *Dimension:
+				if found(e.Entities) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if found(doc.Entities) || found(doc.PaperSpaceEntities) {
+		return true
+	}
+	for i := range doc.Blocks {
+		if found(doc.Blocks[i].Entities) {
+			return true
+		}
+	}
+	return false
+}
+
 func (w *Writer) writeTables(doc *Document) error {
 	if err := w.writeSection("TABLES"); err != nil {
 		return err
 	}
 
 	// LTYPE table
-	if err := w.writeLinetypeTable(); err != nil {
+	if err := w.writeLinetypeTable(doc); err != nil {
 		return err
 	}
 
@@ -172,32 +453,138 @@ func (w *Writer) writeTables(doc *Document) error {
 	}
 
 	// STYLE table (text styles)
-	if err := w.writeStyleTable(); err != nil {
+	if err := w.writeStyleTable(doc); err != nil {
+		return err
+	}
+
+	// DIMSTYLE table
+	if err := w.writeDimStyleTable(doc); err != nil {
 		return err
 	}
 
+	// APPID table, registering the applications of extended entity data
+	if apps := xdataApps(doc); len(apps) > 0 {
+		if err := w.writeAppIDTable(apps); err != nil {
+			return err
+		}
+	}
+
 	return w.writeEndSection()
 }
 
-func (w *Writer) writeLinetypeTable() error {
-	type linetypeDef struct {
-		name   string
-		desc   string
-		values []float64
+// writeDimStyleTable writes the DIMSTYLE table with the STANDARD dimension
+// style, sized by doc.DimStyle and doc.DimScale.
+func (w *Writer) writeDimStyleTable(doc *Document) error {
+	style := doc.DimStyle.withDefaults()
+
+	if err := w.writeGroupCode(0, "TABLE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(2, "DIMSTYLE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(5, w.getHandle()); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(70, 1); err != nil {
+		return err
+	}
+
+	// DIMSTYLE entries take their handle in code 105, as code 5 is DIMBLK
+	codes := []GroupCode{
+		{0, "DIMSTYLE"},
+		{105, w.getHandle()},
+		{2, "STANDARD"},
+		{70, 0},
+		{40, doc.dimScale()},
+		{41, style.ArrowSize},
+		{42, style.ExtensionOffset},
+		{44, style.ExtensionExtension},
+		{140, style.TextHeight},
+	}
+	for _, gc := range codes {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+			return err
+		}
+	}
+
+	return w.writeGroupCode(0, "ENDTAB")
+}
+
+// dimScale returns the overall dimension scale of doc, substituting 1.0
+// for an unset DimScale.
+func (d *Document) dimScale() float64 {
+	if d.DimScale == 0 {
+		return 1.0
+	}
+	return d.DimScale
+}
+
+// writeAppIDTable writes the APPID table with the given application names.
+func (w *Writer) writeAppIDTable(apps []string) error {
+	if err := w.writeGroupCode(0, "TABLE"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(2, "APPID"); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(5, w.getHandle()); err != nil {
+		return err
+	}
+	if err := w.writeGroupCode(70, len(apps)); err != nil {
+		return err
+	}
+
+	for _, name := range apps {
+		if err := w.writeGroupCode(0, "APPID"); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(5, w.getHandle()); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(2, name); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(70, 0); err != nil {
+			return err
+		}
 	}
 
-	linetypes := []linetypeDef{
-		{"BYLAYER", "", nil},
-		{"BYBLOCK", "", nil},
-		{"CONTINUOUS", "Solid line", nil},
-		{"DASHED", "Dashed line", []float64{0.6, -0.3}},
-		{"DASHEDX2", "Dashed line x2", []float64{1.2, -0.6}},
-		{"DASHDOT", "Dash dot", []float64{0.6, -0.2, 0.1, -0.2}},
-		{"DASHDOTX2", "Dash dot x2", []float64{1.2, -0.4, 0.2, -0.4}},
-		{"CENTER", "Center line", []float64{1.25, -0.25, 0.25, -0.25}},
-		{"CENTERX2", "Center line x2", []float64{2.5, -0.5, 0.5, -0.5}},
-		{"DOT", "Dotted line", []float64{0.1, -0.1}},
-		{"DOTX2", "Dotted line x2", []float64{0.2, -0.2}},
+	return w.writeGroupCode(0, "ENDTAB")
+}
+
+// linetypeDef is an entry of the LTYPE table.
+type linetypeDef struct {
+	name   string
+	desc   string
+	values []float64 // dash lengths; negative values are gaps
+}
+
+// standardLinetypes are the linetypes written to every LTYPE table. They
+// cover every name mapLineType can produce.
+var standardLinetypes = []linetypeDef{
+	{"BYLAYER", "", nil},
+	{"BYBLOCK", "", nil},
+	{"CONTINUOUS", "Solid line", nil},
+	{"DASHED", "Dashed line", []float64{0.6, -0.3}},
+	{"DASHEDX2", "Dashed line x2", []float64{1.2, -0.6}},
+	{"DASHDOT", "Dash dot", []float64{0.6, -0.2, 0.1, -0.2}},
+	{"DASHDOTX2", "Dash dot x2", []float64{1.2, -0.4, 0.2, -0.4}},
+	{"CENTER", "Center line", []float64{1.25, -0.25, 0.25, -0.25}},
+	{"CENTERX2", "Center line x2", []float64{2.5, -0.5, 0.5, -0.5}},
+	{"DOT", "Dotted line", []float64{0.1, -0.1}},
+	{"DOTX2", "Dotted line x2", []float64{0.2, -0.2}},
+}
+
+func (w *Writer) writeLinetypeTable(doc *Document) error {
+	linetypes := slices.Clone(standardLinetypes)
+	for _, lt := range doc.LineTypes {
+		standard := slices.ContainsFunc(standardLinetypes, func(s linetypeDef) bool {
+			return strings.EqualFold(s.name, lt.Name)
+		})
+		if !standard {
+			linetypes = append(linetypes, linetypeDef{lt.Name, EscapeUnicode(lt.Description), lt.Pattern})
+		}
 	}
 
 	if err := w.writeGroupCode(0, "TABLE"); err != nil {
@@ -247,6 +634,12 @@ func (w *Writer) writeLinetypeTable() error {
 			if err := w.writeGroupCode(49, v); err != nil {
 				return err
 			}
+			if w.version != R12 {
+				// Plain dash: no embedded shape or text
+				if err := w.writeGroupCode(74, 0); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -318,7 +711,14 @@ func (w *Writer) writeLayerTable(doc *Document) error {
 	return w.writeGroupCode(0, "ENDTAB")
 }
 
-func (w *Writer) writeStyleTable() error {
+func (w *Writer) writeStyleTable(doc *Document) error {
+	styles := []TextStyle{{Name: "STANDARD", Font: "txt"}}
+	for _, style := range doc.TextStyles {
+		if !strings.EqualFold(style.Name, "STANDARD") {
+			styles = append(styles, style)
+		}
+	}
+
 	if err := w.writeGroupCode(0, "TABLE"); err != nil {
 		return err
 	}
@@ -328,106 +728,456 @@ func (w *Writer) writeStyleTable() error {
 	if err := w.writeGroupCode(5, w.getHandle()); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(70, 1); err != nil {
+	if err := w.writeGroupCode(70, len(styles)); err != nil {
 		return err
 	}
 
-	// STANDARD style
-	if err := w.writeGroupCode(0, "STYLE"); err != nil {
-		return err
+	for _, style := range styles {
+		if err := w.writeGroupCode(0, "STYLE"); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(5, w.getHandle()); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(2, EscapeUnicode(style.Name)); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(70, 0); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(40, 0.0); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(41, 1.0); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(50, 0.0); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(71, 0); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(42, 2.5); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(3, EscapeUnicode(style.Font)); err != nil {
+			return err
+		}
+		if err := w.writeGroupCode(4, ""); err != nil {
+			return err
+		}
 	}
-	if err := w.writeGroupCode(5, w.getHandle()); err != nil {
+
+	return w.writeGroupCode(0, "ENDTAB")
+}
+
+func (w *Writer) writeBlocks(doc *Document) error {
+	if err := w.writeSection("BLOCKS"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(2, "STANDARD"); err != nil {
-		return err
+
+	// The paper space layout needs its block definition once it has entities
+	if len(doc.PaperSpaceEntities) > 0 {
+		if err := w.writeBlock(Block{Name: "*Paper_Space"}); err != nil {
+			return err
+		}
 	}
-	if err := w.writeGroupCode(70, 0); err != nil {
+
+	for _, block := range doc.Blocks {
+		if err := w.writeBlock(block); err != nil {
+			return err
+		}
+	}
+
+	// Each dimension is drawn by an anonymous block of its own
+	w.dimensionBlocks = make(map[*Dimension]string)
+	for _, d := range documentDimensions(doc) {
+		if _, ok := w.dimensionBlocks[d]; ok {
+			continue
+		}
+		name := fmt.Sprintf("%s%d", dimensionBlockPrefix, len(w.dimensionBlocks)+1)
+		w.dimensionBlocks[d] = name
+		if err := w.writeBlock(Block{Name: name, Entities: d.Entities}); err != nil {
+			return err
+		}
+	}
+
+	return w.writeEndSection()
+}
+
+// dimensionBlockPrefix starts the names of the anonymous blocks drawing
+// dimensions.
+const dimensionBlockPrefix = "*D"
+
+// documentDimensions returns the dimensions among the model space, paper
+// space, and block definition entities of doc, in that order.
+func documentDimensions(doc *Document) []*Dimension {
+	var dims []*Dimension
+	add := func(entities []Entity) {
+		for _, e := range entities {
+			if d, ok := e.(*Dimension); ok {
+				dims = append(dims, d)
+			}
+		}
+	}
+	add(doc.Entities)
+	add(doc.PaperSpaceEntities)
+	for i := range doc.Blocks {
+		add(doc.Blocks[i].Entities)
+	}
+	return dims
+}
+
+// writeBlock writes a BLOCK definition with its entities.
+func (w *Writer) writeBlock(block Block) error {
+	// Block header
+	if err := w.writeGroupCode(0, "BLOCK"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(40, 0.0); err != nil {
+	if err := w.writeGroupCode(8, "0"); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(41, 1.0); err != nil {
+	if err := w.writeGroupCode(2, block.Name); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(50, 0.0); err != nil {
+	flags := 0
+	if len(block.Attributes) > 0 {
+		flags = 2 // has attribute definitions
+	}
+	if strings.HasPrefix(block.Name, dimensionBlockPrefix) {
+		flags |= 1 // anonymous
+	}
+	if err := w.writeGroupCode(70, flags); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(71, 0); err != nil {
+	if err := w.writeGroupCode(10, block.BaseX); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(42, 2.5); err != nil {
+	if err := w.writeGroupCode(20, block.BaseY); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(3, "txt"); err != nil {
+	if err := w.writeGroupCode(30, 0.0); err != nil {
 		return err
 	}
-	if err := w.writeGroupCode(4, ""); err != nil {
+	if err := w.writeGroupCode(3, block.Name); err != nil {
 		return err
 	}
 
-	return w.writeGroupCode(0, "ENDTAB")
+	// Block entities
+	for _, entity := range block.Entities {
+		if err := w.writeEntity(entity); err != nil {
+			return err
+		}
+	}
+	for _, a := range block.Attributes {
+		codes := append([]GroupCode{{0, "ATTDEF"}, {8, "0"}}, a.groupCodes()...)
+		codes = append(codes, GroupCode{3, a.Tag}) // prompt
+		for _, gc := range codes {
+			if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Block end
+	if err := w.writeGroupCode(0, "ENDBLK"); err != nil {
+		return err
+	}
+	return w.writeGroupCode(8, "0")
 }
 
-func (w *Writer) writeBlocks(doc *Document) error {
-	if err := w.writeSection("BLOCKS"); err != nil {
+func (w *Writer) writeEntities(doc *Document) error {
+	if err := w.writeSection("ENTITIES"); err != nil {
 		return err
 	}
 
-	for _, block := range doc.Blocks {
-		// Block header
-		if err := w.writeGroupCode(0, "BLOCK"); err != nil {
-			return err
-		}
-		if err := w.writeGroupCode(8, "0"); err != nil {
+	for _, entity := range doc.Entities {
+		if err := w.writeEntity(entity); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(2, block.Name); err != nil {
+	}
+	for _, entity := range doc.PaperSpaceEntities {
+		if err := w.writePaperSpaceEntity(entity); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(70, 0); err != nil {
+	}
+
+	return w.writeEndSection()
+}
+
+// writePaperSpaceEntity writes an entity like writeEntity, marking it and
+// any subentities (such as the VERTEX and SEQEND of an R12 POLYLINE) with
+// group code 67 = 1.
+func (w *Writer) writePaperSpaceEntity(entity Entity) error {
+	for _, gc := range inPaperSpace(w.entityGroupCodes(entity)) {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(10, block.BaseX); err != nil {
-			return err
+	}
+	return nil
+}
+
+// inPaperSpace inserts {67, 1} after the entity type (and handle, if any)
+// of every entity in codes.
+func inPaperSpace(codes []GroupCode) []GroupCode {
+	marked := make([]GroupCode, 0, len(codes)+1)
+	for i, gc := range codes {
+		marked = append(marked, gc)
+		if gc.Code == 0 && (i+1 >= len(codes) || codes[i+1].Code != 5) ||
+			gc.Code == 5 && i > 0 && codes[i-1].Code == 0 {
+			marked = append(marked, GroupCode{67, 1})
 		}
-		if err := w.writeGroupCode(20, block.BaseY); err != nil {
+	}
+	return marked
+}
+
+func (w *Writer) writeEntity(entity Entity) error {
+	for _, gc := range w.entityGroupCodes(entity) {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
 			return err
 		}
-		if err := w.writeGroupCode(30, 0.0); err != nil {
-			return err
+	}
+	return nil
+}
+
+// entityGroupCodes returns the group codes written for an entity in the
+// target version, linking images to their IMAGEDEF objects.
+func (w *Writer) entityGroupCodes(entity Entity) []GroupCode {
+	var codes []GroupCode
+	if w.version == R12 {
+		codes = withoutCode(w.legacyGroupCodes(entity), 48) // no entity linetype scale in R12
+	} else if d, ok := entity.(*Dimension); ok {
+		codes = d.groupCodes(w.dimensionBlocks[d])
+	} else {
+		codes = entity.GroupCodes()
+	}
+
+	if _, ok := entity.(*Text); ok {
+		// Each line of a multi-line text is a TEXT entity of its own, which
+		// gets the handle and XData of the text
+		var linked []GroupCode
+		for _, line := range splitEntities(codes) {
+			linked = append(linked, w.linkEntity(entity, line)...)
 		}
-		if err := w.writeGroupCode(3, block.Name); err != nil {
-			return err
+		return linked
+	}
+	return w.linkEntity(entity, codes)
+}
+
+// linkEntity adds to codes the handle entity needs, if any, and its XData.
+func (w *Writer) linkEntity(entity Entity, codes []GroupCode) []GroupCode {
+	if w.version != R12 {
+		if img, ok := entity.(*Image); ok {
+			codes = w.linkImage(img, codes)
+		} else if w.isGroupMember(entity) {
+			codes = withHandle(codes, w.entityHandle(entity))
 		}
+	}
+	return withXData(codes, entity)
+}
 
-		// Block entities
-		for _, entity := range block.Entities {
-			if err := w.writeEntity(entity); err != nil {
-				return err
-			}
+// splitEntities splits codes into the codes of each entity they contain,
+// starting at each entity type (group code 0).
+func splitEntities(codes []GroupCode) [][]GroupCode {
+	var entities [][]GroupCode
+	for i, gc := range codes {
+		if gc.Code == 0 || i == 0 {
+			entities = append(entities, nil)
 		}
+		last := len(entities) - 1
+		entities[last] = append(entities[last], gc)
+	}
+	return entities
+}
 
-		// Block end
-		if err := w.writeGroupCode(0, "ENDBLK"); err != nil {
-			return err
+// legacyGroupCodes returns the R12 representation of an entity.
+// Entity types introduced after R12 are replaced by POLYLINE equivalents
+// (hatches by their unfilled boundaries), or dropped when no equivalent
+// exists (IMAGE).
+func (w *Writer) legacyGroupCodes(entity Entity) []GroupCode {
+	switch e := entity.(type) {
+	case *Ellipse:
+		return w.ellipsePolyline(e).legacyGroupCodes()
+	case *LWPolyline:
+		return e.legacyGroupCodes()
+	case *Spline:
+		return splinePolyline(e).legacyGroupCodes()
+	case *Hatch:
+		return e.legacyGroupCodes()
+	case *Image:
+		return nil
+	case *Dimension:
+		return e.groupCodes(w.dimensionBlocks[e])
+	}
+	return entity.GroupCodes()
+}
+
+// withoutCode returns codes with every group code equal to code removed.
+func withoutCode(codes []GroupCode, code int) []GroupCode {
+	kept := codes[:0:0]
+	for _, gc := range codes {
+		if gc.Code != code {
+			kept = append(kept, gc)
 		}
-		if err := w.writeGroupCode(8, "0"); err != nil {
-			return err
+	}
+	return kept
+}
+
+// ellipsePolyline approximates an ellipse or elliptical arc with a polyline
+// within the writer's MaxSagitta tolerance.
+func (w *Writer) ellipsePolyline(e *Ellipse) *LWPolyline {
+	vertices := e.Flatten(w.maxSagitta)
+
+	// A full ellipse ends where it starts; close it instead of repeating the vertex.
+	closed := math.Abs(e.EndParam-e.StartParam-2*math.Pi) < 1e-9 || e.EndParam == e.StartParam
+	if closed {
+		vertices = vertices[:len(vertices)-1]
+	}
+
+	return &LWPolyline{
+		Layer:    e.Layer,
+		Color:    e.Color,
+		LineType: e.LineType,
+		Vertices: vertices,
+		Closed:   closed,
+	}
+}
+
+// splineSegmentsPerSpan is the number of polyline segments used for each
+// knot span when approximating a spline.
+const splineSegmentsPerSpan = 16
+
+// splinePolyline approximates a spline with a polyline.
+func splinePolyline(s *Spline) *LWPolyline {
+	spans := len(s.ControlPoints) - s.degree()
+	return &LWPolyline{
+		Layer:    s.Layer,
+		Color:    s.Color,
+		LineType: s.LineType,
+		Vertices: s.Sample(spans * splineSegmentsPerSpan),
+	}
+}
+
+// linkImage assigns handles to an IMAGE entity and its IMAGEDEF_REACTOR,
+// and adds the references to the IMAGEDEF shared by the image's file path.
+func (w *Writer) linkImage(img *Image, codes []GroupCode) []GroupCode {
+	ref := imageRef{
+		handle:  w.entityHandle(img),
+		reactor: w.getHandle(),
+		def:     w.imageDefHandle(img),
+	}
+	w.images = append(w.images, ref)
+
+	linked := withHandle(codes, ref.handle)
+	return append(linked, GroupCode{340, ref.def}, GroupCode{360, ref.reactor})
+}
+
+// withHandle returns codes with a handle (group code 5) inserted after the
+// entity type.
+func withHandle(codes []GroupCode, handle string) []GroupCode {
+	if len(codes) == 0 {
+		return codes
+	}
+	linked := make([]GroupCode, 0, len(codes)+1)
+	linked = append(linked, codes[0], GroupCode{5, handle})
+	return append(linked, codes[1:]...)
+}
+
+// groupMembers returns the members of the document's groups, which are
+// written with handles so GROUP objects can refer to them. R12 output has
+// no groups.
+func (w *Writer) groupMembers(doc *Document) map[Entity][]string {
+	if w.version == R12 || len(doc.Groups) == 0 {
+		return nil
+	}
+	members := make(map[Entity][]string)
+	for _, g := range doc.Groups {
+		for _, e := range g.Entities {
+			if e != nil && reflect.TypeOf(e).Comparable() {
+				members[e] = nil
+			}
 		}
 	}
+	return members
+}
 
-	return w.writeEndSection()
+// isGroupMember reports whether entity belongs to one of the groups.
+func (w *Writer) isGroupMember(entity Entity) bool {
+	if len(w.groupHandles) == 0 || !reflect.TypeOf(entity).Comparable() {
+		return false
+	}
+	_, ok := w.groupHandles[entity]
+	return ok
 }
 
-func (w *Writer) writeEntities(doc *Document) error {
-	if err := w.writeSection("ENTITIES"); err != nil {
+// entityHandle returns a new handle for entity, recording it for the
+// GROUP objects if the entity is a group member.
+func (w *Writer) entityHandle(entity Entity) string {
+	handle := w.getHandle()
+	if w.isGroupMember(entity) {
+		w.groupHandles[entity] = append(w.groupHandles[entity], handle)
+	}
+	return handle
+}
+
+// imageDefHandle returns the IMAGEDEF handle for the image's file,
+// registering a new definition on first use.
+func (w *Writer) imageDefHandle(img *Image) string {
+	for _, def := range w.imageDefs {
+		if def.path == img.FilePath {
+			return def.handle
+		}
+	}
+	pw, ph := img.pixelSize()
+	def := imageDef{handle: w.getHandle(), path: img.FilePath, pixelWidth: pw, pixelHeight: ph}
+	w.imageDefs = append(w.imageDefs, def)
+	return def.handle
+}
+
+// writeObjects writes the OBJECTS section with the named object dictionary
+// and the dictionaries of the objects the document uses: ACAD_GROUP with a
+// GROUP per group with written members, and ACAD_IMAGE_DICT with an
+// IMAGEDEF per referenced image file plus an IMAGEDEF_REACTOR per IMAGE
+// entity. Nothing is written when the document has neither.
+func (w *Writer) writeObjects(doc *Document) error {
+	groups := w.writtenGroups(doc)
+	if len(w.imageDefs) == 0 && len(groups) == 0 {
+		return nil
+	}
+
+	if err := w.writeSection("OBJECTS"); err != nil {
 		return err
 	}
 
-	for _, entity := range doc.Entities {
-		if err := w.writeEntity(entity); err != nil {
+	rootHandle := w.getHandle()
+
+	// Root (named object) dictionary
+	codes := []GroupCode{
+		{0, "DICTIONARY"},
+		{5, rootHandle},
+		{330, "0"},
+		{100, "AcDbDictionary"},
+		{281, 1},
+	}
+	var groupDictHandle, imageDictHandle string
+	if len(groups) > 0 {
+		groupDictHandle = w.getHandle()
+		codes = append(codes, GroupCode{3, "ACAD_GROUP"}, GroupCode{350, groupDictHandle})
+	}
+	if len(w.imageDefs) > 0 {
+		imageDictHandle = w.getHandle()
+		codes = append(codes, GroupCode{3, "ACAD_IMAGE_DICT"}, GroupCode{350, imageDictHandle})
+	}
+
+	if len(groups) > 0 {
+		codes = append(codes, w.groupObjects(groups, rootHandle, groupDictHandle)...)
+	}
+	if len(w.imageDefs) > 0 {
+		codes = append(codes, w.imageObjects(rootHandle, imageDictHandle)...)
+	}
+
+	for _, gc := range codes {
+		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
 			return err
 		}
 	}
@@ -435,13 +1185,128 @@ func (w *Writer) writeEntities(doc *Document) error {
 	return w.writeEndSection()
 }
 
-func (w *Writer) writeEntity(entity Entity) error {
-	for _, gc := range entity.GroupCodes() {
-		if err := w.writeGroupCode(gc.Code, gc.Value); err != nil {
-			return err
+// writtenGroup is a group with the handles of its written members.
+type writtenGroup struct {
+	Group
+	handles []string
+}
+
+// writtenGroups returns the groups of doc that have written members.
+func (w *Writer) writtenGroups(doc *Document) []writtenGroup {
+	var groups []writtenGroup
+	for _, g := range doc.Groups {
+		var handles []string
+		for _, e := range g.Entities {
+			if e == nil || !w.isGroupMember(e) {
+				continue
+			}
+			for _, handle := range w.groupHandles[e] {
+				if !slices.Contains(handles, handle) {
+					handles = append(handles, handle)
+				}
+			}
+		}
+		if len(handles) > 0 {
+			groups = append(groups, writtenGroup{g, handles})
 		}
 	}
-	return nil
+	return groups
+}
+
+// groupObjects returns the ACAD_GROUP dictionary and its GROUP objects.
+func (w *Writer) groupObjects(groups []writtenGroup, rootHandle, groupDictHandle string) []GroupCode {
+	groupHandles := make([]string, len(groups))
+	for i := range groups {
+		groupHandles[i] = w.getHandle()
+	}
+
+	codes := []GroupCode{
+		{0, "DICTIONARY"},
+		{5, groupDictHandle},
+		{330, rootHandle},
+		{100, "AcDbDictionary"},
+		{281, 1},
+	}
+	for i, g := range groups {
+		codes = append(codes, GroupCode{3, EscapeUnicode(g.Name)}, GroupCode{350, groupHandles[i]})
+	}
+	for i, g := range groups {
+		codes = append(codes,
+			GroupCode{0, "GROUP"},
+			GroupCode{5, groupHandles[i]},
+			GroupCode{330, groupDictHandle},
+			GroupCode{100, "AcDbGroup"},
+			GroupCode{300, EscapeUnicode(g.Description)},
+			GroupCode{70, 0}, // named
+			GroupCode{71, 1}, // selectable
+		)
+		for _, handle := range g.handles {
+			codes = append(codes, GroupCode{340, handle})
+		}
+	}
+	return codes
+}
+
+// imageObjects returns the ACAD_IMAGE_DICT dictionary, the IMAGEDEF
+// objects, and the IMAGEDEF_REACTOR objects.
+func (w *Writer) imageObjects(rootHandle, imageDictHandle string) []GroupCode {
+	// Image dictionary with one entry per image definition
+	codes := []GroupCode{
+		{0, "DICTIONARY"},
+		{5, imageDictHandle},
+		{330, rootHandle},
+		{100, "AcDbDictionary"},
+		{281, 1},
+	}
+	for i, def := range w.imageDefs {
+		codes = append(codes,
+			GroupCode{3, imageDictName(def.path, i)},
+			GroupCode{350, def.handle})
+	}
+
+	for _, def := range w.imageDefs {
+		codes = append(codes,
+			GroupCode{0, "IMAGEDEF"},
+			GroupCode{5, def.handle},
+			GroupCode{330, imageDictHandle},
+			GroupCode{100, "AcDbRasterImageDef"},
+			GroupCode{90, 0},
+			GroupCode{1, def.path},
+			GroupCode{10, float64(def.pixelWidth)},
+			GroupCode{20, float64(def.pixelHeight)},
+			GroupCode{11, 1.0},
+			GroupCode{21, 1.0},
+			GroupCode{280, 1},
+			GroupCode{281, 0},
+		)
+	}
+	for _, ref := range w.images {
+		codes = append(codes,
+			GroupCode{0, "IMAGEDEF_REACTOR"},
+			GroupCode{5, ref.reactor},
+			GroupCode{330, ref.handle},
+			GroupCode{100, "AcDbRasterImageDefReactor"},
+			GroupCode{90, 2},
+			GroupCode{330, ref.handle},
+		)
+	}
+	return codes
+}
+
+// imageDictName derives an ACAD_IMAGE_DICT entry name from an image path.
+// The index keeps names unique when different paths share a base name.
+func imageDictName(path string, index int) string {
+	name := path
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		name = "IMAGE"
+	}
+	return fmt.Sprintf("%s_%d", EscapeUnicode(name), index+1)
 }
 
 func (w *Writer) writeSection(name string) error {
@@ -467,13 +1332,21 @@ func (w *Writer) writeGroupCode(code int, value interface{}) error {
 	var line string
 	switch v := value.(type) {
 	case string:
+		// A line break would end the value early and corrupt the file
+		v = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(v)
 		line = fmt.Sprintf("%3d\n%s\n", code, v)
 	case int:
 		line = fmt.Sprintf("%3d\n%d\n", code, v)
 	case float64:
-		line = fmt.Sprintf("%3d\n%f\n", code, v)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			if w.strict {
+				return fmt.Errorf("%w: group code %d is %v", ErrNonFinite, code, v)
+			}
+			v = 0
+		}
+		line = fmt.Sprintf("%3d\n%s\n", code, strconv.FormatFloat(v, 'f', w.precision, 64))
 	default:
-		line = fmt.Sprintf("%3d\n%v\n", code, v)
+		return fmt.Errorf("%w: group code %d is %T", ErrUnsupportedValue, code, v)
 	}
 	_, err := io.WriteString(w.w, line)
 	return err
@@ -488,8 +1361,45 @@ func (w *Writer) writeGroupCode(code int, value interface{}) error {
 //	dxfContent := dxf.ToString(doc)
 //	os.WriteFile("output.dxf", []byte(dxfContent), 0644)
 func ToString(doc *Document) string {
+	return ToStringWithOptions(doc, WriteOptions{})
+}
+
+// ToStringWithOptions serializes a DXF Document to a string like ToString,
+// applying the behavior selected in opts.
+//
+// Example:
+//
+//	dxfContent := dxf.ToStringWithOptions(doc, dxf.WriteOptions{Version: dxf.R12})
+func ToStringWithOptions(doc *Document, opts WriteOptions) string {
 	var sb strings.Builder
-	w := NewWriter(&sb)
+	w := NewWriterWithOptions(&sb, opts)
 	_ = w.WriteDocument(doc)
 	return sb.String()
 }
+
+// WriteGzip writes doc as a gzip-compressed DXF file to w, streaming the
+// output through the compressor instead of building the whole file first.
+// Gunzipping the result yields the same content as ToString.
+//
+// Example:
+//
+//	f, err := os.Create("output.dxf.gz")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	return dxf.WriteGzip(f, doc)
+func WriteGzip(w io.Writer, doc *Document) error {
+	return WriteGzipWithOptions(w, doc, WriteOptions{})
+}
+
+// WriteGzipWithOptions writes doc as a gzip-compressed DXF file like
+// WriteGzip, applying the behavior selected in opts.
+func WriteGzipWithOptions(w io.Writer, doc *Document, opts WriteOptions) error {
+	zw := gzip.NewWriter(w)
+	if err := NewWriterWithOptions(zw, opts).WriteDocument(doc); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,523 @@
+package dxf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteDocument_ImageObjects(t *testing.T) {
+	doc := NewDocument().AddEntity(&Image{
+		Layer:       "0",
+		X:           10,
+		Y:           20,
+		Width:       100,
+		Height:      50,
+		FilePath:    `C:\images\photo.bmp`,
+		PixelWidth:  640,
+		PixelHeight: 320,
+	})
+
+	output := ToString(doc)
+
+	for _, want := range []string{
+		"IMAGE\n",
+		"OBJECTS",
+		"ACAD_IMAGE_DICT",
+		"IMAGEDEF\n",
+		"IMAGEDEF_REACTOR",
+		"photo_1",
+		`C:\images\photo.bmp`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+
+	objects := strings.Index(output, "OBJECTS")
+	entities := strings.Index(output, "ENTITIES")
+	if objects < entities {
+		t.Errorf("Expected OBJECTS section after ENTITIES section")
+	}
+
+	// The image classes are declared between HEADER and TABLES
+	classes := strings.Index(output, "  2\nCLASSES\n")
+	if classes < strings.Index(output, "HEADER") || classes > strings.Index(output, "TABLES") {
+		t.Fatalf("Expected a CLASSES section between HEADER and TABLES")
+	}
+	for _, want := range []string{
+		"  0\nCLASS\n  1\nIMAGE\n  2\nAcDbRasterImage\n",
+		"  0\nCLASS\n  1\nIMAGEDEF\n  2\nAcDbRasterImageDef\n",
+		"  0\nCLASS\n  1\nIMAGEDEF_REACTOR\n  2\nAcDbRasterImageDefReactor\n",
+	} {
+		if !strings.Contains(output[classes:], want) {
+			t.Errorf("Expected CLASSES to contain %q", want)
+		}
+	}
+
+	if strings.Contains(ToStringWithOptions(doc, WriteOptions{Version: R12}), "CLASSES") {
+		t.Errorf("Expected no CLASSES section in R12 output, which has no images")
+	}
+}
+
+func TestWriteDocument_NoImageObjects(t *testing.T) {
+	doc := NewDocument().AddLine(0, 0, 100, 100)
+
+	output := ToString(doc)
+
+	if strings.Contains(output, "OBJECTS") {
+		t.Errorf("Expected no OBJECTS section without images")
+	}
+	if strings.Contains(output, "CLASSES") {
+		t.Errorf("Expected no CLASSES section without images")
+	}
+}
+
+func TestWriteOptions_VersionR12(t *testing.T) {
+	doc := NewDocument().AddEntity(&Ellipse{
+		MajorAxisX: 100,
+		MinorRatio: 0.5,
+		EndParam:   2 * math.Pi,
+	}).AddEntity(&LWPolyline{
+		Vertices: []Vertex{{0, 0}, {10, 0}, {10, 10}},
+	}).AddEntity(&Spline{
+		ControlPoints: []Vertex{{0, 0}, {10, 20}, {30, 20}, {40, 0}},
+	})
+
+	output := ToStringWithOptions(doc, WriteOptions{Version: R12})
+
+	if !strings.Contains(output, "AC1009") {
+		t.Errorf("Expected $ACADVER AC1009")
+	}
+	if strings.Contains(output, "ELLIPSE") {
+		t.Errorf("Expected no ELLIPSE entity in R12 output")
+	}
+	if strings.Contains(output, "LWPOLYLINE") {
+		t.Errorf("Expected no LWPOLYLINE entity in R12 output")
+	}
+	if strings.Contains(output, "SPLINE") {
+		t.Errorf("Expected no SPLINE entity in R12 output")
+	}
+	if got := strings.Count(output, "\nPOLYLINE\n"); got != 3 {
+		t.Errorf("Expected 3 POLYLINE entities, got %d", got)
+	}
+	if got := strings.Count(output, "\nSEQEND\n"); got != 3 {
+		t.Errorf("Expected 3 SEQEND markers, got %d", got)
+	}
+}
+
+func TestWriteOptions_VersionR2018(t *testing.T) {
+	doc := NewDocument().AddEntity(&Ellipse{
+		MajorAxisX: 100,
+		MinorRatio: 0.5,
+		EndParam:   2 * math.Pi,
+	})
+
+	output := ToStringWithOptions(doc, WriteOptions{Version: R2018})
+
+	if !strings.Contains(output, "AC1032") {
+		t.Errorf("Expected $ACADVER AC1032")
+	}
+	if !strings.Contains(output, "ELLIPSE") {
+		t.Errorf("Expected ELLIPSE entity in R2018 output")
+	}
+	if strings.Contains(output, "POLYLINE") {
+		t.Errorf("Expected no POLYLINE approximation in R2018 output")
+	}
+}
+
+func TestWriteOptions_DefaultVersion(t *testing.T) {
+	output := ToString(NewDocument())
+
+	if !strings.Contains(output, "AC1015") {
+		t.Errorf("Expected default $ACADVER AC1015")
+	}
+}
+
+func TestWriteDocument_DimStyle(t *testing.T) {
+	doc := NewDocument()
+
+	output := ToString(doc)
+	if !strings.Contains(output, "$DIMSCALE\n 40\n1.000000\n") {
+		t.Errorf("Expected default $DIMSCALE 1.0")
+	}
+	want := "  2\nSTANDARD\n 70\n0\n 40\n1.000000\n 41\n2.500000\n 42\n0.625000\n 44\n1.250000\n140\n2.500000\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected a default STANDARD DIMSTYLE entry")
+	}
+
+	doc.DimScale = 50
+	doc.DimStyle = DimStyle{ArrowSize: 3, TextHeight: 3.5}
+	output = ToStringWithOptions(doc, WriteOptions{Version: R12})
+	if !strings.Contains(output, "$DIMSCALE\n 40\n50.000000\n") {
+		t.Errorf("Expected $DIMSCALE 50")
+	}
+	want = "  2\nSTANDARD\n 70\n0\n 40\n50.000000\n 41\n3.000000\n 42\n0.625000\n 44\n1.250000\n140\n3.500000\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected the STANDARD DIMSTYLE entry to use DimStyle and DimScale")
+	}
+}
+
+func TestWriteDocument_Dimension(t *testing.T) {
+	line := &Line{Layer: "0", X2: 10}
+	doc := NewDocument().
+		AddEntity(&Dimension{X2: 10, LineX: 10, Entities: []Entity{line}}).
+		AddEntity(&Dimension{Style: "STANDARD", Y2: 5, LineY: 5})
+
+	for _, version := range []Version{R2000, R12} {
+		output := ToStringWithOptions(doc, WriteOptions{Version: version})
+		for _, name := range []string{"*D1", "*D2"} {
+			if !strings.Contains(output, "  0\nBLOCK\n  8\n0\n  2\n"+name+"\n 70\n1\n") {
+				t.Errorf("%s: expected anonymous block %s", version, name)
+			}
+			if !strings.Contains(output, "  2\n"+name+"\n  3\nSTANDARD\n") {
+				t.Errorf("%s: expected a DIMENSION referring to %s and STANDARD", version, name)
+			}
+		}
+		block := output[strings.Index(output, "  2\n*D1\n"):strings.Index(output, "ENDBLK")]
+		if !strings.Contains(block, "  0\nLINE\n") {
+			t.Errorf("%s: expected the dimension line in block *D1", version)
+		}
+	}
+}
+
+func TestWriteDocument_LineTypeScale(t *testing.T) {
+	doc := NewDocument()
+
+	if !strings.Contains(ToString(doc), "$LTSCALE\n 40\n1.000000\n") {
+		t.Errorf("Expected default $LTSCALE 1.0")
+	}
+
+	doc.LineTypeScale = 2.5
+	doc.Entities = append(doc.Entities, &Line{Layer: "0", LineType: "DASHED", LineTypeScale: 10, X2: 1})
+	output := ToString(doc)
+	if !strings.Contains(output, "$LTSCALE\n 40\n2.500000\n") {
+		t.Errorf("Expected $LTSCALE 2.5")
+	}
+	if !strings.Contains(output, " 48\n10.000000\n") {
+		t.Errorf("Expected entity linetype scale 48")
+	}
+
+	r12 := ToStringWithOptions(doc, WriteOptions{Version: R12})
+	if strings.Contains(r12, " 48\n") {
+		t.Errorf("Expected no group code 48 in R12 output")
+	}
+}
+
+func TestWriteDocument_LinetypeTableCoversMapLineType(t *testing.T) {
+	output := ToString(NewDocument())
+
+	start := strings.Index(output, "  2\nLTYPE\n")
+	if start < 0 {
+		t.Fatal("Expected LTYPE table")
+	}
+	table := output[start:]
+	table = table[:strings.Index(table, "ENDTAB")]
+
+	for penStyle := 0; penStyle <= 255; penStyle++ {
+		name := mapLineType(byte(penStyle))
+		if !strings.Contains(table, "  2\n"+name+"\n") {
+			t.Errorf("pen style %d: no LTYPE entry for %s", penStyle, name)
+		}
+	}
+}
+
+func TestWriteDocument_NonFinite(t *testing.T) {
+	doc := NewDocument()
+	doc.Entities = append(doc.Entities,
+		&Line{Layer: "0", X1: math.NaN(), Y1: 1, X2: math.Inf(1), Y2: math.Inf(-1)})
+
+	output := ToString(doc)
+	if strings.Contains(output, "NaN") || strings.Contains(output, "Inf") {
+		t.Errorf("Expected no NaN/Inf tokens in output")
+	}
+	if !strings.Contains(output, " 10\n0.000000\n 20\n1.000000\n") {
+		t.Errorf("Expected NaN start X written as 0")
+	}
+
+	var sb strings.Builder
+	err := NewWriterWithOptions(&sb, WriteOptions{RejectNonFinite: true}).WriteDocument(doc)
+	if !errors.Is(err, ErrNonFinite) {
+		t.Errorf("Expected ErrNonFinite, got %v", err)
+	}
+}
+
+func TestWriteOptions_Precision(t *testing.T) {
+	doc := NewDocument()
+	doc.Entities = append(doc.Entities, &Point{Layer: "0", X: 123456.123456789, Y: 2})
+
+	tests := []struct {
+		opts WriteOptions
+		x, y string
+	}{
+		{WriteOptions{}, "123456.123457", "2.000000"},
+		{WriteOptions{Precision: 3}, "123456.123", "2.000"},
+		{WriteOptions{Precision: 9}, "123456.123456789", "2.000000000"},
+		{WriteOptions{Precision: 3, ShortestFloats: true}, "123456.123456789", "2"},
+	}
+
+	for _, tt := range tests {
+		output := ToStringWithOptions(doc, tt.opts)
+		want := " 10\n" + tt.x + "\n 20\n" + tt.y + "\n"
+		if !strings.Contains(output, want) {
+			t.Errorf("%+v: expected point written as %q", tt.opts, want)
+		}
+	}
+}
+
+func TestWriteDocument_PaperSpaceR12Polyline(t *testing.T) {
+	doc := NewDocument()
+	doc.PaperSpaceEntities = []Entity{
+		&LWPolyline{Layer: "0", Vertices: []Vertex{{0, 0}, {10, 0}}},
+	}
+
+	output := ToStringWithOptions(doc, WriteOptions{Version: R12})
+
+	// POLYLINE, two VERTEX entities and SEQEND all belong to paper space
+	if got := strings.Count(output, " 67\n1\n"); got != 4 {
+		t.Errorf("expected 4 entities with 67=1, got %d", got)
+	}
+}
+
+func TestWriteDocument_MultiLineText(t *testing.T) {
+	doc := NewDocument().AddText(10, 50, "first line\nsecond line", WithTextHeight(3))
+
+	output := ToString(doc)
+
+	// Every value must stay on the line after its group code
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines)%2 != 0 {
+		t.Fatalf("Expected group code/value pairs, got %d lines", len(lines))
+	}
+	for i := 0; i < len(lines); i += 2 {
+		if _, err := strconv.Atoi(strings.TrimSpace(lines[i])); err != nil {
+			t.Fatalf("Line %d is not a group code: %q", i+1, lines[i])
+		}
+	}
+
+	if got := strings.Count(output, "\nTEXT\n"); got != 2 {
+		t.Errorf("Expected 2 TEXT entities, got %d", got)
+	}
+	if !strings.Contains(output, "  1\nfirst line\n") || !strings.Contains(output, "  1\nsecond line\n") {
+		t.Errorf("Expected each line as its own TEXT value")
+	}
+	// Second line sits one line spacing below the first
+	if !strings.Contains(output, " 20\n45.000000\n") {
+		t.Errorf("Expected second line at Y=45")
+	}
+}
+
+// customEntity is an entity implemented outside the package's own types.
+type customEntity struct {
+	codes []GroupCode
+}
+
+func (c *customEntity) EntityType() string      { return "CUSTOM" }
+func (c *customEntity) GroupCodes() []GroupCode { return c.codes }
+
+func TestWriteDocument_GroupCodeConstructors(t *testing.T) {
+	doc := NewDocument()
+	doc.Entities = append(doc.Entities, &customEntity{[]GroupCode{
+		Str(0, "CUSTOM"),
+		Int(62, 3),
+		Float(40, 2.5),
+	}})
+
+	output := ToString(doc)
+	want := "  0\nCUSTOM\n 62\n3\n 40\n2.500000\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output", want)
+	}
+}
+
+func TestWriteDocument_UnsupportedValue(t *testing.T) {
+	for _, value := range []interface{}{int64(1), float32(1.5), true, nil} {
+		doc := NewDocument()
+		doc.Entities = append(doc.Entities, &customEntity{[]GroupCode{
+			Str(0, "CUSTOM"),
+			{40, value},
+		}})
+
+		var sb strings.Builder
+		err := NewWriter(&sb).WriteDocument(doc)
+		if !errors.Is(err, ErrUnsupportedValue) {
+			t.Errorf("%T: expected ErrUnsupportedValue, got %v", value, err)
+		}
+	}
+}
+
+func TestWriteDocument_Extents(t *testing.T) {
+	doc := NewDocument().
+		AddBlock(Block{Name: "FAR", BaseX: 1000, BaseY: 1000, Entities: []Entity{
+			&Line{Layer: "0", X1: 1000, Y1: 1000, X2: 1010, Y2: 1020},
+		}}).
+		AddInsert("FAR", 5, 5)
+
+	output := ToString(doc)
+
+	for _, want := range []string{
+		"  9\n$EXTMIN\n 10\n5.000000\n 20\n5.000000\n",
+		"  9\n$EXTMAX\n 10\n15.000000\n 20\n25.000000\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+
+	if empty := ToString(NewDocument()); strings.Contains(empty, "$EXTMIN") {
+		t.Error("Expected no $EXTMIN for an empty document")
+	}
+}
+
+func TestWriteDocument_XData(t *testing.T) {
+	line := NewLine(0, 0, 10, 0)
+	line.XData = XData{"MYAPP": {{1000, "wall"}, {1070, 3}}}
+	insert := &Insert{Layer: "0", BlockName: "B", ScaleX: 1, ScaleY: 1,
+		Attributes: []Attribute{{Tag: "T", Value: "v", Height: 1}}}
+	insert.XData = XData{"MYAPP": {{1070, 4}}}
+	doc := NewDocument().AddEntity(line).AddEntity(insert)
+
+	output := ToString(doc)
+
+	for _, want := range []string{
+		"  2\nAPPID\n",
+		"  2\nMYAPP\n",
+		"1001\nMYAPP\n1000\nwall\n1070\n3\n  0\nINSERT\n",
+		"1001\nMYAPP\n1070\n4\n  0\nATTRIB\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+
+	if plain := ToString(NewDocument().AddLine(0, 0, 1, 1)); strings.Contains(plain, "APPID") {
+		t.Error("Expected no APPID table without xdata")
+	}
+}
+
+func TestWriteDocument_GroupedMultiLineText(t *testing.T) {
+	text := NewText(0, 0, "1F\n2F")
+	text.XData = XData{"MYAPP": {{1070, 7}}}
+	doc := NewDocument().AddEntity(text)
+	doc.Groups = []Group{{Name: "LABELS", Entities: []Entity{text}}}
+
+	// Collect the handle and XData of each TEXT and the GROUP members
+	lines := strings.Split(ToString(doc), "\n")
+	var handles, members []string
+	xdata := 0
+	entity := ""
+	for i := 0; i+1 < len(lines); i += 2 {
+		code, value := strings.TrimSpace(lines[i]), lines[i+1]
+		switch {
+		case code == "0":
+			entity = value
+		case entity == "TEXT" && code == "5":
+			handles = append(handles, value)
+		case entity == "TEXT" && code == "1001" && value == "MYAPP":
+			xdata++
+		case entity == "GROUP" && code == "340":
+			members = append(members, value)
+		}
+	}
+
+	if len(handles) != 2 || handles[0] == handles[1] {
+		t.Fatalf("expected a distinct handle on both TEXT entities, got %v", handles)
+	}
+	if xdata != 2 {
+		t.Errorf("expected XData on both TEXT entities, got %d", xdata)
+	}
+	if !slices.Equal(members, handles) {
+		t.Errorf("GROUP members: got %v, want %v", members, handles)
+	}
+}
+
+func TestWriteDocument_SplineRoundTrip(t *testing.T) {
+	spline := &Spline{
+		Layer:         "CURVES",
+		Degree:        2,
+		ControlPoints: []Vertex{{0, 0}, {10, 20}, {30, 20}, {40, 0}},
+	}
+	output := ToString(NewDocument().AddEntity(spline))
+
+	// Read the SPLINE entity back from its group codes
+	start := strings.Index(output, "  0\nSPLINE\n")
+	if start < 0 {
+		t.Fatal("Expected a SPLINE entity")
+	}
+	lines := strings.Split(output[start:], "\n")
+	read := &Spline{}
+	var knots []float64
+	for i := 2; i+1 < len(lines); i += 2 {
+		code, value := strings.TrimSpace(lines[i]), lines[i+1]
+		if code == "0" {
+			break
+		}
+		f, _ := strconv.ParseFloat(value, 64)
+		switch code {
+		case "8":
+			read.Layer = value
+		case "71":
+			read.Degree = int(f)
+		case "40":
+			knots = append(knots, f)
+		case "10":
+			read.ControlPoints = append(read.ControlPoints, Vertex{X: f})
+		case "20":
+			read.ControlPoints[len(read.ControlPoints)-1].Y = f
+		}
+	}
+	read.Knots = knots
+
+	if read.Layer != spline.Layer || read.Degree != spline.Degree {
+		t.Errorf("got layer %q degree %d, want %q and %d", read.Layer, read.Degree, spline.Layer, spline.Degree)
+	}
+	if !slices.Equal(read.ControlPoints, spline.ControlPoints) {
+		t.Errorf("control points: got %v, want %v", read.ControlPoints, spline.ControlPoints)
+	}
+	if !slices.Equal(knots, []float64{0, 0, 0, 0.5, 1, 1, 1}) {
+		t.Errorf("knots: got %v, want a clamped uniform vector", knots)
+	}
+	// The written knots describe the same curve
+	if !slices.Equal(read.Sample(8), spline.Sample(8)) {
+		t.Errorf("curve read back differs: got %v, want %v", read.Sample(8), spline.Sample(8))
+	}
+}
+
+func TestWriteGzip(t *testing.T) {
+	doc := NewDocument().AddEntity(NewLine(0, 0, 10, 5)).AddEntity(NewText(1, 2, "平面図"))
+
+	var buf bytes.Buffer
+	if err := WriteGzip(&buf, doc); err != nil {
+		t.Fatalf("WriteGzip failed: %v", err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip.NewReader failed: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gunzip failed: %v", err)
+	}
+	if got, want := string(data), ToString(doc); got != want {
+		t.Errorf("gunzipped output differs from ToString:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	opts := WriteOptions{Version: R12}
+	if err := WriteGzipWithOptions(&buf, doc, opts); err != nil {
+		t.Fatalf("WriteGzipWithOptions failed: %v", err)
+	}
+	zr, err = gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip.NewReader failed: %v", err)
+	}
+	if data, _ := io.ReadAll(zr); string(data) != ToStringWithOptions(doc, opts) {
+		t.Error("gunzipped R12 output differs from ToStringWithOptions")
+	}
+}
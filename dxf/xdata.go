@@ -0,0 +1,142 @@
+package dxf
+
+import (
+	"maps"
+	"slices"
+	"sort"
+)
+
+// XData is extended entity data keyed by application name. The writer
+// emits each application's group codes (1000-1071) after the entity body,
+// preceded by a 1001 code with the application name, in name order, and
+// registers the names in the APPID table.
+//
+// Example:
+//
+//	line := dxf.NewLine(0, 0, 10, 0)
+//	line.XData = dxf.XData{"MYAPP": {{1000, "wall"}, {1070, 3}}}
+type XData map[string][]GroupCode
+
+// groupCodes returns the XData as group codes, one 1001 code per application.
+func (x XData) groupCodes() []GroupCode {
+	var codes []GroupCode
+	for _, app := range slices.Sorted(maps.Keys(x)) {
+		codes = append(codes, GroupCode{1001, app})
+		codes = append(codes, x[app]...)
+	}
+	return codes
+}
+
+// clone returns a deep copy of x.
+func (x XData) clone() XData {
+	if x == nil {
+		return nil
+	}
+	c := make(XData, len(x))
+	for app, codes := range x {
+		c[app] = slices.Clone(codes)
+	}
+	return c
+}
+
+// xdataOf returns a pointer to the XData of a known entity type, or nil.
+func xdataOf(entity Entity) *XData {
+	switch e := entity.(type) {
+	case *Line:
+		return &e.XData
+	case *Circle:
+		return &e.XData
+	case *Arc:
+		return &e.XData
+	case *Ellipse:
+		return &e.XData
+	case *Point:
+		return &e.XData
+	case *Text:
+		return &e.XData
+	case *Solid:
+		return &e.XData
+	case *Insert:
+		return &e.XData
+	case *LWPolyline:
+		return &e.XData
+	case *Spline:
+		return &e.XData
+	case *Image:
+		return &e.XData
+	case *Hatch:
+		return &e.XData
+	case *Dimension:
+		return &e.XData
+	}
+	return nil
+}
+
+// setXData sets the XData of application app on a known entity type.
+// It reports false for other entity types.
+func setXData(entity Entity, app string, codes []GroupCode) bool {
+	x := xdataOf(entity)
+	if x == nil {
+		return false
+	}
+	if *x == nil {
+		*x = make(XData)
+	}
+	(*x)[app] = codes
+	return true
+}
+
+// withXData inserts the XData of entity after the codes of the first entity
+// in codes, before any subentities such as ATTRIB or VERTEX.
+func withXData(codes []GroupCode, entity Entity) []GroupCode {
+	x := xdataOf(entity)
+	if x == nil || len(*x) == 0 || len(codes) == 0 {
+		return codes
+	}
+	end := len(codes)
+	for i := 1; i < len(codes); i++ {
+		if codes[i].Code == 0 {
+			end = i
+			break
+		}
+	}
+	xdata := x.groupCodes()
+	merged := make([]GroupCode, 0, len(codes)+len(xdata))
+	merged = append(merged, codes[:end]...)
+	merged = append(merged, xdata...)
+	return append(merged, codes[end:]...)
+}
+
+// xdataApps returns the XData application names used by the document's
+// entities, sorted, with the always registered "ACAD" first. It returns
+// nil if no entity has XData.
+func xdataApps(doc *Document) []string {
+	seen := map[string]bool{"ACAD": true}
+	var apps []string
+	found := false
+	add := func(entities []Entity) {
+		for _, e := range entities {
+			x := xdataOf(e)
+			if x == nil {
+				continue
+			}
+			for app := range *x {
+				found = true
+				if !seen[app] {
+					seen[app] = true
+					apps = append(apps, app)
+				}
+			}
+		}
+	}
+	add(doc.Entities)
+	add(doc.PaperSpaceEntities)
+	for i := range doc.Blocks {
+		add(doc.Blocks[i].Entities)
+	}
+	if !found {
+		return nil
+	}
+	sort.Strings(apps)
+	return append([]string{"ACAD"}, apps...)
+}
@@ -0,0 +1,109 @@
+package jww
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// MFC CArchive object tags
+const (
+	newClassTag = 0xFFFF // a class definition (schema + name) follows
+	classTag    = 0x8000 // set on class references; alone it marks a null object
+)
+
+// classRegistry tracks the class definitions of objects written with MFC
+// CArchive PID tracking:
+//   - Each new class definition gets a PID
+//   - Each object also gets a PID, before its contents are read
+//   - PIDs are assigned sequentially starting from 1
+//   - Class references use 0x8000 | class_PID
+//
+// PIDs belong to the whole archive, so the main entity list, the block
+// definition list, and the entity lists nested in block definitions share
+// one registry: a class defined in one list can be referenced in a later one.
+type classRegistry struct {
+	names   map[uint32]string // PID -> class name
+	nextPID uint32
+	opts    ParseOptions
+}
+
+// newClassRegistry returns an empty registry. Schemas of new class
+// definitions are recorded through opts.
+func newClassRegistry(opts ParseOptions) *classRegistry {
+	return &classRegistry{
+		names:   make(map[uint32]string),
+		nextPID: 1,
+		opts:    opts,
+	}
+}
+
+// ReadClassRef reads the class tag that precedes an object and returns the
+// object's class name. isNew reports that the tag was a new class
+// definition, which is registered under the next PID. For a null object
+// className is empty and err is nil. The caller reads the object data and
+// then calls objectRead.
+func (c *classRegistry) ReadClassRef(jr *Reader) (className string, isNew bool, err error) {
+	tag, err := jr.ReadWORD()
+	if err != nil {
+		return "", false, err
+	}
+
+	switch {
+	case tag == newClassTag:
+		// Validate the name length before consuming the definition
+		header, err := jr.Peek(4)
+		if err != nil {
+			return "", false, fmt.Errorf("reading class definition header: %w", err)
+		}
+		if nameLen := binary.LittleEndian.Uint16(header[2:]); nameLen == 0 || nameLen > maxClassNameLen {
+			return "", false, fmt.Errorf("invalid class name length: %d", nameLen)
+		}
+
+		schema, _ := jr.ReadWORD()
+		nameLen, _ := jr.ReadWORD()
+		nameBuf := make([]byte, nameLen)
+		if err := jr.ReadBytes(nameBuf); err != nil {
+			return "", false, fmt.Errorf("reading class name: %w", err)
+		}
+		className = string(nameBuf)
+		c.opts.recordSchema(className, schema)
+
+		c.names[c.nextPID] = className
+		c.nextPID++
+		c.opts.classRead(className)
+		return className, true, nil
+
+	case tag == classTag:
+		return "", false, nil
+
+	case tag&classTag != 0:
+		pid := uint32(tag &^ classTag)
+		className, ok := c.names[pid]
+		if !ok {
+			return "", false, fmt.Errorf("unknown class PID: %d (have PIDs: %v)", pid, c.pids())
+		}
+		c.opts.classRead(className)
+		return className, false, nil
+
+	default:
+		return "", false, fmt.Errorf("unsupported object reference tag: %#04x", tag)
+	}
+}
+
+// objectRead assigns a PID to the object just read and returns it.
+func (c *classRegistry) objectRead() uint32 {
+	pid := c.nextPID
+	c.nextPID++
+	return pid
+}
+
+// pids returns the registered class PIDs in ascending order, for error messages.
+func (c *classRegistry) pids() []uint32 {
+	pids := make([]uint32, 0, len(c.names))
+	for pid := range c.names {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids
+}
@@ -0,0 +1,165 @@
+package jww
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// classTags encodes a sequence of class tags: strings become new class
+// definitions (schema 700), integers are written as raw tags.
+func classTags(tags ...interface{}) []byte {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	for _, tag := range tags {
+		switch v := tag.(type) {
+		case string:
+			_ = binary.Write(&buf, le, uint16(0xFFFF))
+			_ = binary.Write(&buf, le, uint16(700))
+			_ = binary.Write(&buf, le, uint16(len(v)))
+			buf.WriteString(v)
+		case int:
+			_ = binary.Write(&buf, le, uint16(v))
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestClassRegistry_ReadClassRef(t *testing.T) {
+	schemas := make(map[string]uint16)
+	classes := newClassRegistry(ParseOptions{classSchemas: schemas})
+	jr := NewReader(bytes.NewReader(classTags("CDataSen", 0x8001, 0x8000, "CDataEnko", 0x8004)))
+
+	tests := []struct {
+		className string
+		isNew     bool
+	}{
+		{"CDataSen", true},   // fresh definition, PID 1
+		{"CDataSen", false},  // reference to PID 1
+		{"", false},          // null object
+		{"CDataEnko", true},  // PID 4 after objects 2 and 3; null objects take none
+		{"CDataEnko", false}, // reference to PID 4
+	}
+	for i, tt := range tests {
+		className, isNew, err := classes.ReadClassRef(jr)
+		if err != nil {
+			t.Fatalf("tag %d: %v", i, err)
+		}
+		if className != tt.className || isNew != tt.isNew {
+			t.Errorf("tag %d: got (%q, %v), want (%q, %v)", i, className, isNew, tt.className, tt.isNew)
+		}
+		if className != "" {
+			classes.objectRead()
+		}
+	}
+
+	if schemas["CDataSen"] != 700 || schemas["CDataEnko"] != 700 {
+		t.Errorf("schemas not recorded: %v", schemas)
+	}
+}
+
+func TestClassRegistry_ReadClassRefErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"unknown PID", classTags(0x8002), "unknown class PID: 2"},
+		{"object reference", classTags(0x0001), "unsupported object reference tag"},
+		{"empty class name", classTags(""), "invalid class name length: 0"},
+		{"truncated", classTags(), "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := newClassRegistry(ParseOptions{}).ReadClassRef(NewReader(bytes.NewReader(tt.data)))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBlockDefList_ClassReference(t *testing.T) {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	_ = binary.Write(&buf, le, uint32(3)) // block definition count
+
+	writeBlockDef := func(number uint32) {
+		buf.Write(make([]byte, 4+1+2+2+2+2+2)) // entity base (Ver.3.51+)
+		_ = binary.Write(&buf, le, number)
+		buf.Write(make([]byte, 4+4)) // IsReferenced, CTime
+		buf.WriteByte(1)
+		buf.WriteString("B")
+		_ = binary.Write(&buf, le, uint16(0)) // nested entity count
+	}
+
+	buf.Write(classTags("CDataList")) // PID 1, object PID 2
+	writeBlockDef(1)
+	_ = binary.Write(&buf, le, uint16(0x8000)) // null object
+	_ = binary.Write(&buf, le, uint16(0x8001)) // reference to CDataList
+	writeBlockDef(2)
+
+	blockDefs, err := parseBlockDefList(NewReader(bytes.NewReader(buf.Bytes())), 700, newClassRegistry(ParseOptions{}), ParseOptions{})
+	if err != nil {
+		t.Fatalf("parseBlockDefList failed: %v", err)
+	}
+	if len(blockDefs) != 2 || blockDefs[0].Number != 1 || blockDefs[1].Number != 2 {
+		t.Errorf("got %+v", blockDefs)
+	}
+}
+
+// TestParse_ClassReferenceAcrossLists checks that block definitions resolve
+// class references to classes defined in the main entity list, as CArchive
+// PIDs are numbered across the whole file.
+func TestParse_ClassReferenceAcrossLists(t *testing.T) {
+	var main bytes.Buffer
+	doc := &Document{Version: 700, Entities: []Entity{&Line{EndX: 1}}} // CDataSen PID 1, line PID 2
+	if err := Write(&main, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data := main.Bytes()[:main.Len()-8] // drop the empty block def list and image count
+
+	var buf bytes.Buffer
+	jw := NewWriter(&buf)
+	_ = jw.WriteDWORD(2) // block definition count
+	for i, tag := range []interface{}{"CDataList", 0x8003} {
+		buf.Write(classTags(tag)) // CDataList PID 3, block definitions PIDs 4 and 6
+		_ = writeEntityBase(jw, &EntityBase{}, 700)
+		_ = jw.WriteDWORD(uint32(i + 1))         // number
+		_ = jw.WriteDWORD(0)                     // not referenced
+		_ = jw.WriteDWORD(0)                     // CTime
+		_ = jw.WriteCString("B")                 // name
+		_ = jw.WriteWORD(1)                      // nested entity count
+		_ = jw.WriteWORD(0x8001)                 // CDataSen, defined in the main list
+		_ = writeEntity(jw, &Line{EndX: 5}, 700) // PIDs 5 and 7
+	}
+	_ = jw.WriteDWORD(0) // image count
+
+	parsed, err := Parse(bytes.NewReader(append(data, buf.Bytes()...)))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(parsed.BlockDefs) != 2 {
+		t.Fatalf("got %d block definitions, want 2", len(parsed.BlockDefs))
+	}
+	for i, bd := range parsed.BlockDefs {
+		if len(bd.Entities) != 1 {
+			t.Fatalf("block definition %d: got %d entities, want 1", i, len(bd.Entities))
+		}
+		if line, ok := bd.Entities[0].(*Line); !ok || line.EndX != 5 {
+			t.Errorf("block definition %d: got %+v, want a line ending at x 5", i, bd.Entities[0])
+		}
+	}
+	// IDs continue from the main entity list
+	for i, want := range [][2]uint32{{4, 5}, {6, 7}} {
+		bd := parsed.BlockDefs[i]
+		if bd.ID != want[0] || bd.Entities[0].Base().ID != want[1] {
+			t.Errorf("block definition %d: got IDs %d and %d, want %d and %d",
+				i, bd.ID, bd.Entities[0].Base().ID, want[0], want[1])
+		}
+	}
+	if len(parsed.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", parsed.Warnings)
+	}
+}
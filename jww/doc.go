@@ -5,4 +5,7 @@
 // serialization as MFC's CArchive and converts Shift-JIS encoded strings to
 // UTF-8. Parsed documents can then be inspected directly or transformed into
 // DXF entities via the companion dxf package.
+//
+// Parse and its variants keep all state in the call and may be used from
+// multiple goroutines at once. A Reader is not safe for concurrent use.
 package jww
@@ -0,0 +1,223 @@
+package jww
+
+import "io"
+
+// FlatKind identifies the entity type stored in a FlatEntity.
+type FlatKind uint8
+
+// Flat entity kinds.
+const (
+	FlatOther FlatKind = iota // an entity kept in FlatEntities.Others
+	FlatLine
+	FlatArc
+	FlatPoint
+	FlatText
+	FlatSolid
+	FlatBlock
+)
+
+// FlatEntity is an entity stored by value as a tagged union: Kind selects
+// the entity type and how the remaining fields are used. It holds no
+// pointers, so a []FlatEntity of any length is a single allocation the
+// garbage collector does not scan.
+//
+// Coords holds the float fields in declaration order:
+//
+//	FlatLine:  StartX, StartY, EndX, EndY
+//	FlatArc:   CenterX, CenterY, Radius, StartAngle, ArcAngle, TiltAngle, Flatness
+//	FlatPoint: X, Y, Angle, Scale
+//	FlatText:  StartX, StartY, EndX, EndY, SizeX, SizeY, Spacing, Angle
+//	FlatSolid: Point1X, Point1Y, Point2X, Point2Y, Point3X, Point3Y, Point4X, Point4Y
+//	FlatBlock: RefX, RefY, ScaleX, ScaleY, Rotation
+//
+// The other fields are:
+//
+//	Code:  Point.Code, Text.TextType, Solid.Color, Block.DefNumber
+//	Bool:  Arc.IsFullCircle, Point.IsTemporary
+//	Index: FlatText: the FontName and Content indexes in FlatEntities.Strings;
+//	       FlatOther: the entity index in FlatEntities.Others
+type FlatEntity struct {
+	EntityBase
+	Kind   FlatKind
+	Bool   bool
+	Code   uint32
+	Index  [2]uint32
+	Coords [8]float64
+}
+
+// FlatEntities is an entity list in flat form. Entities of types without
+// a flat kind (dimensions) are kept as Entity values in Others.
+type FlatEntities struct {
+	// Items are the entities in file order.
+	Items []FlatEntity
+
+	// Strings holds the font names and contents of texts.
+	Strings []string
+
+	// Others holds the entities of kind FlatOther.
+	Others []Entity
+}
+
+// Len returns the number of entities.
+func (f *FlatEntities) Len() int { return len(f.Items) }
+
+// Entity returns entity i as an Entity equal to the one Parse returns.
+// Flat kinds are decoded into a newly allocated value; FlatOther returns
+// the stored entity.
+func (f *FlatEntities) Entity(i int) Entity {
+	e := &f.Items[i]
+	c := &e.Coords
+	switch e.Kind {
+	case FlatLine:
+		return &Line{
+			EntityBase: e.EntityBase,
+			StartX:     c[0],
+			StartY:     c[1],
+			EndX:       c[2],
+			EndY:       c[3],
+		}
+	case FlatArc:
+		return &Arc{
+			EntityBase:   e.EntityBase,
+			CenterX:      c[0],
+			CenterY:      c[1],
+			Radius:       c[2],
+			StartAngle:   c[3],
+			ArcAngle:     c[4],
+			TiltAngle:    c[5],
+			Flatness:     c[6],
+			IsFullCircle: e.Bool,
+		}
+	case FlatPoint:
+		return &Point{
+			EntityBase:  e.EntityBase,
+			X:           c[0],
+			Y:           c[1],
+			IsTemporary: e.Bool,
+			Code:        e.Code,
+			Angle:       c[2],
+			Scale:       c[3],
+		}
+	case FlatText:
+		return &Text{
+			EntityBase: e.EntityBase,
+			StartX:     c[0],
+			StartY:     c[1],
+			EndX:       c[2],
+			EndY:       c[3],
+			TextType:   e.Code,
+			SizeX:      c[4],
+			SizeY:      c[5],
+			Spacing:    c[6],
+			Angle:      c[7],
+			FontName:   f.Strings[e.Index[0]],
+			Content:    f.Strings[e.Index[1]],
+		}
+	case FlatSolid:
+		return &Solid{
+			EntityBase: e.EntityBase,
+			Point1X:    c[0],
+			Point1Y:    c[1],
+			Point2X:    c[2],
+			Point2Y:    c[3],
+			Point3X:    c[4],
+			Point3Y:    c[5],
+			Point4X:    c[6],
+			Point4Y:    c[7],
+			Color:      e.Code,
+		}
+	case FlatBlock:
+		return &Block{
+			EntityBase: e.EntityBase,
+			RefX:       c[0],
+			RefY:       c[1],
+			ScaleX:     c[2],
+			ScaleY:     c[3],
+			Rotation:   c[4],
+			DefNumber:  e.Code,
+		}
+	}
+	return f.Others[e.Index[0]]
+}
+
+// Append adds entity to the end of the list.
+func (f *FlatEntities) Append(entity Entity) {
+	e := FlatEntity{EntityBase: *entity.Base()}
+	switch v := entity.(type) {
+	case *Line:
+		e.Kind = FlatLine
+		e.Coords = [8]float64{v.StartX, v.StartY, v.EndX, v.EndY}
+	case *Arc:
+		e.Kind = FlatArc
+		e.Coords = [8]float64{v.CenterX, v.CenterY, v.Radius, v.StartAngle, v.ArcAngle, v.TiltAngle, v.Flatness}
+		e.Bool = v.IsFullCircle
+	case *Point:
+		e.Kind = FlatPoint
+		e.Coords = [8]float64{v.X, v.Y, v.Angle, v.Scale}
+		e.Code = v.Code
+		e.Bool = v.IsTemporary
+	case *Text:
+		e.Kind = FlatText
+		e.Coords = [8]float64{v.StartX, v.StartY, v.EndX, v.EndY, v.SizeX, v.SizeY, v.Spacing, v.Angle}
+		e.Code = v.TextType
+		e.Index = [2]uint32{f.addString(v.FontName), f.addString(v.Content)}
+	case *Solid:
+		e.Kind = FlatSolid
+		e.Coords = [8]float64{v.Point1X, v.Point1Y, v.Point2X, v.Point2Y, v.Point3X, v.Point3Y, v.Point4X, v.Point4Y}
+		e.Code = v.Color
+	case *Block:
+		e.Kind = FlatBlock
+		e.Coords = [8]float64{v.RefX, v.RefY, v.ScaleX, v.ScaleY, v.Rotation}
+		e.Code = v.DefNumber
+	default:
+		e.Index[0] = uint32(len(f.Others))
+		f.Others = append(f.Others, entity)
+	}
+	f.Items = append(f.Items, e)
+}
+
+// addString stores s in Strings and returns its index.
+func (f *FlatEntities) addString(s string) uint32 {
+	f.Strings = append(f.Strings, s)
+	return uint32(len(f.Strings) - 1)
+}
+
+// ParseFlat reads a JWW file like ParseEntities and returns its main entity
+// list in flat form, with the header in the returned document. The parser
+// still decodes each entity into its usual type, but that value is
+// short-lived: the result holds no per-entity pointers, which cuts memory
+// use and garbage collection work for drawings with many entities. Block
+// definitions are not parsed.
+//
+// Example:
+//
+//	doc, flat, err := jww.ParseFlat(f)
+//	if err != nil {
+//		return err
+//	}
+//	for i := range flat.Items {
+//		if e := &flat.Items[i]; e.Kind == jww.FlatLine {
+//			total += math.Hypot(e.Coords[2]-e.Coords[0], e.Coords[3]-e.Coords[1])
+//		}
+//	}
+func ParseFlat(r io.Reader) (*Document, *FlatEntities, error) {
+	var header *Document
+	flat := &FlatEntities{}
+	opts := ParseOptions{
+		OnHeader: func(doc *Document) error {
+			header = doc
+			return nil
+		},
+		onCount: func(count int) {
+			flat.Items = make([]FlatEntity, 0, count)
+		},
+	}
+	err := ParseEntitiesWithOptions(r, opts, func(e Entity) error {
+		flat.Append(e)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, flat, nil
+}
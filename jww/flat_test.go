@@ -0,0 +1,164 @@
+package jww
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// flatTestData returns a written document with one entity of each type
+// Write supports.
+func flatTestData(t testing.TB) []byte {
+	doc := &Document{Version: 700, Entities: []Entity{
+		&Line{EntityBase: EntityBase{PenColor: 2, Layer: 3}, StartX: 1, StartY: 2, EndX: 3, EndY: 4},
+		&Arc{CenterX: 5, CenterY: 6, Radius: 7, ArcAngle: 1.5, TiltAngle: 0.2, Flatness: 0.5},
+		&Arc{Radius: 2, Flatness: 1, IsFullCircle: true},
+		&Point{EntityBase: EntityBase{PenStyle: 100}, X: 8, Y: 9, Code: 3, Angle: 45, Scale: 2},
+		&Point{X: 1, Y: 1, IsTemporary: true},
+		&Text{StartX: 1, EndX: 10, SizeX: 3, SizeY: 3, Spacing: 0.5, FontName: "ＭＳ ゴシック", Content: "平面図"},
+		&Solid{EntityBase: EntityBase{PenColor: 10}, Point1X: 1, Point2X: 2, Point3Y: 3, Point4Y: 4, Color: 0xFF00FF},
+		&Dimension{
+			Line: Line{EndX: 100},
+			Text: Text{EndX: 50, SizeX: 2, SizeY: 2, Content: "100"},
+		},
+	}}
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// checkFlat verifies that ParseFlat returns the entities ParseEntities does.
+func checkFlat(t *testing.T, data []byte) *FlatEntities {
+	t.Helper()
+	var want []Entity
+	if err := ParseEntities(bytes.NewReader(data), func(e Entity) error {
+		want = append(want, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("ParseEntities failed: %v", err)
+	}
+
+	doc, flat, err := ParseFlat(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseFlat failed: %v", err)
+	}
+	if doc == nil || len(doc.Entities) != 0 {
+		t.Fatalf("expected a header document without entities, got %+v", doc)
+	}
+	if flat.Len() != len(want) {
+		t.Fatalf("got %d entities, want %d", flat.Len(), len(want))
+	}
+	for i := range want {
+		if got := flat.Entity(i); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("entity %d: got %+v, want %+v", i, got, want[i])
+		}
+	}
+	return flat
+}
+
+func TestParseFlat(t *testing.T) {
+	flat := checkFlat(t, flatTestData(t))
+
+	var kinds []FlatKind
+	for _, e := range flat.Items {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []FlatKind{FlatLine, FlatArc, FlatArc, FlatPoint, FlatPoint, FlatText, FlatSolid, FlatOther}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("kinds: got %v, want %v", kinds, want)
+	}
+	if len(flat.Others) != 1 {
+		t.Fatalf("expected the dimension in Others, got %d entities", len(flat.Others))
+	}
+	if _, ok := flat.Others[0].(*Dimension); !ok {
+		t.Errorf("Others[0]: got %T, want *Dimension", flat.Others[0])
+	}
+	if c := flat.Items[0].Coords; c[0] != 1 || c[3] != 4 {
+		t.Errorf("line coords: got %v", c)
+	}
+}
+
+func TestParseFlat_SampleFile(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "examples", "jww", "敷地図.jww"))
+	if err != nil {
+		t.Skip("sample file not found")
+	}
+	checkFlat(t, data)
+}
+
+func TestFlatEntities_Append(t *testing.T) {
+	entities := []Entity{
+		&Solid{Point2X: 1, Point3Y: 1, Point4Y: 1},
+		&Block{EntityBase: EntityBase{Layer: 1}, RefX: 1, RefY: 2, ScaleX: 3, ScaleY: 4, Rotation: 0.5, DefNumber: 7},
+		&Dimension{Line: Line{EndX: 10}, Text: Text{Content: "10"}},
+	}
+	var flat FlatEntities
+	for _, e := range entities {
+		flat.Append(e)
+	}
+
+	if flat.Items[1].Kind != FlatBlock || flat.Items[2].Kind != FlatOther {
+		t.Errorf("kinds: got %v, %v", flat.Items[1].Kind, flat.Items[2].Kind)
+	}
+	for i, want := range entities {
+		if got := flat.Entity(i); !reflect.DeepEqual(got, want) {
+			t.Errorf("entity %d: got %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+// benchmarkParseFlat compares ParseFlat with collecting the entities of
+// ParseEntities, which allocates one value per entity that stays live.
+func benchmarkParseFlat(b *testing.B, data []byte) {
+	b.Run("Interface", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var entities []Entity
+			err := ParseEntities(bytes.NewReader(data), func(e Entity) error {
+				entities = append(entities, e)
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Flat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := ParseFlat(bytes.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkParseFlat benchmarks the flat representation on the e2e sample.
+func BenchmarkParseFlat(b *testing.B) {
+	data, err := os.ReadFile(filepath.Join("..", "examples", "jww", "敷地図.jww"))
+	if err != nil {
+		b.Skip("sample file not found")
+	}
+	benchmarkParseFlat(b, data)
+}
+
+// BenchmarkParseFlat_Large benchmarks the flat representation on a written
+// document of 30000 entities.
+func BenchmarkParseFlat_Large(b *testing.B) {
+	doc := &Document{Version: 700}
+	for i := 0; i < 10000; i++ {
+		doc.Entities = append(doc.Entities,
+			&Line{EndX: float64(i)},
+			&Arc{Radius: 1, Flatness: 1},
+			&Text{SizeY: 3, Content: "A"})
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		b.Fatal(err)
+	}
+	benchmarkParseFlat(b, buf.Bytes())
+}
@@ -0,0 +1,77 @@
+package jww
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// fuzzSeeds returns well-formed JWW data used as fuzzing seeds.
+func fuzzSeeds(t testing.TB) [][]byte {
+	var buf bytes.Buffer
+	doc := &Document{Version: 700, Entities: []Entity{
+		&Line{EndX: 1},
+		&Arc{Radius: 1, Flatness: 1},
+		&Point{EntityBase: EntityBase{PenStyle: 100}, Code: 1},
+		&Text{Content: "文字"},
+		&Solid{EntityBase: EntityBase{PenColor: 10}},
+		&Dimension{Line: Line{EndX: 1}, Text: Text{Content: "1"}},
+	}}
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	return [][]byte{createMinimalJWWData(), createMinimalJWWDataWithBlockDef(), buf.Bytes()}
+}
+
+// FuzzParse checks that Parse returns an error instead of panicking on
+// malformed input. Run with:
+//
+//	go test ./jww -run XXX -fuzz FuzzParse -fuzzminimizetime 2s
+//
+// The short minimize time keeps the multi-kilobyte seeds from stalling the run.
+func FuzzParse(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := Parse(bytes.NewReader(data))
+		if err == nil && doc == nil {
+			t.Fatal("Parse returned neither a document nor an error")
+		}
+	})
+}
+
+func TestParse_Truncated(t *testing.T) {
+	for _, seed := range fuzzSeeds(t) {
+		full, err := Parse(bytes.NewReader(seed))
+		if err != nil {
+			t.Fatalf("Parse of seed failed: %v", err)
+		}
+		for n := 0; n < len(seed); n++ {
+			doc, err := Parse(bytes.NewReader(seed[:n]))
+			if err == nil && len(doc.Entities) > len(full.Entities) {
+				t.Fatalf("truncated at %d: got %d entities, full file has %d", n, len(doc.Entities), len(full.Entities))
+			}
+		}
+	}
+}
+
+func TestParse_TruncatedEntity(t *testing.T) {
+	// A line record cut off in its end point used to parse with zero coordinates
+	data := createLineEntityList(1)
+	_, _, err := parseEntityListWithOffset(NewReader(bytes.NewReader(data[:len(data)-4])), 600, newClassRegistry(ParseOptions{}), ParseOptions{})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestReadCString_HugeLength(t *testing.T) {
+	// A corrupt 4-byte length must fail on the missing data, not allocate 4 GiB
+	data := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 'a', 'b'}
+	_, err := NewReader(bytes.NewReader(data)).ReadCString()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
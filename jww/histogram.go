@@ -0,0 +1,63 @@
+package jww
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ClassHistogram counts the objects of each MFC class, such as "CDataSen"
+// for lines, in the entity list and block definitions of a JWW file,
+// including classes the parser does not know. It is meant for triaging
+// files before a full parse.
+//
+// Entities are not collected: records of known classes are decoded only
+// to find where the next one starts, as they carry no length. An unknown
+// class is counted once where it is met; since its record cannot be
+// skipped, counting stops there and the counts so far are returned with
+// an error wrapping ErrUnknownClass.
+//
+// It returns ErrInvalidSignature for data that is not a JWW file, and an
+// error if no entity list is found.
+//
+// Example:
+//
+//	counts, err := jww.ClassHistogram(f)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(counts["CDataSen"], "lines")
+func ClassHistogram(r io.Reader) (map[string]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	if len(data) < 12 || string(data[:8]) != "JwwData." {
+		return nil, ErrInvalidSignature
+	}
+	version := binary.LittleEndian.Uint32(data[8:])
+
+	counts := make(map[string]int)
+	opts := ParseOptions{
+		onEntity: func(Entity) error { return nil },
+		onClass:  func(className string) { counts[className]++ },
+	}
+
+	offset := findEntityListOffset(data, version)
+	if offset < 0 {
+		return nil, fmt.Errorf("could not find entity list in file")
+	}
+	// Block definitions follow the entity list in the same archive, so
+	// class PIDs carry over from one list to the other
+	classes := newClassRegistry(opts)
+	jr := opts.newReader(data[offset:])
+	if _, _, err := parseEntityListWithOffset(jr, version, classes, opts); err != nil {
+		return counts, fmt.Errorf("counting entity list: %w", err)
+	}
+	jr = opts.newReader(data[offset+int(jr.BytesRead()):])
+	if _, err := parseBlockDefList(jr, version, classes, opts); errors.Is(err, ErrUnknownClass) {
+		return counts, fmt.Errorf("counting block definitions: %w", err)
+	}
+	return counts, nil
+}
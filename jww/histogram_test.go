@@ -0,0 +1,121 @@
+package jww
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestClassHistogram(t *testing.T) {
+	doc := &Document{Version: 700}
+	doc.Entities = []Entity{
+		&Line{EndX: 1},
+		&Arc{Radius: 1, Flatness: 1},
+		&Line{EndY: 1},
+		&Point{X: 1},
+		&Text{SizeY: 2.5, Content: "A"},
+		&Solid{Point2X: 1, Point3Y: 1},
+		&Line{EndX: 2},
+		&Dimension{Line: Line{EndX: 10}, Text: Text{Content: "10"}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	counts, err := ClassHistogram(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ClassHistogram failed: %v", err)
+	}
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := make(map[string]int)
+	for _, e := range parsed.Entities {
+		className, err := classNameOf(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[className]++
+	}
+	if !maps.Equal(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
+func TestClassHistogram_BlockDefs(t *testing.T) {
+	counts, err := ClassHistogram(bytes.NewReader(createMinimalJWWDataWithBlockDef()))
+	if err != nil {
+		t.Fatalf("ClassHistogram failed: %v", err)
+	}
+	want := map[string]int{"CDataSen": 1, "CDataList": 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
+func TestClassHistogram_StopsAfterBlockDefs(t *testing.T) {
+	// Data after the block definitions, such as embedded images, is not
+	// scanned for further entity lists
+	other := &Document{Version: 700, Entities: []Entity{&Point{}, &Point{}}}
+	var buf bytes.Buffer
+	if err := Write(&buf, other); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	list := buf.Bytes()[findEntityListOffset(buf.Bytes(), 700):]
+	data := append(createMinimalJWWDataWithBlockDef(), list...)
+
+	counts, err := ClassHistogram(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ClassHistogram failed: %v", err)
+	}
+	want := map[string]int{"CDataSen": 1, "CDataList": 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
+func TestClassHistogram_UnknownBlockDefClass(t *testing.T) {
+	data := bytes.Replace(createMinimalJWWDataWithBlockDef(), []byte("CDataList"), []byte("CDataLizt"), 1)
+
+	counts, err := ClassHistogram(bytes.NewReader(data))
+	if !errors.Is(err, ErrUnknownClass) {
+		t.Fatalf("expected ErrUnknownClass, got %v", err)
+	}
+	want := map[string]int{"CDataSen": 1, "CDataLizt": 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
+func TestClassHistogram_UnknownClass(t *testing.T) {
+	doc := &Document{Version: 700, Entities: []Entity{&Line{}, &Line{}, &Point{}, &Line{}}}
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data := bytes.Replace(buf.Bytes(), []byte("CDataTen"), []byte("CDataXyz"), 1)
+	if _, err := Parse(bytes.NewReader(data)); !errors.Is(err, ErrUnknownClass) {
+		t.Fatalf("Parse: expected ErrUnknownClass, got %v", err)
+	}
+
+	counts, err := ClassHistogram(bytes.NewReader(data))
+	if !errors.Is(err, ErrUnknownClass) {
+		t.Fatalf("expected ErrUnknownClass, got %v", err)
+	}
+	// The line after the unknown object cannot be reached
+	want := map[string]int{"CDataSen": 2, "CDataXyz": 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
+func TestClassHistogram_InvalidSignature(t *testing.T) {
+	if _, err := ClassHistogram(bytes.NewReader([]byte("NotAJwwFile"))); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
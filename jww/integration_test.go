@@ -186,6 +186,39 @@ func BenchmarkParse(b *testing.B) {
 	}
 }
 
+// BenchmarkParse_File parses a written document of 30000 entities from an
+// unbuffered file, where every read is a system call.
+func BenchmarkParse_File(b *testing.B) {
+	doc := &Document{Version: 700}
+	for i := 0; i < 10000; i++ {
+		doc.Entities = append(doc.Entities,
+			&Line{EndX: float64(i)},
+			&Arc{Radius: 1, Flatness: 1},
+			&Solid{})
+	}
+	path := filepath.Join(b.TempDir(), "bench.jww")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := Write(f, doc); err != nil {
+		b.Fatal(err)
+	}
+	f.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f, err := os.Open(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := Parse(f); err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+	}
+}
+
 type bytesReader struct {
 	data []byte
 	pos  int
@@ -0,0 +1,68 @@
+package jww
+
+// LayerInfo summarizes one of the 256 layers of a document together with
+// the settings of its layer group.
+type LayerInfo struct {
+	// Group and Layer are the layer group number and the layer number
+	// within the group (0-15 each).
+	Group, Layer int
+
+	// Name is the layer name, e.g. "2-3" for an unnamed layer read by Parse.
+	Name string
+
+	// State is the layer state: 0 hidden, 1 display only, 2 editable, 3 write mode.
+	State uint32
+
+	// Protect is the layer protection flag.
+	Protect uint32
+
+	// GroupName, GroupState, and GroupScale are the name, state, and scale
+	// denominator of the layer group.
+	GroupName  string
+	GroupState uint32
+	GroupScale float64
+
+	// EntityCount is the number of entities of the main entity list on
+	// this layer. Block definition entities are not counted.
+	EntityCount int
+}
+
+// Layers returns the 256 layers of the document in group and layer order,
+// with the number of entities on each. Entities with out-of-range layer
+// numbers are not counted.
+//
+// Example:
+//
+//	for _, l := range doc.Layers() {
+//	    if l.EntityCount > 0 {
+//	        fmt.Printf("%X-%X %s: %d\n", l.Group, l.Layer, l.Name, l.EntityCount)
+//	    }
+//	}
+func (d *Document) Layers() []LayerInfo {
+	var counts [16][16]int
+	for _, e := range d.Entities {
+		base := e.Base()
+		if base.LayerGroup < 16 && base.Layer < 16 {
+			counts[base.LayerGroup][base.Layer]++
+		}
+	}
+
+	layers := make([]LayerInfo, 0, 16*16)
+	for g := range d.LayerGroups {
+		group := &d.LayerGroups[g]
+		for l, layer := range group.Layers {
+			layers = append(layers, LayerInfo{
+				Group:       g,
+				Layer:       l,
+				Name:        layer.Name,
+				State:       layer.State,
+				Protect:     layer.Protect,
+				GroupName:   group.Name,
+				GroupState:  group.State,
+				GroupScale:  group.Scale,
+				EntityCount: counts[g][l],
+			})
+		}
+	}
+	return layers
+}
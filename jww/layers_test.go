@@ -0,0 +1,48 @@
+package jww
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDocumentLayers(t *testing.T) {
+	var buf bytes.Buffer
+	fixture := &Document{Version: 700, Entities: []Entity{
+		&Line{EndX: 1},
+		&Line{EntityBase: EntityBase{LayerGroup: 2, Layer: 3}, EndX: 1},
+		&Point{EntityBase: EntityBase{LayerGroup: 2, Layer: 3}},
+	}}
+	fixture.LayerGroups[2].State = 2
+	fixture.LayerGroups[2].Scale = 50
+	fixture.LayerGroups[2].Layers[3].State = 1
+	if err := Write(&buf, fixture); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	doc, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	layers := doc.Layers()
+	if len(layers) != 256 {
+		t.Fatalf("expected 256 layers, got %d", len(layers))
+	}
+
+	got := layers[2*16+3]
+	want := LayerInfo{
+		Group: 2, Layer: 3, Name: "2-3", State: 1,
+		GroupName: "Group2", GroupState: 2, GroupScale: 50,
+		EntityCount: 2,
+	}
+	if got != want {
+		t.Errorf("layer 2-3:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	total := 0
+	for _, l := range layers {
+		total += l.EntityCount
+	}
+	if total != 3 || layers[0].EntityCount != 1 {
+		t.Errorf("entity counts: got %d in total and %d on 0-0, want 3 and 1", total, layers[0].EntityCount)
+	}
+}
@@ -0,0 +1,129 @@
+package jww
+
+import "math"
+
+// LineTypeDef is a user-defined line type (ユーザー線種) of the SXF
+// extended line type table, which Ver.4.20 and later store in the header.
+type LineTypeDef struct {
+	// PenStyle is the pen style number of entities drawn with this line
+	// type: 30 plus its index in the table.
+	PenStyle byte
+
+	// Name is the line type name.
+	Name string
+
+	// Pattern holds the lengths of the dashes and gaps in paper
+	// millimeters, alternating and starting with a dash.
+	Pattern []float64
+}
+
+// SXF extended line type table layout.
+const (
+	sxfLineTypeOffset   = 30 // pen style number of table entry 0
+	sxfLineTypeCount    = 33
+	sxfLineTypeMaxPitch = 10
+	sxfColorCount       = 257
+)
+
+// parseLineTypes reads the header settings following the layer groups up to
+// the SXF extended line type table and returns its defined line types.
+// It returns nil for files before Ver.4.20, which have no such table, and
+// when the header does not have the expected layout.
+func parseLineTypes(jr *Reader, version uint32) []LineTypeDef {
+	if version < 420 {
+		return nil
+	}
+
+	skipHeaderSettings(jr, version)
+
+	// Line patterns, dot counts, and pitches
+	jr.Skip(sxfLineTypeCount * 4 * 4)
+
+	var defs []LineTypeDef
+	for n := 0; n < sxfLineTypeCount; n++ {
+		name, _ := jr.ReadCString()
+		segments, _ := jr.ReadDWORD()
+		pitches, _ := jr.ReadDoubles(sxfLineTypeMaxPitch)
+		if jr.Err() != nil || segments > sxfLineTypeMaxPitch {
+			return nil
+		}
+		for _, p := range pitches[:segments] {
+			if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+				return nil
+			}
+		}
+		if segments == 0 {
+			continue
+		}
+		defs = append(defs, LineTypeDef{
+			PenStyle: byte(sxfLineTypeOffset + n),
+			Name:     name,
+			Pattern:  pitches[:segments],
+		})
+	}
+	return defs
+}
+
+// skipHeaderSettings skips the header from the layer groups up to the SXF
+// extended line type table, following the save order of Jw_cad.
+func skipHeaderSettings(jr *Reader, version uint32) {
+	skipDWORDs := func(n int) { jr.Skip(4 * n) }
+	skipDoubles := func(n int) { jr.Skip(8 * n) }
+	skipCStrings := func(n int) {
+		for range n {
+			jr.ReadCString()
+		}
+	}
+
+	skipDWORDs(14 + 5 + 1 + 1) // dummies, dimension settings, dummy, max line width
+	skipDoubles(3)             // printer origin and scale
+	skipDWORDs(2)              // printer rotation, scale mode
+	skipDoubles(5)             // scale display interval and base point
+	skipCStrings(16*16 + 16)   // layer and layer group names
+	skipDoubles(2)             // shadow calculation
+	skipDWORDs(1)
+	skipDoubles(1)
+	if version >= 300 {
+		skipDoubles(2) // sky map
+	}
+	skipDWORDs(1)  // 2.5D unit
+	skipDoubles(6) // screen and range zoom with base points
+	if version >= 300 {
+		for range 8 { // mark jumps
+			skipDoubles(3)
+			skipDWORDs(1)
+		}
+		skipDoubles(3) // text drawing state
+		skipDWORDs(1)
+		skipDoubles(3)
+		skipDWORDs(1)
+	} else {
+		skipDoubles(4 * 3)
+	}
+	skipDoubles(10 + 1) // parallel line spacings
+	skipDWORDs(10 * 2)  // screen colors and widths
+	for range 10 {      // printer colors, widths, point radii
+		skipDWORDs(2)
+		skipDoubles(1)
+	}
+	skipDWORDs(8*4 + 5*5 + 4*4) // line types 2-9, random lines, double-length line types
+	skipDWORDs(2 + 9)           // point radii and drawing/printing flags
+	if version >= 223 {
+		skipDWORDs(1 + 1 + 3) // drawing time, 2.5D eye settings
+		skipDoubles(2 + 2 + 1)
+	}
+	if version >= 225 {
+		skipDoubles(1 + 2 + 1) // last line length, box size, radius
+	}
+	if version >= 230 {
+		skipDWORDs(2) // solid color
+	}
+
+	// SXF extended colors
+	skipDWORDs(sxfColorCount * 2)
+	for range sxfColorCount {
+		skipCStrings(1)
+		skipDWORDs(2)
+		skipDoubles(1)
+	}
+}
@@ -0,0 +1,71 @@
+package jww
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// headerSettingsSize is the size of the Ver.7.00 header settings from the
+// layer groups up to the SXF extended line type table when all strings
+// are empty.
+const headerSettingsSize = 7993
+
+// layerGroupsEnd returns the offset after the layer groups of a file
+// written by Write with an empty memo.
+func layerGroupsEnd() int {
+	return 8 + 4 + 1 + 4 + 4 + 16*(4+4+8+4+16*(4+4))
+}
+
+func TestParse_LineTypes(t *testing.T) {
+	var file bytes.Buffer
+	doc := &Document{Version: 700, Entities: []Entity{
+		&Line{EntityBase: EntityBase{PenStyle: 31}, EndX: 1},
+	}}
+	if err := Write(&file, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	// Header settings and line patterns, all zero
+	var header bytes.Buffer
+	header.Write(make([]byte, headerSettingsSize+sxfLineTypeCount*4*4))
+	jw := NewWriter(&header)
+	for n := range sxfLineTypeCount {
+		name, pattern := "", []float64(nil)
+		if n == 1 {
+			name, pattern = "USER1", []float64{4, 1, 0.5, 1}
+		}
+		jw.WriteCString(name)
+		jw.WriteDWORD(uint32(len(pattern)))
+		for j := range sxfLineTypeMaxPitch {
+			value := 0.0
+			if j < len(pattern) {
+				value = pattern[j]
+			}
+			jw.WriteDouble(value)
+		}
+	}
+
+	data := file.Bytes()
+	end := layerGroupsEnd()
+	data = slices.Concat(data[:end], header.Bytes(), data[end:])
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := []LineTypeDef{{PenStyle: 31, Name: "USER1", Pattern: []float64{4, 1, 0.5, 1}}}
+	if !reflect.DeepEqual(parsed.LineTypes, want) {
+		t.Errorf("LineTypes: got %+v, want %+v", parsed.LineTypes, want)
+	}
+	if len(parsed.Entities) != 1 || parsed.Entities[0].Base().PenStyle != 31 {
+		t.Errorf("expected the line after the header settings, got %v", parsed.Entities)
+	}
+
+	// Files written without the header settings have no line types
+	if reparsed, err := Parse(bytes.NewReader(file.Bytes())); err != nil || reparsed.LineTypes != nil {
+		t.Errorf("expected no line types without header settings, got %v (err %v)", reparsed.LineTypes, err)
+	}
+}
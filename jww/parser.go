@@ -2,8 +2,11 @@ package jww
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 )
 
 // Parse reads a JWW (Jw_cad) file from the provided reader and returns a parsed Document.
@@ -37,6 +40,204 @@ import (
 //
 //	fmt.Printf("Version: %d, Entities: %d\n", doc.Version, len(doc.Entities))
 func Parse(r io.Reader) (*Document, error) {
+	return ParseWithOptions(r, ParseOptions{})
+}
+
+// progressInterval is the number of entities parsed between
+// ParseOptions.Progress calls.
+const progressInterval = 1000
+
+// ParseOptions controls optional behavior of ParseWithOptions.
+// The zero value parses like Parse.
+type ParseOptions struct {
+	// Progress, if set, is called while the main entity list is parsed with
+	// the number of entities done so far and the entity count stored in the
+	// file: every 1000 entities, and once more with done == total when the
+	// list is complete. Entities inside block definitions are not reported.
+	Progress func(done, total int)
+
+	// MaxEntities is the largest entity count accepted for an entity list
+	// (the main list or one block definition). Lists declaring more fail
+	// with ErrTooManyEntities before anything is allocated, which guards
+	// against corrupt or misaligned counts. Values <= 0 allow any count the
+	// WORD count field can hold (65535).
+	MaxEntities int
+
+	// MaxStringLen is the longest string, in bytes, accepted for the memo,
+	// text contents, and names. Longer length prefixes fail with
+	// ErrStringTooLong before the string is read, which guards against
+	// corrupt lengths. Values <= 0 use DefaultMaxStringLen.
+	MaxStringLen int
+
+	// OnHeader, if set, is called once the file header and layer groups
+	// are read, before any entity is parsed. The document has no entities
+	// yet. Returning an error stops parsing with that error.
+	OnHeader func(doc *Document) error
+
+	// VersionOverride, if non-zero, is used instead of the file version to
+	// decide which version-dependent fields entity records contain, such as
+	// PenWidth (Ver.3.51+) or the extra dimension data (Ver.4.20+). Use it
+	// only when the true format of a file with an unusual version number
+	// is known: a wrong value misaligns every following read, which yields
+	// garbage entities or a parse error. Document.Version and the class
+	// schema checks keep the version stored in the file.
+	VersionOverride uint32
+
+	// ctx is checked for cancellation while entities are parsed.
+	// It is set by ParseContextWithOptions; nil means never canceled.
+	ctx context.Context
+
+	// classSchemas collects the schema number of each class definition.
+	// It is set by ParseWithOptions; nil means schemas are not recorded.
+	classSchemas map[string]uint16
+
+	// onEntity receives each entity of the main entity list instead of
+	// Document.Entities. It is set by ParseEntitiesWithOptions.
+	onEntity func(Entity) error
+
+	// onClass receives the class name of each object read, before its data
+	// is parsed. It is set by ClassHistogram.
+	onClass func(className string)
+
+	// onCount receives the declared entity count of the main entity list
+	// before its entities are parsed, when streaming with onEntity. It is
+	// set by ParseFlat to size its result.
+	onCount func(count int)
+}
+
+// DefaultMaxStringLen is the string length limit used when
+// ParseOptions.MaxStringLen is not set. Jw_cad strings are far shorter.
+const DefaultMaxStringLen = 1 << 20
+
+// newReader returns a Reader over data with the string length limit applied.
+func (o ParseOptions) newReader(data []byte) *Reader {
+	jr := NewReader(bytes.NewReader(data))
+	if o.MaxStringLen > 0 {
+		jr.SetMaxStringLen(o.MaxStringLen)
+	} else {
+		jr.SetMaxStringLen(DefaultMaxStringLen)
+	}
+	return jr
+}
+
+// recordSchema stores the schema number read for a class definition.
+func (o ParseOptions) recordSchema(className string, schema uint16) {
+	if o.classSchemas != nil {
+		o.classSchemas[className] = schema
+	}
+}
+
+// classRead reports an object of class className to onClass, if set.
+func (o ParseOptions) classRead(className string) {
+	if o.onClass != nil {
+		o.onClass(className)
+	}
+}
+
+// ctxCheckInterval is the number of entities parsed between checks for
+// context cancellation.
+const ctxCheckInterval = 64
+
+// canceled returns the context error once the parse context is done.
+func (o ParseOptions) canceled() error {
+	if o.ctx == nil {
+		return nil
+	}
+	return o.ctx.Err()
+}
+
+// ParseContext reads a JWW file like Parse, stopping early with an error
+// wrapping ctx.Err() when ctx is canceled or its deadline passes. Use it to
+// bound the time spent on untrusted input.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	doc, err := jww.ParseContext(ctx, f)
+//	if errors.Is(err, context.DeadlineExceeded) {
+//	    return fmt.Errorf("parsing took too long: %w", err)
+//	}
+func ParseContext(ctx context.Context, r io.Reader) (*Document, error) {
+	return ParseContextWithOptions(ctx, r, ParseOptions{})
+}
+
+// ParseContextWithOptions is ParseContext with options, such as Progress
+// or MaxEntities, controlling the parse like ParseWithOptions.
+//
+// Example:
+//
+//	doc, err := jww.ParseContextWithOptions(ctx, f, jww.ParseOptions{
+//	    MaxEntities: 100000,
+//	    Progress: func(done, total int) {
+//	        fmt.Printf("\r%d/%d entities", done, total)
+//	    },
+//	})
+func ParseContextWithOptions(ctx context.Context, r io.Reader, opts ParseOptions) (*Document, error) {
+	opts.ctx = ctx
+	return ParseWithOptions(r, opts)
+}
+
+// StopIteration can be returned by a ParseEntities callback to stop parsing
+// early. ParseEntities then returns nil.
+var StopIteration = errors.New("jww: stop iteration")
+
+// ParseEntities reads a JWW file like Parse, but passes each entity of the
+// main entity list to fn as it is parsed instead of collecting them in
+// Document.Entities. It is equivalent to ParseEntitiesWithOptions with zero
+// options.
+//
+// Example:
+//
+//	var found *jww.Text
+//	err := jww.ParseEntities(f, func(e jww.Entity) error {
+//		if t, ok := e.(*jww.Text); ok && strings.Contains(t.Content, "GL") {
+//			found = t
+//			return jww.StopIteration
+//		}
+//		return nil
+//	})
+func ParseEntities(r io.Reader, fn func(Entity) error) error {
+	return ParseEntitiesWithOptions(r, ParseOptions{}, fn)
+}
+
+// ParseEntitiesWithOptions streams the main entity list to fn like
+// ParseEntities, applying the behavior selected in opts. Use opts.OnHeader
+// to receive the header and layer groups before the first entity.
+//
+// Parsing stops at the first error returned by fn or opts.OnHeader. That
+// error is returned so errors.Is matches it, except StopIteration, for which
+// nil is returned.
+// Block definitions are not parsed. The file itself is still read into
+// memory; only the entity slice is avoided.
+func ParseEntitiesWithOptions(r io.Reader, opts ParseOptions, fn func(Entity) error) error {
+	opts.onEntity = fn
+	_, err := ParseWithOptions(r, opts)
+	if errors.Is(err, StopIteration) {
+		return nil
+	}
+	return err
+}
+
+// progress reports parse progress if a Progress callback is set.
+func (o ParseOptions) progress(done, total int) {
+	if o.Progress != nil {
+		o.Progress(done, total)
+	}
+}
+
+// ParseWithOptions reads a JWW file like Parse, applying the behavior
+// selected in opts.
+//
+// Example:
+//
+//	doc, err := jww.ParseWithOptions(f, jww.ParseOptions{
+//		Progress: func(done, total int) {
+//			fmt.Printf("\r%d/%d entities", done, total)
+//		},
+//	})
+func ParseWithOptions(r io.Reader, opts ParseOptions) (*Document, error) {
 	// Read entire file into memory for simpler parsing
 	data, err := io.ReadAll(r)
 	if err != nil {
@@ -48,7 +249,11 @@ func Parse(r io.Reader) (*Document, error) {
 		return nil, ErrInvalidSignature
 	}
 
-	jr := NewReader(bytes.NewReader(data))
+	if err := opts.canceled(); err != nil {
+		return nil, fmt.Errorf("parsing canceled: %w", err)
+	}
+
+	jr := opts.newReader(data)
 
 	// Skip signature
 	jr.Skip(8)
@@ -108,6 +313,24 @@ func Parse(r io.Reader) (*Document, error) {
 		}
 	}
 
+	opts.classSchemas = make(map[string]uint16)
+	doc.ClassSchemas = opts.classSchemas
+
+	if err := jr.Err(); err != nil {
+		return nil, fmt.Errorf("reading layer groups: %w", err)
+	}
+
+	doc.LineTypes = parseLineTypes(jr, version)
+
+	// Parse layer names from earlier in the file
+	parseLayerNames(data, doc)
+
+	if opts.OnHeader != nil {
+		if err := opts.OnHeader(doc); err != nil {
+			return nil, err
+		}
+	}
+
 	// Find entity list start by scanning for the first CData class pattern
 	// Pattern: [count DWORD] [0xFF 0xFF] [schema WORD] [name_len WORD] ["CData..."]
 	entityListOffset := findEntityListOffset(data, version)
@@ -115,29 +338,172 @@ func Parse(r io.Reader) (*Document, error) {
 		return nil, fmt.Errorf("could not find entity list in file")
 	}
 
-	// Parse entities from found offset
-	jr2 := NewReader(bytes.NewReader(data[entityListOffset:]))
-	entities, bytesRead, err := parseEntityListWithOffset(jr2, version)
+	// Entity records are laid out by version, which the caller may override
+	recordVersion := version
+	if opts.VersionOverride != 0 {
+		recordVersion = opts.VersionOverride
+	}
+
+	// Parse entities from found offset. Class PIDs carry over to the block
+	// definitions, which are written to the same archive.
+	classes := newClassRegistry(opts)
+	jr2 := opts.newReader(data[entityListOffset:])
+	entities, bytesRead, err := parseEntityListWithOffset(jr2, recordVersion, classes, opts)
 	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			pe.Offset += entityListOffset // make the offset file-relative
+		}
 		return nil, fmt.Errorf("parsing entity list: %w", err)
 	}
 	doc.Entities = entities
+	if opts.onEntity != nil {
+		// Streaming covers the main entity list only
+		return doc, nil
+	}
 
 	// Parse block definitions (immediately after entity list)
-	jr3 := NewReader(bytes.NewReader(data[entityListOffset+bytesRead:]))
-	blockDefs, err := parseBlockDefList(jr3, version)
-	if err != nil {
-		// Block definitions might not exist in all files, just continue
-		blockDefs = nil
-	}
+	jr3 := opts.newReader(data[entityListOffset+bytesRead:])
+	blockDefs, err := parseBlockDefList(jr3, recordVersion, classes, opts)
+	if cerr := opts.canceled(); cerr != nil {
+		// Block definition errors are tolerated below, cancellation is not
+		return nil, fmt.Errorf("parsing block definitions canceled: %w", cerr)
+	}
+	// Block definitions might not exist in all files, or be cut short;
+	// keep what was read and continue
 	doc.BlockDefs = blockDefs
 
-	// Parse layer names from earlier in the file
-	parseLayerNames(data, doc)
+	// Ver.7.00+ files end with the images embedded in the drawing; report
+	// any data left after them, or after the block definitions if the
+	// images cannot be read.
+	consumed := entityListOffset + bytesRead + int(jr3.BytesRead())
+	if version >= 700 {
+		if n, err := skipEmbeddedImages(opts.newReader(data[consumed:])); err == nil {
+			consumed += n
+		}
+	}
+	if trailing := len(data) - consumed; trailing > 0 {
+		doc.Warnings = append(doc.Warnings,
+			fmt.Sprintf("trailing %d bytes not parsed after block definitions (offset %d)", trailing, consumed))
+	}
+
+	doc.Warnings = append(doc.Warnings, schemaWarnings(doc.ClassSchemas, version)...)
+
+	inferLayerGroupPens(doc)
+	doc.Groups = entityGroups(doc.Entities)
 
 	return doc, nil
 }
 
+// schemaWarnings reports classes whose schema number differs from the file
+// version. Jw_cad writes the version as the schema of every class, so a
+// mismatch hints at a format change the parser may not handle.
+func schemaWarnings(classSchemas map[string]uint16, version uint32) []string {
+	var warnings []string
+	for className, schema := range classSchemas {
+		if uint32(schema) != version {
+			warnings = append(warnings,
+				fmt.Sprintf("class %s has schema %d, file version is %d", className, schema, version))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// inferLayerGroupPens sets each layer group's InferredPenColor and
+// InferredPenStyle to the values most used by its lines and arcs, as the
+// file stores none. Other entity types are not counted: their pen fields
+// encode other things, such as a text's base point position or a solid's
+// fill. Ties resolve to the lower value so results are deterministic.
+func inferLayerGroupPens(doc *Document) {
+	var colors [16]map[uint16]int
+	var styles [16]map[byte]int
+
+	for _, e := range doc.Entities {
+		switch e.(type) {
+		case *Line, *Arc:
+		default:
+			continue
+		}
+		base := e.Base()
+		g := base.LayerGroup
+		if g >= 16 {
+			continue
+		}
+		if colors[g] == nil {
+			colors[g] = make(map[uint16]int)
+			styles[g] = make(map[byte]int)
+		}
+		colors[g][base.PenColor]++
+		styles[g][base.PenStyle]++
+	}
+
+	for g := range doc.LayerGroups {
+		lg := &doc.LayerGroups[g]
+		best := 0
+		for color, n := range colors[g] {
+			if n > best || (n == best && color < lg.InferredPenColor) {
+				lg.InferredPenColor, best = color, n
+			}
+		}
+		best = 0
+		for style, n := range styles[g] {
+			if n > best || (n == best && style < lg.InferredPenStyle) {
+				lg.InferredPenStyle, best = style, n
+			}
+		}
+	}
+}
+
+// entityGroups collects the entities with a nonzero curve attribute number
+// into groups, ordered by number.
+func entityGroups(entities []Entity) []EntityGroup {
+	members := make(map[uint32][]int)
+	for i, e := range entities {
+		if group := e.Base().Group; group != 0 {
+			members[group] = append(members[group], i)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	groups := make([]EntityGroup, 0, len(members))
+	for number, indices := range members {
+		groups = append(groups, EntityGroup{Number: number, Entities: indices})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Number < groups[j].Number })
+	return groups
+}
+
+// skipEmbeddedImages reads past the images embedded in Ver.7.00+ files
+// after the block definition list: a DWORD image count, then for each image
+// its file name (CString), a DWORD size, and size bytes of file data. It
+// returns the number of bytes read.
+func skipEmbeddedImages(jr *Reader) (int, error) {
+	count, err := jr.ReadDWORD()
+	if err != nil {
+		return 0, fmt.Errorf("reading image count: %w", err)
+	}
+	for i := range count {
+		if _, err := jr.ReadCString(); err != nil {
+			return 0, fmt.Errorf("reading image %d name: %w", i, err)
+		}
+		size, err := jr.ReadDWORD()
+		if err != nil {
+			return 0, fmt.Errorf("reading image %d size: %w", i, err)
+		}
+		if err := jr.Skip(int(size)); err != nil {
+			return 0, fmt.Errorf("reading image %d data: %w", i, err)
+		}
+	}
+	return int(jr.BytesRead()), nil
+}
+
+// maxClassNameLen is the longest class name accepted in a new class definition.
+// JWW class names such as "CDataSunpou" are far shorter; larger values
+// indicate the parser has lost its position in the stream.
+const maxClassNameLen = 64
+
 // findEntityListOffset scans the file for the entity list start position.
 // The entity list is preceded by [count DWORD] and starts with a class definition.
 func findEntityListOffset(data []byte, version uint32) int {
@@ -169,7 +535,9 @@ func findEntityListOffset(data []byte, version uint32) int {
 }
 
 // parseEntityListWithOffset parses the entity list and returns bytes consumed.
-func parseEntityListWithOffset(jr *Reader, version uint32) ([]Entity, int, error) {
+// Class tags are resolved through classes. opts.Progress is called as
+// entities are parsed.
+func parseEntityListWithOffset(jr *Reader, version uint32, classes *classRegistry, opts ParseOptions) ([]Entity, int, error) {
 	startBytes := jr.BytesRead()
 
 	countWord, err := jr.ReadWORD()
@@ -177,84 +545,75 @@ func parseEntityListWithOffset(jr *Reader, version uint32) ([]Entity, int, error
 		return nil, 0, fmt.Errorf("reading entity count: %w", err)
 	}
 	count := uint32(countWord)
+	if opts.MaxEntities > 0 && int(count) > opts.MaxEntities {
+		return nil, 0, fmt.Errorf("%w: list declares %d entities, limit is %d", ErrTooManyEntities, count, opts.MaxEntities)
+	}
 
-	entities := make([]Entity, 0, count)
-
-	// MFC CArchive PID tracking:
-	// - Each new class definition gets a PID
-	// - Each object also gets a PID
-	// - PIDs are assigned sequentially starting from 1
-	// - Class references use 0x8000 | class_PID
-	pidToClassName := make(map[uint32]string) // PID -> class name
-	nextPID := uint32(1)
+	var entities []Entity
+	if opts.onEntity == nil {
+		entities = make([]Entity, 0, count)
+	} else if opts.onCount != nil {
+		opts.onCount(int(count))
+	}
 
 	for i := uint32(0); i < count; i++ {
-		entity, newPID, err := parseEntityWithPIDTracking(jr, version, pidToClassName, nextPID)
+		if i%ctxCheckInterval == 0 {
+			if err := opts.canceled(); err != nil {
+				return entities, 0, fmt.Errorf("parsing canceled at entity %d/%d: %w", i+1, count, err)
+			}
+		}
+		entity, err := parseEntityWithPIDTracking(jr, version, classes)
 		if err != nil {
+			var pe *ParseError
+			if errors.As(err, &pe) {
+				pe.EntityIndex = int(i)
+			}
 			return entities, 0, fmt.Errorf("parsing entity %d/%d: %w", i+1, count, err)
 		}
-		nextPID = newPID
 		if entity != nil {
-			entities = append(entities, entity)
+			if opts.onEntity != nil {
+				if err := opts.onEntity(entity); err != nil {
+					return nil, 0, err
+				}
+			} else {
+				entities = append(entities, entity)
+			}
+		}
+		if done := i + 1; done%progressInterval == 0 && done < count {
+			opts.progress(int(done), int(count))
 		}
 	}
+	opts.progress(int(count), int(count))
 
 	bytesConsumed := jr.BytesRead() - startBytes
 	return entities, int(bytesConsumed), nil
 }
 
 // parseEntityWithPIDTracking parses an entity using MFC CArchive PID tracking.
-// In MFC serialization:
-// - 0xFFFF = new class definition follows (schema + name), then assign PID to class
-// - 0x8000 = null object
-// - 0x8000 | n = reference to class with PID n
-// After parsing each object, assign a new PID to that object too.
-func parseEntityWithPIDTracking(jr *Reader, version uint32, pidToClassName map[uint32]string, nextPID uint32) (Entity, uint32, error) {
-	classID, err := jr.ReadWORD()
-	if err != nil {
-		return nil, nextPID, err
-	}
-
+// The class tag is read through classes, which assigns PIDs to new class
+// definitions and to the object once it is parsed. A null object yields a
+// nil entity.
+//
+// Errors are returned as *ParseError carrying the object's offset relative to
+// the start of jr and the class name, when known.
+func parseEntityWithPIDTracking(jr *Reader, version uint32, classes *classRegistry) (entity Entity, err error) {
+	start := int(jr.BytesRead())
 	var className string
-
-	if classID == 0xFFFF {
-		// New class definition
-		schemaVer, err := jr.ReadWORD()
-		if err != nil {
-			return nil, nextPID, fmt.Errorf("reading schema version: %w", err)
-		}
-		_ = schemaVer
-
-		nameLen, err := jr.ReadWORD()
+	defer func() {
 		if err != nil {
-			return nil, nextPID, fmt.Errorf("reading class name length: %w", err)
+			err = &ParseError{Offset: start, EntityIndex: -1, ClassName: className, Err: err}
 		}
+	}()
 
-		nameBuf := make([]byte, nameLen)
-		if err := jr.ReadBytes(nameBuf); err != nil {
-			return nil, nextPID, fmt.Errorf("reading class name: %w", err)
-		}
-		className = string(nameBuf)
-
-		// Assign PID to this class definition
-		pidToClassName[nextPID] = className
-		nextPID++
-	} else if classID == 0x8000 {
-		// Null object
-		return nil, nextPID, nil
-	} else {
-		// Class reference: 0x8000 | class_PID
-		// The lower bits contain the PID of the class definition
-		classPID := uint32(classID & 0x7FFF)
-		var ok bool
-		className, ok = pidToClassName[classPID]
-		if !ok {
-			return nil, nextPID, fmt.Errorf("unknown class PID: %d (have PIDs: %v)", classPID, getKeys(pidToClassName))
-		}
+	className, _, err = classes.ReadClassRef(jr)
+	if err != nil {
+		return nil, err
+	}
+	if className == "" {
+		return nil, nil // null object
 	}
 
 	// Parse the object based on class name
-	var entity Entity
 	switch className {
 	case "CDataSen":
 		entity, err = parseLine(jr, version)
@@ -271,26 +630,20 @@ func parseEntityWithPIDTracking(jr *Reader, version uint32, pidToClassName map[u
 	case "CDataSunpou":
 		entity, err = parseDimension(jr, version)
 	default:
-		return nil, nextPID, fmt.Errorf("unknown entity class: %s", className)
+		return nil, ErrUnknownClass
 	}
 
+	if err == nil {
+		// Entity parsers ignore individual read errors; catch truncated records here
+		err = jr.Err()
+	}
 	if err != nil {
-		return nil, nextPID, err
+		return nil, err
 	}
 
-	// Assign PID to this object
-	nextPID++
-
-	return entity, nextPID, nil
-}
+	entity.Base().ID = classes.objectRead()
 
-// getKeys returns the keys of a map for debugging
-func getKeys(m map[uint32]string) []uint32 {
-	keys := make([]uint32, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	return keys
+	return entity, nil
 }
 
 // parseLayerNames extracts layer names from the file.
@@ -309,8 +662,9 @@ func parseLayerNames(data []byte, doc *Document) {
 	}
 }
 
-// parseBlockDefList parses the block definition list
-func parseBlockDefList(jr *Reader, version uint32) ([]BlockDef, error) {
+// parseBlockDefList parses the block definition list, resolving class tags
+// through classes, the registry of the preceding entity list.
+func parseBlockDefList(jr *Reader, version uint32, classes *classRegistry, opts ParseOptions) ([]BlockDef, error) {
 	count, err := jr.ReadDWORD()
 	if err != nil {
 		return nil, fmt.Errorf("reading block def count: %w", err)
@@ -322,45 +676,44 @@ func parseBlockDefList(jr *Reader, version uint32) ([]BlockDef, error) {
 	}
 
 	blockDefs := make([]BlockDef, 0, count)
-	classMap := make(map[uint16]string)
-	nextID := uint16(1)
 
 	for i := uint32(0); i < count; i++ {
-		bd, newID, err := parseBlockDefWithTracking(jr, version, classMap, nextID)
-		if err != nil {
-			return blockDefs, nil // Return what we have
+		if err := opts.canceled(); err != nil {
+			return blockDefs, err
 		}
-		nextID = newID
+		bd, err := parseBlockDefWithTracking(jr, version, classes, opts)
 		if bd != nil {
 			blockDefs = append(blockDefs, *bd)
 		}
+		if err != nil {
+			// Return what we have
+			return blockDefs, fmt.Errorf("block def %d: %w", i, err)
+		}
 	}
 
 	return blockDefs, nil
 }
 
 // parseBlockDefWithTracking parses a single block definition with class tracking.
-func parseBlockDefWithTracking(jr *Reader, version uint32, classMap map[uint16]string, nextID uint16) (*BlockDef, uint16, error) {
-	classID, err := jr.ReadWORD()
+func parseBlockDefWithTracking(jr *Reader, version uint32, classes *classRegistry, opts ParseOptions) (*BlockDef, error) {
+	className, _, err := classes.ReadClassRef(jr)
 	if err != nil {
-		return nil, nextID, err
+		return nil, err
 	}
-
-	if classID == 0xFFFF {
-		_, _ = jr.ReadWORD() // schema
-		nameLen, _ := jr.ReadWORD()
-		nameBuf := make([]byte, nameLen)
-		jr.ReadBytes(nameBuf)
-		classMap[nextID] = string(nameBuf)
-		nextID++
-	} else if classID == 0x8000 {
-		return nil, nextID, nil
+	if className == "" {
+		return nil, nil // null object
+	}
+	if className != "CDataList" {
+		return nil, ErrUnknownClass
 	}
+	// The block definition gets its PID before the nested entities get theirs
+	id := classes.objectRead()
 
 	base, err := parseEntityBase(jr, version)
 	if err != nil {
-		return nil, nextID, err
+		return nil, err
 	}
+	base.ID = id
 
 	bd := &BlockDef{EntityBase: *base}
 
@@ -373,52 +726,63 @@ func parseBlockDefWithTracking(jr *Reader, version uint32, classMap map[uint16]s
 
 	bd.Name, _ = jr.ReadCString()
 
-	// Parse nested entities
-	nestedEntities, _, err := parseEntityListWithOffset(jr, version)
+	// Parse nested entities; progress and streaming cover the main entity list only
+	opts.Progress = nil
+	opts.onEntity = nil
+	nestedEntities, _, err := parseEntityListWithOffset(jr, version, classes, opts)
 	if err != nil {
-		return bd, nextID, nil
+		// Keep the definition without its entities
+		return bd, err
 	}
 	bd.Entities = nestedEntities
 
-	return bd, nextID, nil
+	return bd, nil
 }
 
 // parseDimension parses a dimension entity from the JWW file (JWW class: CDataSunpou).
-// Dimensions are complex entities composed of lines and text to show measurements.
-// This function extracts the dimension data and returns the associated line entity.
-// Version 4.20 and later include additional SXF mode data.
-func parseDimension(jr *Reader, version uint32) (Entity, error) {
+// The record holds complete line and text records (each with its own entity
+// base) after the dimension's entity base. Version 4.20 and later add the
+// SXF mode, two extension lines, and the end and auxiliary points.
+func parseDimension(jr *Reader, version uint32) (*Dimension, error) {
 	base, err := parseEntityBase(jr, version)
 	if err != nil {
 		return nil, err
 	}
-	_ = base
 
-	// Parse the line member
+	dim := &Dimension{EntityBase: *base}
+
 	line, err := parseLine(jr, version)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("reading dimension line: %w", err)
 	}
+	dim.Line = *line
 
-	// Parse the text member
-	_, err = parseText(jr, version)
+	text, err := parseText(jr, version)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("reading dimension text: %w", err)
 	}
+	dim.Text = *text
 
-	// Ver.4.20+ has additional SXF mode data
 	if version >= 420 {
-		_, _ = jr.ReadWORD() // SXF mode
+		dim.SXFMode, _ = jr.ReadWORD()
 
-		for i := 0; i < 2; i++ {
-			parseLine(jr, version)
+		for i := range dim.ExtensionLines {
+			if line, err = parseLine(jr, version); err != nil {
+				return nil, fmt.Errorf("reading dimension extension line: %w", err)
+			}
+			dim.ExtensionLines[i] = *line
 		}
-		for i := 0; i < 4; i++ {
-			parsePoint(jr, version)
+		points := []*Point{&dim.EndPoints[0], &dim.EndPoints[1], &dim.AuxPoints[0], &dim.AuxPoints[1]}
+		for _, p := range points {
+			pt, err := parsePoint(jr, version)
+			if err != nil {
+				return nil, fmt.Errorf("reading dimension point: %w", err)
+			}
+			*p = *pt
 		}
 	}
 
-	return line, nil
+	return dim, nil
 }
 
 // parseEntityBase reads the common entity base fields shared by all entity types.
@@ -488,10 +852,11 @@ func parseLine(jr *Reader, version uint32) (*Line, error) {
 
 	line := &Line{EntityBase: *base}
 
-	line.StartX, _ = jr.ReadDouble()
-	line.StartY, _ = jr.ReadDouble()
-	line.EndX, _ = jr.ReadDouble()
-	line.EndY, _ = jr.ReadDouble()
+	v, err := jr.ReadDoubles(4)
+	if err != nil {
+		return nil, err
+	}
+	line.StartX, line.StartY, line.EndX, line.EndY = v[0], v[1], v[2], v[3]
 
 	return line, nil
 }
@@ -507,13 +872,12 @@ func parseArc(jr *Reader, version uint32) (*Arc, error) {
 
 	arc := &Arc{EntityBase: *base}
 
-	arc.CenterX, _ = jr.ReadDouble()
-	arc.CenterY, _ = jr.ReadDouble()
-	arc.Radius, _ = jr.ReadDouble()
-	arc.StartAngle, _ = jr.ReadDouble()
-	arc.ArcAngle, _ = jr.ReadDouble()
-	arc.TiltAngle, _ = jr.ReadDouble()
-	arc.Flatness, _ = jr.ReadDouble()
+	v, err := jr.ReadDoubles(7)
+	if err != nil {
+		return nil, err
+	}
+	arc.CenterX, arc.CenterY, arc.Radius = v[0], v[1], v[2]
+	arc.StartAngle, arc.ArcAngle, arc.TiltAngle, arc.Flatness = v[3], v[4], v[5], v[6]
 	fullCircle, _ := jr.ReadDWORD()
 	arc.IsFullCircle = fullCircle != 0
 
@@ -535,6 +899,8 @@ func parsePoint(jr *Reader, version uint32) (*Point, error) {
 	tmp, _ := jr.ReadDWORD()
 	pt.IsTemporary = tmp != 0
 
+	// Jw_cad stores a pen style of 100 exactly when the point has a marker
+	// code (CDataTen::Serialize), so no other pen style carries these fields.
 	if base.PenStyle == 100 {
 		pt.Code, _ = jr.ReadDWORD()
 		pt.Angle, _ = jr.ReadDouble()
@@ -580,14 +946,15 @@ func parseSolid(jr *Reader, version uint32) (*Solid, error) {
 
 	solid := &Solid{EntityBase: *base}
 
-	solid.Point1X, _ = jr.ReadDouble()
-	solid.Point1Y, _ = jr.ReadDouble()
-	solid.Point4X, _ = jr.ReadDouble()
-	solid.Point4Y, _ = jr.ReadDouble()
-	solid.Point2X, _ = jr.ReadDouble()
-	solid.Point2Y, _ = jr.ReadDouble()
-	solid.Point3X, _ = jr.ReadDouble()
-	solid.Point3Y, _ = jr.ReadDouble()
+	// Points are stored in the order 1, 4, 2, 3
+	v, err := jr.ReadDoubles(8)
+	if err != nil {
+		return nil, err
+	}
+	solid.Point1X, solid.Point1Y = v[0], v[1]
+	solid.Point4X, solid.Point4Y = v[2], v[3]
+	solid.Point2X, solid.Point2Y = v[4], v[5]
+	solid.Point3X, solid.Point3Y = v[6], v[7]
 
 	if base.PenColor == 10 {
 		solid.Color, _ = jr.ReadDWORD()
@@ -2,9 +2,14 @@ package jww
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -134,6 +139,97 @@ func TestParse_LayerGroups(t *testing.T) {
 	}
 }
 
+func TestParse_UnknownClassError(t *testing.T) {
+	data := createMinimalJWWData()
+	nameAt := bytes.Index(data, []byte("CDataSen"))
+	copy(data[nameAt:], "CDataFoo")
+
+	_, err := Parse(bytes.NewReader(data))
+	if err == nil {
+		t.Fatal("expected error for unknown class")
+	}
+
+	if !errors.Is(err, ErrUnknownClass) {
+		t.Errorf("expected errors.Is(err, ErrUnknownClass), got %v", err)
+	}
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	if pe.ClassName != "CDataFoo" {
+		t.Errorf("ClassName: got %q, want CDataFoo", pe.ClassName)
+	}
+	// The object starts at the 0xFFFF marker, 6 bytes before the name (marker, schema, name length)
+	if want := nameAt - 6; pe.Offset != want {
+		t.Errorf("Offset: got %d, want %d", pe.Offset, want)
+	}
+	if pe.EntityIndex != 0 {
+		t.Errorf("EntityIndex: got %d, want 0", pe.EntityIndex)
+	}
+}
+
+func TestParse_LayerGroupInferredPens(t *testing.T) {
+	doc, err := Parse(bytes.NewReader(createMinimalJWWData()))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// The single line entity is on group 0 with pen color 1, style 1
+	if doc.LayerGroups[0].InferredPenColor != 1 || doc.LayerGroups[0].InferredPenStyle != 1 {
+		t.Errorf("group 0 inferred pens: got color %d style %d, want 1 and 1",
+			doc.LayerGroups[0].InferredPenColor, doc.LayerGroups[0].InferredPenStyle)
+	}
+	if doc.LayerGroups[1].InferredPenColor != 0 || doc.LayerGroups[1].InferredPenStyle != 0 {
+		t.Errorf("group 1 inferred pens: got color %d style %d, want 0 and 0",
+			doc.LayerGroups[1].InferredPenColor, doc.LayerGroups[1].InferredPenStyle)
+	}
+}
+
+func TestInferLayerGroupPens(t *testing.T) {
+	doc := &Document{Entities: []Entity{
+		&Line{EntityBase: EntityBase{LayerGroup: 2, PenColor: 5, PenStyle: 2}},
+		&Line{EntityBase: EntityBase{LayerGroup: 2, PenColor: 8, PenStyle: 3}},
+		&Line{EntityBase: EntityBase{LayerGroup: 2, PenColor: 8, PenStyle: 2}},
+		&Line{EntityBase: EntityBase{LayerGroup: 3, PenColor: 4, PenStyle: 1}},
+		&Line{EntityBase: EntityBase{LayerGroup: 3, PenColor: 3, PenStyle: 1}},
+	}}
+
+	inferLayerGroupPens(doc)
+
+	if got := doc.LayerGroups[2].InferredPenColor; got != 8 {
+		t.Errorf("group 2 color: got %d, want 8 (most used)", got)
+	}
+	if got := doc.LayerGroups[2].InferredPenStyle; got != 2 {
+		t.Errorf("group 2 pen style: got %d, want 2 (most used)", got)
+	}
+	if got := doc.LayerGroups[3].InferredPenColor; got != 3 {
+		t.Errorf("group 3 color: got %d, want 3 (lowest of tie)", got)
+	}
+}
+
+func TestInferLayerGroupPens_LinesAndArcsOnly(t *testing.T) {
+	doc := &Document{Entities: []Entity{
+		&Arc{EntityBase: EntityBase{LayerGroup: 1, PenColor: 2, PenStyle: 1}},
+		&Text{EntityBase: EntityBase{LayerGroup: 1, PenColor: 6, PenStyle: 4}},
+		&Text{EntityBase: EntityBase{LayerGroup: 1, PenColor: 6, PenStyle: 4}},
+		&Text{EntityBase: EntityBase{LayerGroup: 2, PenColor: 6, PenStyle: 4}},
+		&Point{EntityBase: EntityBase{LayerGroup: 2, PenColor: 3, PenStyle: 100}},
+		&Solid{EntityBase: EntityBase{LayerGroup: 2, PenColor: 10, PenStyle: 101}},
+	}}
+
+	inferLayerGroupPens(doc)
+
+	if lg := doc.LayerGroups[1]; lg.InferredPenColor != 2 || lg.InferredPenStyle != 1 {
+		t.Errorf("group 1: got color %d style %d, want the arc's 2 and 1",
+			lg.InferredPenColor, lg.InferredPenStyle)
+	}
+	if lg := doc.LayerGroups[2]; lg.InferredPenColor != 0 || lg.InferredPenStyle != 0 {
+		t.Errorf("group 2 without lines or arcs: got color %d style %d, want 0 and 0",
+			lg.InferredPenColor, lg.InferredPenStyle)
+	}
+}
+
 func TestParseLine(t *testing.T) {
 	// Create minimal line entity data
 	// EntityBase (version 600): DWORD group + BYTE penStyle + WORD penColor + WORD penWidth + WORD layer + WORD layerGroup + WORD flag
@@ -282,6 +378,32 @@ func TestParseText(t *testing.T) {
 	}
 }
 
+func TestTextImage(t *testing.T) {
+	tests := []struct {
+		content string
+		ok      bool
+		want    ImageReference
+	}{
+		{"^@BM%temp%logo.bmp,100,50,0,0,1,0", true, ImageReference{Path: "%temp%logo.bmp", Width: 100, Height: 50}},
+		{"^@BMC:\\images\\a.jpg,20.5,10", true, ImageReference{Path: "C:\\images\\a.jpg", Width: 20.5, Height: 10}},
+		{"^@BMbroken.bmp", false, ImageReference{}},
+		{"^@BMbad.bmp,x,10", false, ImageReference{}},
+		{"plain text", false, ImageReference{}},
+	}
+
+	for _, tt := range tests {
+		text := &Text{Content: tt.content}
+		got, ok := text.Image()
+		if ok != tt.ok {
+			t.Errorf("Image(%q): ok = %v, want %v", tt.content, ok, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Image(%q): got %+v, want %+v", tt.content, got, tt.want)
+		}
+	}
+}
+
 func TestParseSolid(t *testing.T) {
 	data := make([]byte, 0)
 
@@ -318,6 +440,250 @@ func TestParseSolid(t *testing.T) {
 	}
 }
 
+func TestEntitiesOfType(t *testing.T) {
+	doc := &Document{Entities: []Entity{
+		&Line{EndX: 1},
+		&Text{Content: "A"},
+		&Arc{Radius: 1},
+		&Line{EndX: 2},
+		&Text{Content: "B"},
+	}}
+
+	lines := EntitiesOfType[*Line](doc)
+	if len(lines) != 2 || lines[0].EndX != 1 || lines[1].EndX != 2 {
+		t.Errorf("lines: got %+v", lines)
+	}
+	texts := EntitiesOfType[*Text](doc)
+	if len(texts) != 2 || texts[0].Content != "A" || texts[1].Content != "B" {
+		t.Errorf("texts: got %+v", texts)
+	}
+	if solids := EntitiesOfType[*Solid](doc); len(solids) != 0 {
+		t.Errorf("solids: got %d, want 0", len(solids))
+	}
+}
+
+func TestSolidCircleSolid(t *testing.T) {
+	tests := []struct {
+		name      string
+		penStyle  byte
+		kindValue float64
+		want      CircleSolidKind
+	}{
+		{"sector", 101, 0, CircleSolidSector},
+		{"segment", 101, 5, CircleSolidSegment},
+		{"full", 101, 100, CircleSolidFull},
+		{"outer arc", 101, -1, CircleSolidOuterArc},
+		{"annulus", 105, 3, CircleSolidAnnulus},
+		{"circumference", 111, 0, CircleSolidCircumference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solid := &Solid{
+				EntityBase: EntityBase{PenStyle: tt.penStyle},
+				Point1X:    10, Point1Y: 20, // center
+				Point4X: 5, Point4Y: 1, // radius, flatness
+				Point2X: 0, Point2Y: 0.5, // tilt, start angle
+				Point3X: 1, Point3Y: tt.kindValue, // arc angle, kind
+			}
+			cs, ok := solid.CircleSolid()
+			if !ok {
+				t.Fatal("expected a circle solid")
+			}
+			if cs.Kind != tt.want {
+				t.Errorf("kind: got %v, want %v", cs.Kind, tt.want)
+			}
+			if cs.CenterX != 10 || cs.CenterY != 20 || cs.Radius != 5 {
+				t.Errorf("geometry: got center (%v, %v) radius %v", cs.CenterX, cs.CenterY, cs.Radius)
+			}
+		})
+	}
+
+	if _, ok := (&Solid{EntityBase: EntityBase{PenStyle: 1}}).CircleSolid(); ok {
+		t.Error("quadrilateral solid reported as circle solid")
+	}
+}
+
+// createLineEntityList builds an entity list of n CDataSen lines, the first
+// defining the class and the rest referencing it.
+func createLineEntityList(n int) []byte {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+
+	_ = binary.Write(&buf, le, uint16(n))
+	for i := 0; i < n; i++ {
+		if i == 0 {
+			_ = binary.Write(&buf, le, uint16(0xFFFF))
+			_ = binary.Write(&buf, le, uint16(0))
+			_ = binary.Write(&buf, le, uint16(len("CDataSen")))
+			buf.WriteString("CDataSen")
+		} else {
+			_ = binary.Write(&buf, le, uint16(0x8001)) // class PID 1
+		}
+		_ = binary.Write(&buf, le, uint32(0)) // group
+		buf.WriteByte(1)                      // penStyle
+		_ = binary.Write(&buf, le, uint16(1)) // penColor
+		_ = binary.Write(&buf, le, uint16(1)) // penWidth
+		_ = binary.Write(&buf, le, uint16(0)) // layer
+		_ = binary.Write(&buf, le, uint16(0)) // layerGroup
+		_ = binary.Write(&buf, le, uint16(0)) // flag
+		for _, v := range []float64{0, 0, float64(i), 1} {
+			_ = binary.Write(&buf, le, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestParseEntityList_ClassSchemas(t *testing.T) {
+	opts := ParseOptions{classSchemas: make(map[string]uint16)}
+	if _, _, err := parseEntityListWithOffset(NewReader(bytes.NewReader(createLineEntityList(3))), 600, newClassRegistry(opts), opts); err != nil {
+		t.Fatalf("parseEntityListWithOffset failed: %v", err)
+	}
+
+	schema, ok := opts.classSchemas["CDataSen"]
+	if !ok || schema != 0 {
+		t.Fatalf("CDataSen schema: got %d (recorded %v), want 0", schema, ok)
+	}
+	warnings := schemaWarnings(opts.classSchemas, 600)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "CDataSen has schema 0") {
+		t.Errorf("warnings: got %v", warnings)
+	}
+}
+
+func TestParseEntityList_Progress(t *testing.T) {
+	data := createLineEntityList(2500)
+
+	var calls [][2]int
+	opts := ParseOptions{Progress: func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}}
+
+	entities, _, err := parseEntityListWithOffset(NewReader(bytes.NewReader(data)), 600, newClassRegistry(opts), opts)
+	if err != nil {
+		t.Fatalf("parseEntityListWithOffset failed: %v", err)
+	}
+	if len(entities) != 2500 {
+		t.Fatalf("entities: got %d, want 2500", len(entities))
+	}
+
+	want := [][2]int{{1000, 2500}, {2000, 2500}, {2500, 2500}}
+	if len(calls) != len(want) {
+		t.Fatalf("progress calls: got %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("progress call %d: got %v, want %v", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestParseWithOptions_Progress(t *testing.T) {
+	var lastDone, lastTotal int
+	_, err := ParseWithOptions(bytes.NewReader(createMinimalJWWData()), ParseOptions{
+		Progress: func(done, total int) { lastDone, lastTotal = done, total },
+	})
+	if err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+	if lastTotal != 1 || lastDone != lastTotal {
+		t.Errorf("final progress: got %d/%d, want 1/1", lastDone, lastTotal)
+	}
+}
+
+func TestParseEntityList_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := ParseOptions{ctx: ctx}
+	opts.Progress = func(done, total int) {
+		if done == 1000 {
+			cancel()
+		}
+	}
+
+	entities, _, err := parseEntityListWithOffset(NewReader(bytes.NewReader(createLineEntityList(2500))), 600, newClassRegistry(opts), opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(entities) >= 2500 {
+		t.Errorf("expected parsing to stop early, got %d entities", len(entities))
+	}
+}
+
+func TestParseContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseContext(ctx, bytes.NewReader(createMinimalJWWData()))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if _, err := ParseContext(context.Background(), bytes.NewReader(createMinimalJWWData())); err != nil {
+		t.Fatalf("ParseContext failed: %v", err)
+	}
+}
+
+func TestParseContextWithOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	headers := 0
+	_, err := ParseContextWithOptions(ctx, bytes.NewReader(createMinimalJWWData()), ParseOptions{
+		OnHeader: func(*Document) error {
+			headers++
+			cancel()
+			return nil
+		},
+	})
+	if headers != 1 {
+		t.Errorf("OnHeader called %d times, want 1", headers)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled after OnHeader canceled, got %v", err)
+	}
+
+	var lastDone, lastTotal int
+	_, err = ParseContextWithOptions(context.Background(), bytes.NewReader(createMinimalJWWData()), ParseOptions{
+		Progress: func(done, total int) { lastDone, lastTotal = done, total },
+	})
+	if err != nil {
+		t.Fatalf("ParseContextWithOptions failed: %v", err)
+	}
+	if lastTotal != 1 || lastDone != lastTotal {
+		t.Errorf("final progress: got %d/%d, want 1/1", lastDone, lastTotal)
+	}
+}
+
+func TestParseEntityList_MaxEntities(t *testing.T) {
+	data := createLineEntityList(2500)
+
+	_, _, err := parseEntityListWithOffset(NewReader(bytes.NewReader(data)), 600, newClassRegistry(ParseOptions{MaxEntities: 1000}), ParseOptions{MaxEntities: 1000})
+	if !errors.Is(err, ErrTooManyEntities) {
+		t.Fatalf("expected ErrTooManyEntities, got %v", err)
+	}
+
+	entities, _, err := parseEntityListWithOffset(NewReader(bytes.NewReader(data)), 600, newClassRegistry(ParseOptions{MaxEntities: 2500}), ParseOptions{MaxEntities: 2500})
+	if err != nil {
+		t.Fatalf("parseEntityListWithOffset failed at the limit: %v", err)
+	}
+	if len(entities) != 2500 {
+		t.Errorf("entities: got %d, want 2500", len(entities))
+	}
+}
+
+func TestParseWithOptions_MaxEntities(t *testing.T) {
+	data := createMinimalJWWData()
+	// Corrupt the entity count to the largest WORD value
+	at := bytes.Index(data, []byte("CDataSen")) - 8
+	data[at], data[at+1] = 0xFF, 0xFF
+
+	_, err := ParseWithOptions(bytes.NewReader(data), ParseOptions{MaxEntities: 10000})
+	if !errors.Is(err, ErrTooManyEntities) {
+		t.Fatalf("expected ErrTooManyEntities, got %v", err)
+	}
+}
+
 func TestParseBlock(t *testing.T) {
 	data := make([]byte, 0)
 
@@ -373,6 +739,63 @@ func TestParse_BlockDefinitionsAreParsedAfterEntities(t *testing.T) {
 	}
 }
 
+func TestParse_TrailingBytesWarning(t *testing.T) {
+	data := createMinimalJWWDataWithBlockDef()
+
+	doc, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", doc.Warnings)
+	}
+
+	data = append(data, make([]byte, 100)...)
+	doc, err = Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", doc.Warnings)
+	}
+	if !strings.Contains(doc.Warnings[0], "trailing 100 bytes") {
+		t.Errorf("unexpected warning: %q", doc.Warnings[0])
+	}
+}
+
+func TestParse_EmbeddedImages(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, &Document{Version: 700, Entities: []Entity{&Line{EndX: 1}}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	buf.Truncate(buf.Len() - 4) // replace the empty image count
+
+	jw := NewWriter(&buf)
+	_ = jw.WriteDWORD(2)
+	for _, image := range []string{"a.bmp", "b.jpg"} {
+		_ = jw.WriteCString(image)
+		_ = jw.WriteDWORD(300)
+		_ = jw.WriteBytes(make([]byte, 300))
+	}
+	data := buf.Bytes()
+
+	doc, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", doc.Warnings)
+	}
+
+	doc, err = Parse(bytes.NewReader(append(data, 1, 2, 3)))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Warnings) != 1 || !strings.Contains(doc.Warnings[0], "trailing 3 bytes") {
+		t.Errorf("expected a warning about 3 trailing bytes, got %v", doc.Warnings)
+	}
+}
+
 // createMinimalJWWData creates minimal valid JWW file data for testing
 func createMinimalJWWData() []byte {
 	data := make([]byte, 0, 15000)
@@ -479,3 +902,214 @@ func createMinimalJWWDataWithBlockDef() []byte {
 
 	return append(data, buf.Bytes()...)
 }
+
+func TestParseEntities_StopIteration(t *testing.T) {
+	doc := &Document{Version: 700}
+	for i := 0; i < 10; i++ {
+		doc.Entities = append(doc.Entities, &Line{EndX: float64(i + 1)})
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var header *Document
+	var visited []Entity
+	err := ParseEntitiesWithOptions(bytes.NewReader(buf.Bytes()), ParseOptions{
+		OnHeader: func(d *Document) error {
+			header = d
+			return nil
+		},
+	}, func(e Entity) error {
+		if header == nil {
+			t.Error("entity visited before OnHeader")
+		}
+		visited = append(visited, e)
+		if len(visited) == 3 {
+			return StopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParseEntities failed: %v", err)
+	}
+	if len(visited) != 3 {
+		t.Fatalf("expected 3 entities visited, got %d", len(visited))
+	}
+	if l := visited[2].(*Line); l.EndX != 3 {
+		t.Errorf("third entity: got EndX %v, want 3", l.EndX)
+	}
+	if header.Version != 700 || len(header.Entities) != 0 {
+		t.Errorf("header: got version %d with %d entities", header.Version, len(header.Entities))
+	}
+}
+
+func TestParseEntities_Error(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, &Document{Version: 700, Entities: []Entity{&Line{}, &Line{}}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	errStop := errors.New("stop")
+	n := 0
+	err := ParseEntities(bytes.NewReader(buf.Bytes()), func(Entity) error {
+		n++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 entity visited, got %d", n)
+	}
+}
+
+// TestParse_Concurrent guards against shared mutable state in the parser.
+// Run it with -race.
+func TestParse_Concurrent(t *testing.T) {
+	data := fuzzSeeds(t)[2] // one entity of each type, written by Write
+
+	want, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	const workers = 50
+	results := make([]*Document, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = Parse(bytes.NewReader(data))
+		}()
+	}
+	wg.Wait()
+
+	for i := range workers {
+		if errs[i] != nil {
+			t.Fatalf("goroutine %d: Parse failed: %v", i, errs[i])
+		}
+		if !reflect.DeepEqual(results[i], want) {
+			t.Errorf("goroutine %d: result differs from sequential parse", i)
+		}
+	}
+}
+
+func TestParseWithOptions_VersionOverride(t *testing.T) {
+	// A file laid out like Ver.3.51 (with PenWidth) that reports version 300
+	var buf bytes.Buffer
+	src := &Document{Version: 351, Entities: []Entity{
+		&Line{EntityBase: EntityBase{PenWidth: 5}, EndX: 1},
+	}}
+	if err := Write(&buf, src); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint32(data[8:], 300)
+	data = bytes.ReplaceAll(data, []byte{0xFF, 0xFF, 0x5F, 0x01}, []byte{0xFF, 0xFF, 0x2C, 0x01})
+
+	// Without the override the PenWidth WORD is read as the layer number
+	doc, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if base := doc.Entities[0].Base(); base.PenWidth != 0 || base.Layer != 5 {
+		t.Errorf("without override: got PenWidth %d and Layer %d, want 0 and 5", base.PenWidth, base.Layer)
+	}
+
+	doc, err = ParseWithOptions(bytes.NewReader(data), ParseOptions{VersionOverride: 351})
+	if err != nil {
+		t.Fatalf("ParseWithOptions failed: %v", err)
+	}
+	if doc.Version != 300 {
+		t.Errorf("Version: got %d, want the stored 300", doc.Version)
+	}
+	if len(doc.Entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(doc.Entities))
+	}
+	line := doc.Entities[0].(*Line)
+	if line.PenWidth != 5 || line.EndX != 1 {
+		t.Errorf("got PenWidth %d and EndX %v, want 5 and 1", line.PenWidth, line.EndX)
+	}
+}
+
+func TestParse_NoFurtherEntityLists(t *testing.T) {
+	var buf bytes.Buffer
+	doc := &Document{Version: 700, Entities: []Entity{
+		&Line{EndX: 1},
+		&Text{Content: "main"},
+	}}
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// Bytes resembling an entity list after the block definitions and the
+	// image count are not part of the format and must not become entities.
+	extra := []Entity{&Line{EndX: 2}, &Text{Content: "reference"}}
+	if err := writeEntityList(NewWriter(&buf), extra, doc.Version); err != nil {
+		t.Fatalf("writeEntityList failed: %v", err)
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(parsed.Entities) != 2 {
+		t.Fatalf("expected the 2 entities of the main list, got %d", len(parsed.Entities))
+	}
+	if len(parsed.Warnings) == 0 {
+		t.Error("expected a warning about the trailing bytes")
+	}
+}
+
+func TestParse_MaxStringLen(t *testing.T) {
+	data := createMinimalJWWData()
+	// Replace the memo with a corrupt 4-byte length prefix
+	bogus := append([]byte("JwwData."), data[8:12]...)
+	bogus = append(bogus, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F)
+
+	_, err := Parse(bytes.NewReader(bogus))
+	if !errors.Is(err, ErrStringTooLong) {
+		t.Errorf("got error %v, want ErrStringTooLong", err)
+	}
+}
+
+func TestParse_EntityIDs(t *testing.T) {
+	var buf bytes.Buffer
+	doc := &Document{Version: 700, Entities: []Entity{
+		&Line{EndX: 1}, // class definition PID 1, object PID 2
+		&Arc{Radius: 1, Flatness: 1},
+		&Line{EndX: 2}, // class reference, object PID 5
+		&Line{EndX: 3},
+	}}
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	for i, want := range []uint32{2, 4, 5, 6} {
+		if got := parsed.Entities[i].Base().ID; got != want {
+			t.Errorf("entity %d: got ID %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestParse_Groups(t *testing.T) {
+	doc := &Document{Version: 700, Entities: []Entity{
+		&Line{EntityBase: EntityBase{Group: 5}, EndX: 1},
+		&Line{EndX: 2},
+		&Arc{EntityBase: EntityBase{Group: 2}, Radius: 1, Flatness: 1},
+		&Line{EntityBase: EntityBase{Group: 5}, EndX: 3},
+	}}
+
+	parsed := roundTrip(t, doc)
+
+	want := []EntityGroup{{Number: 2, Entities: []int{2}}, {Number: 5, Entities: []int{0, 3}}}
+	if !reflect.DeepEqual(parsed.Groups, want) {
+		t.Errorf("Groups: got %+v, want %+v", parsed.Groups, want)
+	}
+}
@@ -28,8 +28,9 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
-	"unsafe"
+	"math"
 
 	"golang.org/x/text/encoding/japanese"
 	"golang.org/x/text/transform"
@@ -41,27 +42,128 @@ var (
 
 	// ErrUnsupportedVersion is returned when the JWW file version is not supported by this parser.
 	ErrUnsupportedVersion = errors.New("unsupported JWW version")
+
+	// ErrUnknownClass is returned (wrapped in a ParseError) when the entity list
+	// contains an object of a class the parser does not support.
+	ErrUnknownClass = errors.New("unknown entity class")
+
+	// ErrTooManyEntities is returned when an entity list declares more
+	// entities than ParseOptions.MaxEntities allows.
+	ErrTooManyEntities = errors.New("entity count exceeds limit")
+
+	// ErrStringTooLong is returned when a string's length prefix exceeds
+	// the reader's limit (see Reader.SetMaxStringLen).
+	ErrStringTooLong = errors.New("string length exceeds limit")
 )
 
+// ParseError describes a failure to parse an object in the entity list.
+// It is returned wrapped by Parse; use errors.As to extract it:
+//
+//	var pe *jww.ParseError
+//	if errors.As(err, &pe) && errors.Is(err, jww.ErrUnknownClass) {
+//	    log.Printf("unsupported class %s at offset %d", pe.ClassName, pe.Offset)
+//	}
+type ParseError struct {
+	// Offset is the byte offset from the start of the file where the object begins.
+	Offset int
+
+	// EntityIndex is the 0-based index of the object in its entity list,
+	// or -1 if unknown.
+	EntityIndex int
+
+	// ClassName is the MFC class of the object, or "" if it was not read.
+	ClassName string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	if e.ClassName != "" {
+		return fmt.Sprintf("%s at offset %d: %v", e.ClassName, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error { return e.Err }
+
 // Reader wraps an io.Reader to provide convenient methods for reading JWW binary data.
 // All multi-byte values are read in little-endian format, and text strings are
 // decoded from Shift-JIS to UTF-8.
 type Reader struct {
-	r         io.Reader
+	r         *peekReader
 	buf       []byte
 	bytesRead int64
+	err       error
+
+	// scratch is reused between calls by ReadDoubles, ReadCString and Skip
+	// so they do not allocate for every record.
+	scratch []byte
+
+	// maxStringLen is the longest string ReadCString accepts; 0 means no limit.
+	maxStringLen int
+}
+
+// scratchChunk bounds how far the scratch buffer grows ahead of the data
+// actually read, and is the chunk size Skip discards at a time.
+const scratchChunk = 64 * 1024
+
+// peekReader serves bytes buffered by Reader.Peek before reading from src.
+type peekReader struct {
+	src     io.Reader
+	pending []byte
+}
+
+// Read implements io.Reader, draining pending bytes first.
+func (p *peekReader) Read(b []byte) (int, error) {
+	if len(p.pending) > 0 {
+		n := copy(b, p.pending)
+		p.pending = p.pending[n:]
+		return n, nil
+	}
+	return p.src.Read(b)
 }
 
 // NewReader creates a new JWW binary reader that wraps the provided io.Reader.
 // The reader maintains an internal buffer for efficient binary data reading.
 func NewReader(r io.Reader) *Reader {
 	return &Reader{
-		r:         r,
+		r:         &peekReader{src: r},
 		buf:       make([]byte, 8),
 		bytesRead: 0,
 	}
 }
 
+// SetMaxStringLen makes ReadCString reject strings longer than n bytes
+// with ErrStringTooLong before reading them, so a corrupt length prefix
+// fails early. n <= 0 removes the limit, which is the default.
+func (r *Reader) SetMaxStringLen(n int) {
+	r.maxStringLen = max(n, 0)
+}
+
+// Peek returns the next n bytes without advancing the reader.
+// The returned slice is a copy and stays valid after subsequent reads.
+// If fewer than n bytes remain, the available bytes are returned with
+// io.EOF or io.ErrUnexpectedEOF; they remain available to later reads.
+// A negative n is an error.
+func (r *Reader) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, r.fail(fmt.Errorf("peek of negative length %d", n))
+	}
+	p := r.r
+	if missing := n - len(p.pending); missing > 0 {
+		more := make([]byte, missing)
+		m, err := io.ReadFull(p.src, more)
+		p.pending = append(p.pending, more[:m]...)
+		if err != nil {
+			return append([]byte(nil), p.pending...), err
+		}
+	}
+	return append([]byte(nil), p.pending[:n]...), nil
+}
+
 // ReadSignature reads and validates the JWW file signature.
 // The signature must be the 8-byte string "JwwData.".
 // Returns ErrInvalidSignature if the signature is invalid.
@@ -84,7 +186,7 @@ func (r *Reader) ReadDWORD() (uint32, error) {
 	n, err := io.ReadFull(r.r, r.buf[:4])
 	r.bytesRead += int64(n)
 	if err != nil {
-		return 0, err
+		return 0, r.fail(err)
 	}
 	return binary.LittleEndian.Uint32(r.buf[:4]), nil
 }
@@ -95,7 +197,7 @@ func (r *Reader) ReadWORD() (uint16, error) {
 	n, err := io.ReadFull(r.r, r.buf[:2])
 	r.bytesRead += int64(n)
 	if err != nil {
-		return 0, err
+		return 0, r.fail(err)
 	}
 	return binary.LittleEndian.Uint16(r.buf[:2]), nil
 }
@@ -106,7 +208,7 @@ func (r *Reader) ReadBYTE() (byte, error) {
 	n, err := io.ReadFull(r.r, r.buf[:1])
 	r.bytesRead += int64(n)
 	if err != nil {
-		return 0, err
+		return 0, r.fail(err)
 	}
 	return r.buf[0], nil
 }
@@ -117,10 +219,43 @@ func (r *Reader) ReadDouble() (float64, error) {
 	n, err := io.ReadFull(r.r, r.buf[:8])
 	r.bytesRead += int64(n)
 	if err != nil {
-		return 0, err
+		return 0, r.fail(err)
 	}
 	bits := binary.LittleEndian.Uint64(r.buf[:8])
-	return float64FromBits(bits), nil
+	return math.Float64frombits(bits), nil
+}
+
+// ReadFloat reads a 32-bit IEEE 754 floating point number in little-endian
+// format and widens it to float64. The JWW records described in the format
+// notes store doubles; this is for single-precision fields in other data.
+func (r *Reader) ReadFloat() (float64, error) {
+	n, err := io.ReadFull(r.r, r.buf[:4])
+	r.bytesRead += int64(n)
+	if err != nil {
+		return 0, r.fail(err)
+	}
+	bits := binary.LittleEndian.Uint32(r.buf[:4])
+	return float64(math.Float32frombits(bits)), nil
+}
+
+// ReadDoubles reads n consecutive 64-bit IEEE 754 floating point numbers in
+// little-endian format with a single read. It returns the same values as n
+// calls to ReadDouble and is used by entity parsers that read fixed runs of
+// coordinates.
+func (r *Reader) ReadDoubles(n int) ([]float64, error) {
+	if n < 0 {
+		return nil, r.fail(fmt.Errorf("read of negative double count %d", n))
+	}
+	buf, err := r.readScratch(8 * n)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[8*i:]))
+	}
+	return values, nil
 }
 
 // ReadCString reads a length-prefixed string in MFC CString format.
@@ -135,7 +270,7 @@ func (r *Reader) ReadCString() (string, error) {
 	// Read length prefix
 	lenByte, err := r.ReadBYTE()
 	if err != nil {
-		return "", err
+		return "", r.fail(err)
 	}
 
 	var length uint32
@@ -145,7 +280,7 @@ func (r *Reader) ReadCString() (string, error) {
 		// Read 2-byte length
 		lenWord, err := r.ReadWORD()
 		if err != nil {
-			return "", err
+			return "", r.fail(err)
 		}
 		if lenWord < 0xFFFF {
 			length = uint32(lenWord)
@@ -153,7 +288,7 @@ func (r *Reader) ReadCString() (string, error) {
 			// Read 4-byte length
 			length, err = r.ReadDWORD()
 			if err != nil {
-				return "", err
+				return "", r.fail(err)
 			}
 		}
 	}
@@ -161,11 +296,13 @@ func (r *Reader) ReadCString() (string, error) {
 	if length == 0 {
 		return "", nil
 	}
+	if r.maxStringLen > 0 && int64(length) > int64(r.maxStringLen) {
+		return "", r.fail(fmt.Errorf("%w: %d bytes (limit %d)", ErrStringTooLong, length, r.maxStringLen))
+	}
 
-	// Read string bytes
-	strBuf := make([]byte, length)
-	n, err := io.ReadFull(r.r, strBuf)
-	r.bytesRead += int64(n)
+	// Read string bytes. The conversion copies them, so the scratch buffer
+	// can be reused.
+	strBuf, err := r.readScratch(int(length))
 	if err != nil {
 		return "", err
 	}
@@ -174,20 +311,103 @@ func (r *Reader) ReadCString() (string, error) {
 	return shiftJISToUTF8(strBuf), nil
 }
 
+// ReadStringFixed reads a fixed-width string field of exactly n bytes, as
+// opposed to a length-prefixed CString. The Shift-JIS data is converted to
+// UTF-8 after trailing null and space padding is removed.
+func (r *Reader) ReadStringFixed(n int) (string, error) {
+	if n < 0 {
+		return "", r.fail(fmt.Errorf("fixed string of negative length %d", n))
+	}
+	buf, err := r.readScratch(n)
+	if err != nil {
+		return "", err
+	}
+	// Shift-JIS trail bytes are 0x40 or above, so trimming cannot split a
+	// double-byte character.
+	return shiftJISToUTF8(bytes.TrimRight(buf, "\x00 ")), nil
+}
+
 // ReadBytes reads exactly len(buf) bytes into the provided buffer.
 // Returns an error if fewer bytes are available.
 func (r *Reader) ReadBytes(buf []byte) error {
 	n, err := io.ReadFull(r.r, buf)
 	r.bytesRead += int64(n)
-	return err
+	return r.fail(err)
 }
 
 // Skip skips n bytes in the input stream.
 // This is useful for skipping over unknown or unneeded data structures.
 func (r *Reader) Skip(n int) error {
-	buf := make([]byte, n)
-	read, err := io.ReadFull(r.r, buf)
-	r.bytesRead += int64(read)
+	if n < 0 {
+		return r.fail(fmt.Errorf("skip of negative length %d", n))
+	}
+	for n > 0 {
+		chunk := min(n, scratchChunk)
+		if _, err := r.readScratch(chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// Align skips to the next multiple of n bytes from the start of the input,
+// as counted by BytesRead, and does nothing if the reader is already there
+// or n <= 1. It is for data padded to a word or DWORD boundary; Jw_cad
+// writes its records through CArchive without padding, so the JWW parsers
+// read them packed and do not call Align.
+func (r *Reader) Align(n int) error {
+	if n <= 1 {
+		return nil
+	}
+	if rem := int(r.bytesRead % int64(n)); rem != 0 {
+		return r.Skip(n - rem)
+	}
+	return nil
+}
+
+// readScratch reads exactly n bytes into the reader's scratch buffer and
+// returns them. The result is only valid until the next read.
+//
+// A corrupt length can claim up to 4 GiB, so the buffer grows at most
+// scratchChunk bytes beyond the data actually read, and a short input fails
+// with io.ErrUnexpectedEOF before anything close to n is allocated.
+func (r *Reader) readScratch(n int) ([]byte, error) {
+	buf := r.scratch[:0]
+	for len(buf) < n {
+		want := min(n, len(buf)+scratchChunk)
+		if cap(buf) < want {
+			grown := make([]byte, len(buf), max(want, 2*cap(buf)))
+			copy(grown, buf)
+			buf = grown
+		}
+		read, err := io.ReadFull(r.r, buf[len(buf):want])
+		r.bytesRead += int64(read)
+		buf = buf[:len(buf)+read]
+		if err != nil {
+			if err == io.EOF && len(buf) > 0 {
+				err = io.ErrUnexpectedEOF // ended inside an earlier chunk
+			}
+			r.scratch = buf
+			return nil, r.fail(err)
+		}
+	}
+	r.scratch = buf
+	return buf, nil
+}
+
+// Err returns the first error any read method returned, or nil.
+// Parsers that ignore individual read errors check it once per record,
+// so truncated data is reported instead of parsed as zero values.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// fail records err as the reader's first error, if it is not nil, and returns it.
+func (r *Reader) fail(err error) error {
+	if err != nil && r.err == nil {
+		r.err = err
+	}
 	return err
 }
 
@@ -197,12 +417,6 @@ func (r *Reader) BytesRead() int64 {
 	return r.bytesRead
 }
 
-// float64FromBits converts a uint64 bit pattern to a float64 value.
-// This uses unsafe pointer conversion to reinterpret the bits as a float64.
-func float64FromBits(bits uint64) float64 {
-	return *(*float64)(unsafe.Pointer(&bits))
-}
-
 // shiftJISToUTF8 converts Shift-JIS encoded bytes to a UTF-8 string.
 // Shift-JIS is the legacy Japanese character encoding used by JWW files.
 // Null bytes are trimmed from the result.
@@ -2,6 +2,9 @@ package jww
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
 	"math"
 	"testing"
 )
@@ -128,6 +131,74 @@ func TestReader_ReadDouble(t *testing.T) {
 	}
 }
 
+func TestReader_ReadFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected float64
+	}{
+		{"zero", []byte{0, 0, 0, 0}, 0},
+		{"one", []byte{0, 0, 0x80, 0x3F}, 1.0},
+		{"half", []byte{0, 0, 0, 0x3F}, 0.5},
+		{"negative two", []byte{0, 0, 0, 0xC0}, -2.0},
+		{"one tenth", []byte{0xCD, 0xCC, 0xCC, 0x3D}, float64(float32(0.1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(tt.data))
+			val, err := r.ReadFloat()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if val != tt.expected {
+				t.Errorf("got %v, want %v", val, tt.expected)
+			}
+			if r.BytesRead() != 4 {
+				t.Errorf("BytesRead: got %d, want 4", r.BytesRead())
+			}
+		})
+	}
+}
+
+func TestReader_ReadDoubles(t *testing.T) {
+	var data []byte
+	for _, v := range []float64{0, 1, -1.5, math.Pi, 1e300} {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+
+	single := NewReader(bytes.NewReader(data))
+	batch := NewReader(bytes.NewReader(data))
+
+	got, err := batch.ReadDoubles(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("got %d values, want 4", len(got))
+	}
+	for i, v := range got {
+		want, err := single.ReadDouble()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v != want {
+			t.Errorf("value %d: got %v, want %v", i, v, want)
+		}
+	}
+	if batch.BytesRead() != single.BytesRead() {
+		t.Errorf("BytesRead: got %d, want %d", batch.BytesRead(), single.BytesRead())
+	}
+
+	// Only one double remains
+	if _, err := batch.ReadDoubles(2); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if batch.Err() == nil {
+		t.Error("expected the short read to be recorded by Err")
+	}
+}
+
 func TestReader_ReadCString_Short(t *testing.T) {
 	// Short string (length < 255): 1-byte length prefix
 	// "test" in Shift-JIS (ASCII compatible for basic chars)
@@ -155,6 +226,31 @@ func TestReader_ReadCString_Empty(t *testing.T) {
 	}
 }
 
+func TestReader_ReadCString_MaxStringLen(t *testing.T) {
+	// Corrupt 4-byte length: 0xFF, 0xFFFF markers, then ~4 GB with no data
+	data := []byte{0xFF, 0xFF, 0xFF, 0xF0, 0xFF, 0xFF, 0xFF}
+	r := NewReader(bytes.NewReader(data))
+	r.SetMaxStringLen(1024)
+
+	_, err := r.ReadCString()
+	if !errors.Is(err, ErrStringTooLong) {
+		t.Fatalf("got error %v, want ErrStringTooLong", err)
+	}
+	if !errors.Is(r.Err(), ErrStringTooLong) {
+		t.Errorf("Err: got %v, want ErrStringTooLong", r.Err())
+	}
+	if got := r.BytesRead(); got != int64(len(data)) {
+		t.Errorf("read %d bytes, want only the %d byte length prefix", got, len(data))
+	}
+
+	// Strings up to the limit are read
+	r = NewReader(bytes.NewReader([]byte{4, 't', 'e', 's', 't'}))
+	r.SetMaxStringLen(4)
+	if val, err := r.ReadCString(); err != nil || val != "test" {
+		t.Errorf("at the limit: got %q, %v, want %q", val, err, "test")
+	}
+}
+
 func TestReader_ReadCString_Medium(t *testing.T) {
 	// Medium string (length >= 255): 0xFF prefix + 2-byte length
 	// Create a 300-byte string
@@ -180,6 +276,41 @@ func TestReader_ReadCString_Medium(t *testing.T) {
 	}
 }
 
+func TestReader_ReadStringFixed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		n    int
+		want string
+	}{
+		// "図面" in Shift-JIS, padded with nulls and spaces to 8 bytes
+		{"padded", []byte{0x90, 0x7D, 0x96, 0xCA, ' ', 0, 0, 0, 'x'}, 8, "図面"},
+		{"full", []byte{'M', 'S', ' ', 'P', 'G', 'O', 'T', 'H', 'x'}, 8, "MS PGOTH"},
+		{"empty", []byte{0, 0, 0, 0, 'x'}, 4, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(tt.data))
+			got, err := r.ReadStringFixed(tt.n)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			// The reader must be positioned right after the field.
+			if next, _ := r.ReadBYTE(); next != 'x' {
+				t.Errorf("next byte = %q, want 'x'", next)
+			}
+		})
+	}
+
+	r := NewReader(bytes.NewReader([]byte{'a', 'b'}))
+	if _, err := r.ReadStringFixed(4); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("short read: got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
 func TestReader_ReadBytes(t *testing.T) {
 	data := []byte{1, 2, 3, 4, 5}
 	r := NewReader(bytes.NewReader(data))
@@ -215,6 +346,94 @@ func TestReader_Skip(t *testing.T) {
 	}
 }
 
+func TestReader_Skip_Large(t *testing.T) {
+	// Skips longer than one scratch chunk are discarded in several reads
+	n := 3*scratchChunk + 17
+	data := make([]byte, n+1)
+	data[n] = 42
+	r := NewReader(bytes.NewReader(data))
+
+	if err := r.Skip(n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.BytesRead() != int64(n) {
+		t.Errorf("BytesRead: got %d, want %d", r.BytesRead(), n)
+	}
+	val, err := r.ReadBYTE()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val != 42 {
+		t.Errorf("got %d, want 42", val)
+	}
+
+	if err := r.Skip(1); err != io.EOF {
+		t.Errorf("skip at end: expected io.EOF, got %v", err)
+	}
+}
+
+func TestReader_Skip_Short(t *testing.T) {
+	r := NewReader(bytes.NewReader(make([]byte, scratchChunk+1)))
+	if err := r.Skip(scratchChunk + 2); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestReader_Align(t *testing.T) {
+	tests := []struct {
+		start, n int
+		want     int64
+	}{
+		{0, 4, 0},
+		{1, 4, 4},
+		{3, 4, 4},
+		{4, 4, 4},
+		{5, 2, 6},
+		{6, 2, 6},
+		{7, 8, 8},
+		{9, 8, 16},
+		{3, 1, 3},
+		{3, 0, 3},
+	}
+	for _, tt := range tests {
+		data := make([]byte, 32)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		r := NewReader(bytes.NewReader(data))
+		if err := r.Skip(tt.start); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Align(tt.n); err != nil {
+			t.Fatalf("Align(%d) from %d: unexpected error: %v", tt.n, tt.start, err)
+		}
+		if r.BytesRead() != tt.want {
+			t.Errorf("Align(%d) from %d: BytesRead got %d, want %d", tt.n, tt.start, r.BytesRead(), tt.want)
+		}
+		if val, _ := r.ReadBYTE(); int64(val) != tt.want {
+			t.Errorf("Align(%d) from %d: next byte got %d, want %d", tt.n, tt.start, val, tt.want)
+		}
+	}
+
+	// Padding past the end of the input is an error
+	r := NewReader(bytes.NewReader([]byte{1, 2, 3}))
+	r.Skip(3)
+	if err := r.Align(4); err != io.EOF {
+		t.Errorf("align at end: expected io.EOF, got %v", err)
+	}
+}
+
+func TestReader_ReadCString_ReusesScratch(t *testing.T) {
+	// Strings returned earlier must not change when the buffer is reused
+	data := []byte{3, 'a', 'b', 'c', 2, 'x', 'y'}
+	r := NewReader(bytes.NewReader(data))
+	first, _ := r.ReadCString()
+	second, _ := r.ReadCString()
+	if first != "abc" || second != "xy" {
+		t.Errorf("got %q, %q; want \"abc\", \"xy\"", first, second)
+	}
+}
+
 func TestReader_ReadSignature_Valid(t *testing.T) {
 	data := []byte("JwwData.")
 	r := NewReader(bytes.NewReader(data))
@@ -232,3 +451,108 @@ func TestReader_ReadSignature_Invalid(t *testing.T) {
 		t.Errorf("expected ErrInvalidSignature, got: %v", err)
 	}
 }
+
+func TestReader_PeekThenRead(t *testing.T) {
+	r := NewReader(bytes.NewReader([]byte{1, 2, 3, 4, 5, 6}))
+
+	peeked, err := r.Peek(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.BytesRead() != 0 {
+		t.Errorf("expected Peek not to advance, bytesRead = %d", r.BytesRead())
+	}
+
+	buf := make([]byte, 4)
+	if err := r.ReadBytes(buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(peeked, buf) {
+		t.Errorf("peeked %v, then read %v", peeked, buf)
+	}
+
+	val, err := r.ReadWORD()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val != 0x0605 {
+		t.Errorf("expected 0x0605 after peeked bytes, got 0x%04X", val)
+	}
+}
+
+func TestReader_PeekAcrossBuffer(t *testing.T) {
+	r := NewReader(bytes.NewReader([]byte{1, 2, 3, 4, 5, 6}))
+
+	// Grow the peek buffer in steps, then read part of it and peek past it
+	if _, err := r.Peek(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Peek(4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, err := r.ReadBYTE(); err != nil || b != 1 {
+		t.Fatalf("ReadBYTE: got %d, %v; want 1", b, err)
+	}
+
+	peeked, err := r.Peek(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(peeked, []byte{2, 3, 4, 5, 6}) {
+		t.Errorf("expected [2 3 4 5 6], got %v", peeked)
+	}
+
+	// Peeking beyond the end returns what is available and an error
+	peeked, err = r.Peek(10)
+	if err == nil {
+		t.Error("expected error peeking past end of data")
+	}
+	if !bytes.Equal(peeked, []byte{2, 3, 4, 5, 6}) {
+		t.Errorf("expected available bytes [2 3 4 5 6], got %v", peeked)
+	}
+
+	dword, err := r.ReadDWORD()
+	if err != nil || dword != 0x05040302 {
+		t.Errorf("ReadDWORD: got 0x%08X, %v; want 0x05040302", dword, err)
+	}
+}
+
+func TestReader_PeekNegative(t *testing.T) {
+	r := NewReader(bytes.NewReader([]byte{1, 2}))
+
+	peeked, err := r.Peek(-1)
+	if err == nil || peeked != nil {
+		t.Fatalf("Peek(-1): got %v, %v; want nil and an error", peeked, err)
+	}
+	if r.Err() == nil {
+		t.Error("expected the error to be recorded in Err")
+	}
+}
+
+// benchmarkStrings is 1000 CStrings of 40 bytes, as in a text-heavy drawing.
+var benchmarkStrings = bytes.Repeat(append([]byte{40}, bytes.Repeat([]byte{'a'}, 40)...), 1000)
+
+func BenchmarkReader_ReadCString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := NewReader(bytes.NewReader(benchmarkStrings))
+		for j := 0; j < 1000; j++ {
+			if _, err := r.ReadCString(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkReader_Skip(b *testing.B) {
+	b.ReportAllocs()
+	data := make([]byte, 1000*16)
+	for i := 0; i < b.N; i++ {
+		r := NewReader(bytes.NewReader(data))
+		for j := 0; j < 1000; j++ {
+			if err := r.Skip(16); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package jww
+
+import (
+	"fmt"
+	"io"
+)
+
+// Write serializes a Document to w in JWW format, mirroring what Parse reads:
+// the signature, version, memo, paper size, layer groups, and the entity list
+// using MFC CArchive PID tracking, followed by an empty block definition list.
+//
+// Only Line, Arc, Point, Text, and Solid entities are supported so far;
+// documents containing other entity types or block definitions return an
+// error. The other header settings, including the LineTypes Parse reads, are
+// not written, so Write is meant for round-trip testing rather than producing
+// files for Jw_cad.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	if err := jww.Write(&buf, doc); err != nil {
+//	    return err
+//	}
+//	reparsed, err := jww.Parse(&buf)
+func Write(w io.Writer, doc *Document) error {
+	jw := NewWriter(w)
+	version := doc.Version
+
+	if err := jw.WriteSignature(); err != nil {
+		return err
+	}
+	if err := jw.WriteDWORD(version); err != nil {
+		return fmt.Errorf("writing version: %w", err)
+	}
+	if err := jw.WriteCString(doc.Memo); err != nil {
+		return fmt.Errorf("writing memo: %w", err)
+	}
+	if err := jw.WriteDWORD(doc.PaperSize); err != nil {
+		return fmt.Errorf("writing paper size: %w", err)
+	}
+	if err := jw.WriteDWORD(doc.WriteLayerGroup); err != nil {
+		return fmt.Errorf("writing write layer group: %w", err)
+	}
+
+	for gLay := range doc.LayerGroups {
+		if err := writeLayerGroup(jw, &doc.LayerGroups[gLay]); err != nil {
+			return fmt.Errorf("writing layer group %d: %w", gLay, err)
+		}
+	}
+
+	if err := writeEntityList(jw, doc.Entities, version); err != nil {
+		return fmt.Errorf("writing entity list: %w", err)
+	}
+
+	if len(doc.BlockDefs) > 0 {
+		return fmt.Errorf("writing block definitions is not supported")
+	}
+	if err := jw.WriteDWORD(0); err != nil {
+		return fmt.Errorf("writing block def count: %w", err)
+	}
+
+	if version >= 700 {
+		// Ver.7.00+ files end with the embedded image count
+		if err := jw.WriteDWORD(0); err != nil {
+			return fmt.Errorf("writing image count: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeLayerGroup writes a layer group and its 16 layers.
+func writeLayerGroup(jw *Writer, lg *LayerGroup) error {
+	if err := jw.WriteDWORD(lg.State); err != nil {
+		return err
+	}
+	if err := jw.WriteDWORD(lg.WriteLayer); err != nil {
+		return err
+	}
+	if err := jw.WriteDouble(lg.Scale); err != nil {
+		return err
+	}
+	if err := jw.WriteDWORD(lg.Protect); err != nil {
+		return err
+	}
+	for lay := range lg.Layers {
+		if err := jw.WriteDWORD(lg.Layers[lay].State); err != nil {
+			return err
+		}
+		if err := jw.WriteDWORD(lg.Layers[lay].Protect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEntityList writes the entity count followed by each entity using
+// MFC CArchive PID tracking: the first object of a class is preceded by the
+// class definition (0xFFFF, schema, name), later ones by 0x8000 | class PID.
+// Class definitions and objects each take the next PID.
+func writeEntityList(jw *Writer, entities []Entity, version uint32) error {
+	if len(entities) >= 0xFFFF {
+		return fmt.Errorf("too many entities for a WORD count: %d", len(entities))
+	}
+	if err := jw.WriteWORD(uint16(len(entities))); err != nil {
+		return fmt.Errorf("writing entity count: %w", err)
+	}
+
+	classToPID := make(map[string]uint32)
+	nextPID := uint32(1)
+
+	for i, e := range entities {
+		className, err := classNameOf(e)
+		if err != nil {
+			return fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		if pid, ok := classToPID[className]; ok {
+			err = jw.WriteWORD(uint16(0x8000 | pid))
+		} else {
+			err = writeClassDef(jw, className, version)
+			classToPID[className] = nextPID
+			nextPID++
+		}
+		if err != nil {
+			return fmt.Errorf("entity %d: writing class: %w", i, err)
+		}
+
+		if err := writeEntity(jw, e, version); err != nil {
+			return fmt.Errorf("entity %d (%s): %w", i, className, err)
+		}
+		nextPID++
+	}
+
+	return nil
+}
+
+// writeClassDef writes a new class definition: the 0xFFFF marker, the
+// schema number (the file version), and the class name.
+func writeClassDef(jw *Writer, className string, version uint32) error {
+	if err := jw.WriteWORD(0xFFFF); err != nil {
+		return err
+	}
+	if err := jw.WriteWORD(uint16(version)); err != nil {
+		return err
+	}
+	if err := jw.WriteWORD(uint16(len(className))); err != nil {
+		return err
+	}
+	return jw.WriteBytes([]byte(className))
+}
+
+// classNameOf returns the MFC class an entity is serialized as.
+func classNameOf(e Entity) (string, error) {
+	switch e.(type) {
+	case *Line:
+		return "CDataSen", nil
+	case *Arc:
+		return "CDataEnko", nil
+	case *Point:
+		return "CDataTen", nil
+	case *Text:
+		return "CDataMoji", nil
+	case *Solid:
+		return "CDataSolid", nil
+	case *Dimension:
+		return "CDataSunpou", nil
+	}
+	return "", fmt.Errorf("writing %s entities is not supported", e.Type())
+}
+
+// writeEntity writes the object data of a supported entity.
+func writeEntity(jw *Writer, e Entity, version uint32) error {
+	if err := writeEntityBase(jw, e.Base(), version); err != nil {
+		return err
+	}
+
+	switch v := e.(type) {
+	case *Line:
+		return writeDoubles(jw, v.StartX, v.StartY, v.EndX, v.EndY)
+
+	case *Arc:
+		if err := writeDoubles(jw, v.CenterX, v.CenterY, v.Radius,
+			v.StartAngle, v.ArcAngle, v.TiltAngle, v.Flatness); err != nil {
+			return err
+		}
+		return jw.WriteDWORD(boolDWORD(v.IsFullCircle))
+
+	case *Point:
+		if err := writeDoubles(jw, v.X, v.Y); err != nil {
+			return err
+		}
+		if err := jw.WriteDWORD(boolDWORD(v.IsTemporary)); err != nil {
+			return err
+		}
+		if v.PenStyle == 100 {
+			if err := jw.WriteDWORD(v.Code); err != nil {
+				return err
+			}
+			return writeDoubles(jw, v.Angle, v.Scale)
+		}
+		return nil
+
+	case *Text:
+		if err := writeDoubles(jw, v.StartX, v.StartY, v.EndX, v.EndY); err != nil {
+			return err
+		}
+		if err := jw.WriteDWORD(v.TextType); err != nil {
+			return err
+		}
+		if err := writeDoubles(jw, v.SizeX, v.SizeY, v.Spacing, v.Angle); err != nil {
+			return err
+		}
+		if err := jw.WriteCString(v.FontName); err != nil {
+			return err
+		}
+		return jw.WriteCString(v.Content)
+
+	case *Solid:
+		// Stored in the order 1, 4, 2, 3 (see parseSolid)
+		if err := writeDoubles(jw, v.Point1X, v.Point1Y, v.Point4X, v.Point4Y,
+			v.Point2X, v.Point2Y, v.Point3X, v.Point3Y); err != nil {
+			return err
+		}
+		if v.PenColor == 10 {
+			return jw.WriteDWORD(v.Color)
+		}
+		return nil
+
+	case *Dimension:
+		// The member records carry their own entity bases (see parseDimension)
+		if err := writeEntity(jw, &v.Line, version); err != nil {
+			return err
+		}
+		if err := writeEntity(jw, &v.Text, version); err != nil {
+			return err
+		}
+		if version < 420 {
+			return nil
+		}
+		if err := jw.WriteWORD(v.SXFMode); err != nil {
+			return err
+		}
+		for i := range v.ExtensionLines {
+			if err := writeEntity(jw, &v.ExtensionLines[i], version); err != nil {
+				return err
+			}
+		}
+		for _, p := range []*Point{&v.EndPoints[0], &v.EndPoints[1], &v.AuxPoints[0], &v.AuxPoints[1]} {
+			if err := writeEntity(jw, p, version); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("writing %s entities is not supported", e.Type())
+}
+
+// writeEntityBase writes the common entity attributes read by parseEntityBase.
+func writeEntityBase(jw *Writer, base *EntityBase, version uint32) error {
+	if err := jw.WriteDWORD(base.Group); err != nil {
+		return err
+	}
+	if err := jw.WriteBYTE(base.PenStyle); err != nil {
+		return err
+	}
+	if err := jw.WriteWORD(base.PenColor); err != nil {
+		return err
+	}
+	if version >= 351 {
+		if err := jw.WriteWORD(base.PenWidth); err != nil {
+			return err
+		}
+	}
+	if err := jw.WriteWORD(base.Layer); err != nil {
+		return err
+	}
+	if err := jw.WriteWORD(base.LayerGroup); err != nil {
+		return err
+	}
+	return jw.WriteWORD(base.Flag)
+}
+
+// writeDoubles writes each value as a double.
+func writeDoubles(jw *Writer, values ...float64) error {
+	for _, v := range values {
+		if err := jw.WriteDouble(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// boolDWORD returns 1 for true and 0 for false.
+func boolDWORD(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package jww
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTrip writes doc and parses the result.
+func roundTrip(t *testing.T, doc *Document) *Document {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	reparsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse of written data failed: %v", err)
+	}
+	if len(doc.Entities) > 0 && doc.Entities[0].Base().ID == 0 {
+		// IDs are assigned by Parse; documents built in code have none
+		for _, e := range reparsed.Entities {
+			e.Base().ID = 0
+		}
+	}
+	return reparsed
+}
+
+func TestWrite_RoundTripMinimal(t *testing.T) {
+	doc, err := Parse(bytes.NewReader(createMinimalJWWData()))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	reparsed := roundTrip(t, doc)
+
+	if reparsed.Version != doc.Version || reparsed.Memo != doc.Memo {
+		t.Errorf("header: got version %d memo %q, want %d %q",
+			reparsed.Version, reparsed.Memo, doc.Version, doc.Memo)
+	}
+	if reparsed.LayerGroups != doc.LayerGroups {
+		t.Errorf("layer groups differ after round trip")
+	}
+	if !reflect.DeepEqual(reparsed.Entities, doc.Entities) {
+		t.Errorf("entities: got %+v, want %+v", reparsed.Entities, doc.Entities)
+	}
+}
+
+func TestWrite_RoundTripEntities(t *testing.T) {
+	base := EntityBase{PenStyle: 1, PenColor: 2, PenWidth: 1, Layer: 3, LayerGroup: 4}
+	doc := &Document{Version: 700, Memo: "テスト", PaperSize: 3}
+	for i := range doc.LayerGroups {
+		doc.LayerGroups[i].Scale = 100
+	}
+	doc.Entities = []Entity{
+		&Line{EntityBase: base, StartX: 1, StartY: 2, EndX: 3, EndY: 4},
+		&Arc{EntityBase: base, CenterX: 5, CenterY: 6, Radius: 7, ArcAngle: 1.5, Flatness: 1},
+		&Point{EntityBase: EntityBase{PenStyle: 100, PenColor: 1}, X: 8, Y: 9, Code: 2, Angle: 0.5, Scale: 2},
+		&Text{EntityBase: base, StartX: 10, StartY: 11, SizeX: 3, SizeY: 3, FontName: "ＭＳ ゴシック", Content: "平面図"},
+		&Solid{EntityBase: EntityBase{PenColor: 10}, Point1X: 1, Point2X: 2, Point3X: 3, Point4X: 4, Point3Y: 5, Color: 0xFF0000},
+		&Line{EntityBase: base, StartX: -1, EndY: 12.5},
+		&Dimension{
+			EntityBase:     base,
+			Line:           Line{EntityBase: base, StartX: 0, EndX: 100},
+			Text:           Text{EntityBase: base, StartX: 45, StartY: 1, SizeY: 2.5, Content: "100"},
+			SXFMode:        1,
+			ExtensionLines: [2]Line{{EndY: -10}, {StartX: 100, EndX: 100, EndY: -10}},
+			EndPoints:      [2]Point{{X: 0}, {X: 100}},
+			AuxPoints:      [2]Point{{Y: -10}, {X: 100, Y: -10}},
+		},
+	}
+
+	reparsed := roundTrip(t, doc)
+
+	if reparsed.Memo != "テスト" {
+		t.Errorf("memo: got %q, want テスト", reparsed.Memo)
+	}
+	if schema := reparsed.ClassSchemas["CDataSen"]; schema != 700 {
+		t.Errorf("CDataSen schema: got %d, want 700", schema)
+	}
+	if len(reparsed.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", reparsed.Warnings)
+	}
+	if !reflect.DeepEqual(reparsed.Entities, doc.Entities) {
+		for i := range doc.Entities {
+			if i >= len(reparsed.Entities) || !reflect.DeepEqual(reparsed.Entities[i], doc.Entities[i]) {
+				t.Errorf("entity %d differs after round trip", i)
+			}
+		}
+	}
+}
+
+func TestWrite_UnsupportedEntity(t *testing.T) {
+	doc := &Document{Version: 600, Entities: []Entity{&Block{DefNumber: 1}}}
+
+	err := Write(&bytes.Buffer{}, doc)
+	if err == nil || !strings.Contains(err.Error(), "BLOCK") {
+		t.Errorf("expected unsupported BLOCK error, got %v", err)
+	}
+}
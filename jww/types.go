@@ -1,5 +1,11 @@
 package jww
 
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
 // Document represents a complete JWW (Jw_cad) file structure.
 // JWW files are binary CAD files used by Jw_cad, a popular Japanese CAD software.
 // The document contains layer information, drawing entities, and optional block definitions.
@@ -16,6 +22,11 @@ type Document struct {
 	// WriteLayerGroup is the currently active layer group for writing (0-15).
 	WriteLayerGroup uint32
 
+	// LineTypes holds the user-defined line types of the SXF extended line
+	// type table (Ver.4.20 and later), which entities refer to by pen
+	// style. Undefined table entries are left out.
+	LineTypes []LineTypeDef
+
 	// LayerGroups contains 16 layer groups, each with 16 layers.
 	// This provides a total of 256 possible layers organized in a hierarchical structure.
 	LayerGroups [16]LayerGroup
@@ -25,6 +36,21 @@ type Document struct {
 
 	// BlockDefs contains block definitions that can be referenced by block insert entities.
 	BlockDefs []BlockDef
+
+	// Groups lists the entities sharing each curve attribute number
+	// (EntityBase.Group), which Jw_cad uses to select and edit them as one
+	// object, in ascending number order. Parse fills it from Entities.
+	Groups []EntityGroup
+
+	// Warnings contains non-fatal problems noticed while parsing, such as
+	// unparsed data remaining after the block definition list or class
+	// schemas that differ from the file version.
+	Warnings []string
+
+	// ClassSchemas maps each MFC class name defined in the file (e.g.
+	// "CDataSen") to the schema number stored with its definition. Jw_cad
+	// writes the file version here; other values point to format drift.
+	ClassSchemas map[string]uint16
 }
 
 // LayerGroup represents a layer group (レイヤグループ) in a JWW file.
@@ -44,6 +70,16 @@ type LayerGroup struct {
 	// Protect is the protection flag to prevent accidental modifications.
 	Protect uint32
 
+	// InferredPenColor is the pen color most used by the group's lines and
+	// arcs (0 if the group has none). JWW files store no default color
+	// per layer group, so this is a heuristic computed by the parser, not a
+	// value read from the file.
+	InferredPenColor uint16
+
+	// InferredPenStyle is the pen style most used by the group's lines and
+	// arcs (0 if the group has none), a heuristic like InferredPenColor.
+	InferredPenStyle byte
+
 	// Layers contains the 16 layers within this layer group.
 	Layers [16]Layer
 
@@ -65,10 +101,22 @@ type Layer struct {
 	Name string
 }
 
+// EntityGroup is a set of entities sharing a curve attribute number
+// (曲線属性番号), such as the segments of a curve or a grouped object.
+type EntityGroup struct {
+	// Number is the curve attribute number stored in EntityBase.Group.
+	Number uint32
+
+	// Entities holds the indices of the member entities in
+	// Document.Entities, in ascending order.
+	Entities []int
+}
+
 // EntityBase contains common attributes shared by all JWW drawing entities.
 // These attributes control appearance properties like line type, color, and layer assignment.
 type EntityBase struct {
-	// Group is the curve attribute number (線種グループ).
+	// Group is the curve attribute number (曲線属性番号), shared by the
+	// entities Jw_cad treats as one object; 0 means none.
 	Group uint32
 
 	// PenStyle is the line type number (線種).
@@ -86,8 +134,19 @@ type EntityBase struct {
 	// LayerGroup is the layer group number (0-15).
 	LayerGroup uint16
 
-	// Flag contains various attribute flags for the entity.
+	// Flag contains various attribute flags for the entity, such as 0x2000
+	// for lines that are part of a dimension. No flag marks
+	// coordinates as real-world or paper size: coordinates are always
+	// real-world millimeters (実寸), while text sizes are paper millimeters
+	// (図寸), which the layer group's Scale converts to real-world size.
 	Flag uint16
+
+	// ID is the MFC archive PID Parse assigned to the entity's object. PIDs
+	// increase through the file but skip the PIDs of class definitions, and
+	// continue from the main entity list into the block definitions and
+	// their entities, so IDs are unique archive-wide. It is 0 for entities
+	// not read by Parse and is not written by Write.
+	ID uint32
 }
 
 // Entity is the interface implemented by all JWW drawing entities.
@@ -101,6 +160,24 @@ type Entity interface {
 	Type() string
 }
 
+// EntitiesOfType returns the entities of type T in doc.Entities, in file order.
+// Block definition entities are not included.
+//
+// Example:
+//
+//	for _, txt := range jww.EntitiesOfType[*jww.Text](doc) {
+//	    fmt.Println(txt.Content)
+//	}
+func EntitiesOfType[T Entity](doc *Document) []T {
+	var typed []T
+	for _, entity := range doc.Entities {
+		if e, ok := entity.(T); ok {
+			typed = append(typed, e)
+		}
+	}
+	return typed
+}
+
 // Line represents a straight line segment entity (JWW class: CDataSen).
 // Lines are defined by their start and end points in 2D space.
 type Line struct {
@@ -198,7 +275,9 @@ type Text struct {
 	// TextType contains text style flags: +10000 for italic, +20000 for bold.
 	TextType uint32
 
-	// SizeX is the character width.
+	// SizeX is the character width, and SizeY the character height, in
+	// paper millimeters (図寸). Multiply them by the layer group's Scale
+	// for the size in drawing coordinates.
 	SizeX, SizeY float64
 
 	// Spacing is the character spacing factor.
@@ -220,6 +299,43 @@ func (t *Text) Base() *EntityBase { return &t.EntityBase }
 // Type returns "TEXT".
 func (t *Text) Type() string { return "TEXT" }
 
+// imageDataPrefix marks text content that is an image data string.
+const imageDataPrefix = "^@BM"
+
+// ImageReference is the placement information of an image.
+// JWW stores images as text entities whose content is an image data string:
+// "^@BM" followed by the file name, drawn width and drawn height separated
+// by commas, with optional trimming fields after them.
+type ImageReference struct {
+	// Path is the image file name (embedded images use a "%temp%" prefix).
+	Path string
+
+	// Width, Height are the drawn size of the image.
+	Width, Height float64
+}
+
+// Image returns the image placement described by the text's content.
+// ok is false if the content is not an image data string or its size
+// fields cannot be parsed.
+func (t *Text) Image() (ref ImageReference, ok bool) {
+	if !strings.HasPrefix(t.Content, imageDataPrefix) {
+		return ImageReference{}, false
+	}
+	fields := strings.Split(strings.TrimPrefix(t.Content, imageDataPrefix), ",")
+	if len(fields) < 3 {
+		return ImageReference{}, false
+	}
+	width, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+	if err != nil || width <= 0 {
+		return ImageReference{}, false
+	}
+	height, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+	if err != nil || height <= 0 {
+		return ImageReference{}, false
+	}
+	return ImageReference{Path: fields[0], Width: width, Height: height}, true
+}
+
 // Solid represents a solid fill entity (JWW class: CDataSolid).
 // Solids are filled quadrilaterals or triangles used for hatching and shading.
 type Solid struct {
@@ -247,6 +363,123 @@ func (s *Solid) Base() *EntityBase { return &s.EntityBase }
 // Type returns "SOLID".
 func (s *Solid) Type() string { return "SOLID" }
 
+// CircleSolidKind identifies the shape of a circle solid.
+type CircleSolidKind int
+
+// Circle solid shapes, selected by the pen style and the kind value stored
+// in Point3Y (see CircleSolid).
+const (
+	// CircleSolidSector is a pie slice bounded by the arc and two radii.
+	CircleSolidSector CircleSolidKind = iota
+
+	// CircleSolidSegment is the area between the arc and its chord.
+	CircleSolidSegment
+
+	// CircleSolidFull is a filled circle or ellipse.
+	CircleSolidFull
+
+	// CircleSolidOuterArc is the area outside the arc, bounded by the
+	// tangents at its end points (a filled corner of a rounded rectangle).
+	CircleSolidOuterArc
+
+	// CircleSolidAnnulus is a ring between the arc and a concentric inner arc.
+	CircleSolidAnnulus
+
+	// CircleSolidCircumference is a solid along the circumference of a circle or arc.
+	CircleSolidCircumference
+)
+
+// CircleSolid is the circle solid stored in a CDataSolid record with a pen
+// style of 101 or more. Such records reuse the corner fields: Point1 is the
+// center, Point4 holds the radius and flatness, Point2 the tilt and start
+// angles, and Point3 the arc angle and the kind (or the inner radius).
+type CircleSolid struct {
+	// CenterX is the X coordinate of the center point.
+	CenterX, CenterY float64
+
+	// Radius is the outer radius (the semi-major axis for ellipses).
+	Radius float64
+
+	// Flatness is the ratio of minor to major axis (1.0 for circles).
+	Flatness float64
+
+	// TiltAngle is the rotation of the major axis in radians.
+	TiltAngle float64
+
+	// StartAngle is the starting angle in radians.
+	StartAngle float64
+
+	// ArcAngle is the arc sweep in radians. It is 2π for full shapes.
+	ArcAngle float64
+
+	// Kind is the shape of the solid.
+	Kind CircleSolidKind
+
+	// InnerRadius is the inner radius of an annulus.
+	InnerRadius float64
+
+	// EqualWidth reports that an elliptical annulus keeps the same ring
+	// width on both axes (pen style 106) instead of scaling its inner
+	// ellipse with the outer one (pen style 105).
+	EqualWidth bool
+}
+
+// IsCircle reports whether the solid stores a circle solid rather than a
+// quadrilateral. Jw_cad marks circle solids with a pen style of 101 or more.
+func (s *Solid) IsCircle() bool {
+	return s.PenStyle >= 101
+}
+
+// CircleSolid returns the circle solid stored in the record.
+// ok is false if the solid is a quadrilateral.
+func (s *Solid) CircleSolid() (cs CircleSolid, ok bool) {
+	if !s.IsCircle() {
+		return CircleSolid{}, false
+	}
+
+	cs = CircleSolid{
+		CenterX:    s.Point1X,
+		CenterY:    s.Point1Y,
+		Radius:     s.Point4X,
+		Flatness:   s.Point4Y,
+		TiltAngle:  s.Point2X,
+		StartAngle: s.Point2Y,
+		ArcAngle:   s.Point3X,
+	}
+	if cs.Flatness == 0 {
+		cs.Flatness = 1.0
+	}
+
+	full := false
+	switch s.PenStyle {
+	case 105, 106:
+		cs.Kind = CircleSolidAnnulus
+		cs.InnerRadius = s.Point3Y
+		cs.EqualWidth = s.PenStyle == 106
+	case 111:
+		cs.Kind = CircleSolidCircumference
+		full = s.Point3Y == 100
+	default:
+		switch s.Point3Y {
+		case -1:
+			cs.Kind = CircleSolidOuterArc
+		case 5:
+			cs.Kind = CircleSolidSegment
+		case 100:
+			cs.Kind = CircleSolidFull
+			full = true
+		default:
+			cs.Kind = CircleSolidSector
+		}
+	}
+	if full || cs.ArcAngle == 0 {
+		cs.StartAngle = 0
+		cs.ArcAngle = 2 * math.Pi
+	}
+
+	return cs, true
+}
+
 // Block represents a block insert entity (JWW class: CDataBlock).
 // Blocks allow reuse of geometry defined in a BlockDef.
 type Block struct {
@@ -274,6 +507,38 @@ func (b *Block) Base() *EntityBase { return &b.EntityBase }
 // Type returns "BLOCK".
 func (b *Block) Type() string { return "BLOCK" }
 
+// Dimension represents a dimension entity (JWW class: CDataSunpou): a
+// measurement line with its value text. Files from Ver.4.20 on also store
+// the SXF mode, two extension lines, and four points.
+type Dimension struct {
+	EntityBase
+
+	// Line is the measurement line.
+	Line Line
+
+	// Text is the dimension value text.
+	Text Text
+
+	// SXFMode is nonzero for SXF-style dimensions (Ver.4.20+).
+	SXFMode uint16
+
+	// ExtensionLines are the two extension lines (Ver.4.20+).
+	ExtensionLines [2]Line
+
+	// EndPoints are the ends of the measurement line, where the arrowheads
+	// are drawn (Ver.4.20+).
+	EndPoints [2]Point
+
+	// AuxPoints are the two auxiliary points (Ver.4.20+).
+	AuxPoints [2]Point
+}
+
+// Base returns the entity's base attributes.
+func (d *Dimension) Base() *EntityBase { return &d.EntityBase }
+
+// Type returns "DIMENSION".
+func (d *Dimension) Type() string { return "DIMENSION" }
+
 // BlockDef represents a block definition (JWW class: CDataList).
 // Block definitions are reusable collections of entities that can be inserted
 // multiple times via Block entities.
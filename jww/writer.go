@@ -0,0 +1,120 @@
+package jww
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// Writer wraps an io.Writer to provide convenient methods for writing JWW binary data.
+// It is the counterpart of Reader: all multi-byte values are written in
+// little-endian format, and text strings are encoded from UTF-8 to Shift-JIS.
+type Writer struct {
+	w            io.Writer
+	buf          []byte
+	bytesWritten int64
+}
+
+// NewWriter creates a new JWW binary writer that outputs to the provided io.Writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:   w,
+		buf: make([]byte, 8),
+	}
+}
+
+// WriteSignature writes the 8-byte JWW file signature "JwwData.".
+func (w *Writer) WriteSignature() error {
+	return w.WriteBytes([]byte("JwwData."))
+}
+
+// WriteDWORD writes a 32-bit unsigned integer in little-endian format.
+func (w *Writer) WriteDWORD(v uint32) error {
+	binary.LittleEndian.PutUint32(w.buf[:4], v)
+	return w.WriteBytes(w.buf[:4])
+}
+
+// WriteWORD writes a 16-bit unsigned integer in little-endian format.
+func (w *Writer) WriteWORD(v uint16) error {
+	binary.LittleEndian.PutUint16(w.buf[:2], v)
+	return w.WriteBytes(w.buf[:2])
+}
+
+// WriteBYTE writes a single unsigned byte.
+func (w *Writer) WriteBYTE(v byte) error {
+	w.buf[0] = v
+	return w.WriteBytes(w.buf[:1])
+}
+
+// WriteDouble writes a 64-bit IEEE 754 floating point number in little-endian format.
+func (w *Writer) WriteDouble(v float64) error {
+	binary.LittleEndian.PutUint64(w.buf[:8], math.Float64bits(v))
+	return w.WriteBytes(w.buf[:8])
+}
+
+// WriteCString writes a length-prefixed string in MFC CString format,
+// using the same length prefix rules ReadCString accepts:
+//   - If length < 255: 1 byte length prefix
+//   - If length < 65535: 1 byte 0xFF marker + 2 byte length
+//   - Otherwise: 1 byte 0xFF marker + 2 byte 0xFFFF marker + 4 byte length
+//
+// The string is converted from UTF-8 to Shift-JIS; characters Shift-JIS
+// cannot represent are written as '?'.
+func (w *Writer) WriteCString(s string) error {
+	data := utf8ToShiftJIS(s)
+
+	var err error
+	switch n := len(data); {
+	case n < 0xFF:
+		err = w.WriteBYTE(byte(n))
+	case n < 0xFFFF:
+		if err = w.WriteBYTE(0xFF); err == nil {
+			err = w.WriteWORD(uint16(n))
+		}
+	default:
+		if err = w.WriteBYTE(0xFF); err == nil {
+			if err = w.WriteWORD(0xFFFF); err == nil {
+				err = w.WriteDWORD(uint32(n))
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return w.WriteBytes(data)
+}
+
+// WriteBytes writes buf unchanged.
+func (w *Writer) WriteBytes(buf []byte) error {
+	n, err := w.w.Write(buf)
+	w.bytesWritten += int64(n)
+	return err
+}
+
+// BytesWritten returns the total number of bytes written to the underlying writer.
+func (w *Writer) BytesWritten() int64 {
+	return w.bytesWritten
+}
+
+// utf8ToShiftJIS converts a UTF-8 string to Shift-JIS encoded bytes.
+// Characters Shift-JIS cannot represent (and invalid UTF-8) become '?'.
+func utf8ToShiftJIS(s string) []byte {
+	encoder := japanese.ShiftJIS.NewEncoder()
+	if result, err := encoder.Bytes([]byte(s)); err == nil {
+		return result
+	}
+
+	// Encode rune by rune to replace only the unsupported characters
+	var result []byte
+	for _, r := range s {
+		b, err := encoder.Bytes([]byte(string(r)))
+		if err != nil {
+			b = []byte{'?'}
+		}
+		result = append(result, b...)
+	}
+	return result
+}
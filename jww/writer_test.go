@@ -0,0 +1,83 @@
+package jww
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriter_Primitives(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	_ = w.WriteDWORD(700)
+	_ = w.WriteWORD(0xFFFF)
+	_ = w.WriteBYTE(7)
+	_ = w.WriteDouble(math.Pi)
+
+	want := []byte{188, 2, 0, 0, 255, 255, 7}
+	if !bytes.Equal(buf.Bytes()[:len(want)], want) {
+		t.Errorf("got % x, want % x", buf.Bytes()[:len(want)], want)
+	}
+	if w.BytesWritten() != 15 {
+		t.Errorf("BytesWritten: got %d, want 15", w.BytesWritten())
+	}
+
+	r := NewReader(bytes.NewReader(buf.Bytes()))
+	dw, _ := r.ReadDWORD()
+	wd, _ := r.ReadWORD()
+	b, _ := r.ReadBYTE()
+	d, _ := r.ReadDouble()
+	if dw != 700 || wd != 0xFFFF || b != 7 || d != math.Pi {
+		t.Errorf("read back: got %d %d %d %v", dw, wd, b, d)
+	}
+}
+
+func TestWriter_WriteCString(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		prefix []byte
+	}{
+		{"empty", "", []byte{0}},
+		{"ascii", "Layer0", []byte{6}},
+		{"japanese", "平面図", []byte{6}}, // 2 bytes per character in Shift-JIS
+		{"254 bytes", strings.Repeat("a", 254), []byte{254}},
+		{"255 bytes", strings.Repeat("a", 255), []byte{0xFF, 0xFF, 0x00}},
+		{"long japanese", strings.Repeat("図", 200), []byte{0xFF, 0x90, 0x01}},
+		{"65535 bytes", strings.Repeat("a", 65535), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewWriter(&buf).WriteCString(tt.s); err != nil {
+				t.Fatalf("WriteCString failed: %v", err)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), tt.prefix) {
+				t.Errorf("prefix: got % x, want % x", buf.Bytes()[:len(tt.prefix)], tt.prefix)
+			}
+
+			got, err := NewReader(bytes.NewReader(buf.Bytes())).ReadCString()
+			if err != nil {
+				t.Fatalf("ReadCString failed: %v", err)
+			}
+			if got != tt.s {
+				t.Errorf("round trip: got %d bytes, want %d", len(got), len(tt.s))
+			}
+		})
+	}
+}
+
+func TestWriter_WriteCStringUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).WriteCString("a😀b"); err != nil {
+		t.Fatalf("WriteCString failed: %v", err)
+	}
+
+	got, _ := NewReader(bytes.NewReader(buf.Bytes())).ReadCString()
+	if got != "a?b" {
+		t.Errorf("got %q, want %q", got, "a?b")
+	}
+}
@@ -961,6 +961,10 @@ declare global {
   var jwwParse: ((data: Uint8Array) => WasmResult) | undefined;
   var jwwToDxf: ((data: Uint8Array) => WasmResult) | undefined;
   var jwwToDxfString: ((data: Uint8Array) => WasmResult) | undefined;
+  var jwwToDxfStringWithOptions:
+    | ((data: Uint8Array, options?: Record<string, unknown>) => WasmResult)
+    | undefined;
+  var jwwGetLayers: ((data: Uint8Array) => WasmResult) | undefined;
   var jwwValidate: ((data: Uint8Array) => WasmValidationResult) | undefined;
   var jwwGetVersion: (() => string) | undefined;
   var jwwSetDebug: ((enabled: boolean) => void) | undefined;
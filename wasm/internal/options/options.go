@@ -0,0 +1,59 @@
+// Package options converts the options objects passed to the WASM exports
+// into conversion options. It is kept apart from the js/wasm-only main
+// package so it can be tested on any platform.
+package options
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/f4ah6o/jww-parser/dxf"
+)
+
+// Parse builds conversion options from the JSON encoding of
+// a JS options object such as {"pruneUnusedLayers": true, "curveSegments": 32}.
+// Keys are ConvertOptions field names, matched case-insensitively, so the
+// lower camel case names used in JS work. Keys that name no option, or an
+// option JS cannot set (such as EntityHook), are returned sorted in unknown.
+// An empty string or "null" yields the default options.
+func Parse(data string) (opts dxf.ConvertOptions, unknown []string, err error) {
+	if data == "" || data == "null" {
+		return opts, nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return opts, nil, err
+	}
+
+	known := make(map[string]bool)
+	t := reflect.TypeOf(opts)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		switch f.Type.Kind() {
+		case reflect.Func, reflect.Interface:
+			continue
+		}
+		if f.IsExported() {
+			known[strings.ToLower(f.Name)] = true
+		}
+	}
+
+	settable := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		if known[strings.ToLower(key)] {
+			settable[key] = value
+		} else {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	encoded, err := json.Marshal(settable)
+	if err != nil {
+		return opts, unknown, err
+	}
+	err = json.Unmarshal(encoded, &opts)
+	return opts, unknown, err
+}
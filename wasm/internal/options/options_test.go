@@ -0,0 +1,57 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/f4ah6o/jww-parser/dxf"
+)
+
+func TestParse(t *testing.T) {
+	opts, unknown, err := Parse(`{
+		"pruneUnusedLayers": true,
+		"curveSegments": 16,
+		"lineTypeScale": 0.5,
+		"excludeLayers": ["0-F"],
+		"globalTranslation": {"x": 10, "y": -5},
+		"textAlignment": 1,
+		"applyGroupScale": true,
+		"entityHook": null
+	}`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := dxf.ConvertOptions{
+		PruneUnusedLayers: true,
+		CurveSegments:     16,
+		LineTypeScale:     0.5,
+		ExcludeLayers:     []string{"0-F"},
+		GlobalTranslation: dxf.Vertex{X: 10, Y: -5},
+		TextAlignment:     dxf.TextHAlignCenter,
+	}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("options: got %+v, want %+v", opts, want)
+	}
+	if wantUnknown := []string{"applyGroupScale", "entityHook"}; !reflect.DeepEqual(unknown, wantUnknown) {
+		t.Errorf("unknown: got %v, want %v", unknown, wantUnknown)
+	}
+}
+
+func TestParse_Defaults(t *testing.T) {
+	for _, data := range []string{"", "null", "{}"} {
+		opts, unknown, err := Parse(data)
+		if err != nil || unknown != nil || !reflect.DeepEqual(opts, dxf.ConvertOptions{}) {
+			t.Errorf("%q: got %+v, %v, %v, want default options", data, opts, unknown, err)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, _, err := Parse(`{"curveSegments": "many"}`); err == nil {
+		t.Error("expected an error for a value of the wrong type")
+	}
+	if _, _, err := Parse(`[1, 2]`); err == nil {
+		t.Error("expected an error for a non-object value")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/f4ah6o/jww-parser/dxf"
 	"github.com/f4ah6o/jww-parser/jww"
+	"github.com/f4ah6o/jww-parser/wasm/internal/options"
 )
 
 // Version of the WASM module (overridden at build time via -ldflags)
@@ -26,6 +27,8 @@ func main() {
 	js.Global().Set("jwwParse", js.FuncOf(jwwParse))
 	js.Global().Set("jwwToDxf", js.FuncOf(jwwToDxf))
 	js.Global().Set("jwwToDxfString", js.FuncOf(jwwToDxfString))
+	js.Global().Set("jwwToDxfStringWithOptions", js.FuncOf(jwwToDxfStringWithOptions))
+	js.Global().Set("jwwGetLayers", js.FuncOf(jwwGetLayers))
 	js.Global().Set("jwwGetVersion", js.FuncOf(jwwGetVersion))
 	js.Global().Set("jwwSetDebug", js.FuncOf(jwwSetDebug))
 	js.Global().Set("jwwCommitHash", js.FuncOf(jwwCommitHash))
@@ -174,6 +177,82 @@ func jwwToDxfString(this js.Value, args []js.Value) interface{} {
 	return makeResult(dxfString)
 }
 
+// jwwToDxfStringWithOptions parses JWW binary data and returns DXF file
+// content as string like jwwToDxfString, converting with the options set
+// in the options object. Its keys are ConvertOptions field names in lower
+// camel case, e.g. { pruneUnusedLayers: true, curveSegments: 32 }; keys
+// that name no option are ignored and logged in debug mode.
+// JS: jwwToDxfStringWithOptions(Uint8Array, options?: object) -> { ok: boolean, data?: string, error?: string }
+func jwwToDxfStringWithOptions(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return makeError("jwwToDxfStringWithOptions requires at least 1 argument: Uint8Array")
+	}
+
+	var optsJSON string
+	if len(args) >= 2 && !args[1].IsUndefined() && !args[1].IsNull() {
+		optsJSON = js.Global().Get("JSON").Call("stringify", args[1]).String()
+	}
+	opts, unknown, err := options.Parse(optsJSON)
+	if err != nil {
+		return makeError("invalid options: " + err.Error())
+	}
+	for _, key := range unknown {
+		logDebug("Ignoring unknown option %s", key)
+	}
+
+	logDebug("Starting DXF string generation with options")
+
+	// Get Uint8Array data
+	data := jsArrayToBytes(args[0])
+	logDebug("Received %d bytes", len(data))
+
+	// Parse JWW data
+	jwwDoc, err := jww.Parse(bytes.NewReader(data))
+	if err != nil {
+		logDebug("Parse error: %v", err.Error())
+		return makeError("parse error: " + err.Error())
+	}
+
+	logDebug("Parsed JWW document with %d entities", len(jwwDoc.Entities))
+
+	// Convert to DXF
+	dxfDoc := dxf.ConvertDocumentWithOptions(jwwDoc, opts)
+	logDebug("Converted to DXF with %d entities", len(dxfDoc.Entities))
+
+	// Convert to DXF string
+	dxfString := dxf.ToString(dxfDoc)
+	logDebug("Generated %d bytes of DXF string", len(dxfString))
+
+	return makeResult(dxfString)
+}
+
+// jwwGetLayers parses JWW binary data and returns the 256 layers as JSON,
+// with names, states, group scales, and entity counts but no entities.
+// JS: jwwGetLayers(Uint8Array) -> { ok: boolean, data?: string, error?: string }
+func jwwGetLayers(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return makeError("jwwGetLayers requires 1 argument: Uint8Array")
+	}
+
+	data := jsArrayToBytes(args[0])
+	logDebug("Received %d bytes", len(data))
+
+	doc, err := jww.Parse(bytes.NewReader(data))
+	if err != nil {
+		logDebug("Parse error: %v", err.Error())
+		return makeError("parse error: " + err.Error())
+	}
+
+	jsonData, err := json.Marshal(doc.Layers())
+	if err != nil {
+		logDebug("JSON marshal error: %v", err.Error())
+		return makeError("JSON marshal error: " + err.Error())
+	}
+
+	logDebug("Generated %d bytes of JSON", len(jsonData))
+	return makeResult(string(jsonData))
+}
+
 // jsArrayToBytes converts a JavaScript Uint8Array to Go []byte.
 func jsArrayToBytes(arr js.Value) []byte {
 	length := arr.Length()
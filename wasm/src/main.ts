@@ -14,6 +14,9 @@ declare global {
   // Set by Go WASM runtime
   var jwwToDxf: ((data: Uint8Array) => WasmResult) | undefined;
   var jwwToDxfString: ((data: Uint8Array) => WasmResult) | undefined;
+  var jwwToDxfStringWithOptions:
+    | ((data: Uint8Array, options?: Record<string, unknown>) => WasmResult)
+    | undefined;
   var jwwGetVersion: (() => string) | undefined;
 }
 